@@ -0,0 +1,295 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/checkpb"
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+const defaultAgentBatch = 200
+
+type agentClient struct {
+	addr  string
+	check func(ctx context.Context, urls []string) ([]urlcheck.Result, error)
+	close func() error
+}
+
+// agentTimeout bounds one batch round trip: every url in the batch could take
+// the full per-url timeout on the agent.
+func agentTimeout(timeout time.Duration, batch int) time.Duration {
+	if batch <= 0 {
+		batch = defaultAgentBatch
+	}
+	return timeout * time.Duration(batch)
+}
+
+func newAgentClient(addr string, timeout time.Duration, token string) (*agentClient, error) {
+	u, err := url.Parse(addr)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid agent address %q (want http://, https:// or grpc://host:port)", addr)
+	}
+	switch u.Scheme {
+	case "http", "https":
+		endpoint := strings.TrimSuffix(addr, "/") + "/check"
+		client := &http.Client{Timeout: timeout}
+		return &agentClient{
+			addr: addr,
+			check: func(ctx context.Context, urls []string) ([]urlcheck.Result, error) {
+				return httpAgentCheck(ctx, client, endpoint, token, urls)
+			},
+			close: func() error { return nil },
+		}, nil
+	case "grpc":
+		conn, err := grpc.NewClient(u.Host, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return nil, err
+		}
+		client := checkpb.NewCheckServiceClient(conn)
+		return &agentClient{
+			addr: addr,
+			check: func(ctx context.Context, urls []string) ([]urlcheck.Result, error) {
+				if timeout > 0 {
+					var cancel context.CancelFunc
+					ctx, cancel = context.WithTimeout(ctx, timeout)
+					defer cancel()
+				}
+				if token != "" {
+					ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+				}
+				resp, err := client.Check(ctx, &checkpb.CheckRequest{Urls: urls})
+				if err != nil {
+					return nil, err
+				}
+				results := make([]urlcheck.Result, 0, len(resp.Results))
+				for _, r := range resp.Results {
+					results = append(results, resultFromProto(r))
+				}
+				return results, nil
+			},
+			close: conn.Close,
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported agent scheme %q (want http, https or grpc)", u.Scheme)
+}
+
+func httpAgentCheck(ctx context.Context, client *http.Client, endpoint, token string, urls []string) ([]urlcheck.Result, error) {
+	body, err := json.Marshal(checkRequest{URLs: urls})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("agent returned %s", resp.Status)
+	}
+	var report jsonReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return nil, err
+	}
+	return report.Results, nil
+}
+
+func resultFromProto(r *checkpb.Result) urlcheck.Result {
+	return urlcheck.Result{
+		URL:        r.Url,
+		OK:         r.Ok,
+		Status:     int(r.Status),
+		Error:      r.Error,
+		ErrorKind:  urlcheck.ErrorKind(r.ErrorKind),
+		Attempts:   int(r.Attempts),
+		Duration:   time.Duration(r.DurationMs) * time.Millisecond,
+		FinalURL:   r.FinalUrl,
+		SkipReason: r.SkipReason,
+	}
+}
+
+type agentBatch struct {
+	offset int
+	urls   []string
+}
+
+// checkWithAgents shards urls across agents. Once stop closes no new batches
+// are sent, and batches still at an agent get interruptGrace to come back.
+func checkWithAgents(ctx context.Context, stop <-chan struct{}, agents []*agentClient, urls []string, size int, onResult func(urlcheck.Result)) []urlcheck.Result {
+	if size <= 0 {
+		size = defaultAgentBatch
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if stop != nil {
+		go func() {
+			select {
+			case <-stop:
+				time.AfterFunc(interruptGrace, cancel)
+			case <-ctx.Done():
+			}
+		}()
+	}
+	results := make([]urlcheck.Result, len(urls))
+	queue := make(chan agentBatch, (len(urls)+size-1)/size)
+	var pending sync.WaitGroup
+	for i := 0; i < len(urls); i += size {
+		pending.Add(1)
+		queue <- agentBatch{offset: i, urls: urls[i:min(i+size, len(urls))]}
+	}
+	var mu sync.Mutex
+	live := len(agents)
+	finish := func(b agentBatch, got []urlcheck.Result) {
+		mu.Lock()
+		for i, r := range got {
+			results[b.offset+i] = r
+			if onResult != nil {
+				onResult(r)
+			}
+		}
+		mu.Unlock()
+		pending.Done()
+	}
+	done := make(chan struct{})
+	var workers sync.WaitGroup
+	for _, a := range agents {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for {
+				select {
+				case b := <-queue:
+					if stopped(stop) {
+						finish(b, agentInterrupted(b.urls))
+						continue
+					}
+					got, err := a.check(ctx, b.urls)
+					if err == nil && len(got) != len(b.urls) {
+						err = fmt.Errorf("agent returned %d results for %d urls", len(got), len(b.urls))
+					}
+					if err == nil {
+						finish(b, got)
+						continue
+					}
+					if stopped(stop) {
+						finish(b, agentInterrupted(b.urls))
+						continue
+					}
+					slog.Warn("agent error, dropping it for the rest of the run", "agent", a.addr, "urls", len(b.urls), "error", err)
+					mu.Lock()
+					live--
+					last := live == 0
+					mu.Unlock()
+					if !last && ctx.Err() == nil {
+						queue <- b
+						return
+					}
+					finish(b, agentFailed(b.urls, err))
+					for {
+						select {
+						case b := <-queue:
+							finish(b, agentFailed(b.urls, err))
+						case <-done:
+							return
+						}
+					}
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+	pending.Wait()
+	close(done)
+	workers.Wait()
+	return results
+}
+
+func agentFailed(urls []string, err error) []urlcheck.Result {
+	results := make([]urlcheck.Result, len(urls))
+	for i, u := range urls {
+		results[i] = urlcheck.Result{URL: u, Error: "not checked (agent: " + err.Error() + ")", ErrorKind: urlcheck.KindNotAttempted}
+	}
+	return results
+}
+
+func stopped(stop <-chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func agentInterrupted(urls []string) []urlcheck.Result {
+	results := make([]urlcheck.Result, len(urls))
+	for i, u := range urls {
+		results[i] = urlcheck.Result{URL: u, SkipReason: "not attempted (interrupted)", ErrorKind: urlcheck.KindNotAttempted}
+	}
+	return results
+}
+
+// agentLocalFlags are not cache-neutral but still mean the same with -agent:
+// they pick the agents, load settings, or act on the merged results locally.
+var agentLocalFlags = map[string]bool{
+	"agent": true, "config": true, "profile": true, "seed": true,
+	"verify-failures": true, "verify-timeout": true,
+}
+
+// agentDroppedSettings lists check-shaping settings that cannot reach the
+// agents. Only the url list is sent; agents check with their own settings.
+func agentDroppedSettings(cfg config) []string {
+	var dropped []string
+	for name := range cfg.effective {
+		if !cacheNeutralFlags[name] && !agentLocalFlags[name] {
+			dropped = append(dropped, "-"+name)
+		}
+	}
+	if len(cfg.hosts) > 0 {
+		dropped = append(dropped, "hosts (from -config)")
+	}
+	if len(cfg.assertList) > 0 || len(cfg.modChecks) > 0 {
+		dropped = append(dropped, "assertions (from -config)")
+	}
+	sort.Strings(dropped)
+	return dropped
+}
+
+func openAgents(addrs []string, timeout time.Duration, token string) ([]*agentClient, func(), error) {
+	var agents []*agentClient
+	closeAll := func() {
+		for _, a := range agents {
+			a.close()
+		}
+	}
+	for _, addr := range addrs {
+		a, err := newAgentClient(addr, timeout, token)
+		if err != nil {
+			closeAll()
+			return nil, nil, err
+		}
+		agents = append(agents, a)
+	}
+	return agents, closeAll, nil
+}
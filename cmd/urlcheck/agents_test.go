@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func newAgentTarget(t *testing.T) string {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(target.Close)
+	return target.URL
+}
+
+func newHTTPAgent(t *testing.T, requests *atomic.Int32) string {
+	api := apiHandler(context.Background(), urlcheck.NewChecker(2, time.Second, 0, nil), newJobStore(), 2)
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		api.ServeHTTP(w, r)
+	}))
+	t.Cleanup(agent.Close)
+	return agent.URL
+}
+
+func TestCheckWithAgentsShardsAndMerges(t *testing.T) {
+	target := newAgentTarget(t)
+	var first, second atomic.Int32
+	agents, closeAgents, err := openAgents([]string{newHTTPAgent(t, &first), newHTTPAgent(t, &second)}, time.Minute, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeAgents()
+	urls := []string{target + "/a", target + "/broken", target + "/c", target + "/d", target + "/e"}
+	var seen atomic.Int32
+	results := checkWithAgents(context.Background(), nil, agents, urls, 2, func(urlcheck.Result) { seen.Add(1) })
+	if len(results) != len(urls) || seen.Load() != int32(len(urls)) {
+		t.Fatalf("expected %d results and callbacks, got %d and %d", len(urls), len(results), seen.Load())
+	}
+	for i, r := range results {
+		if r.URL != urls[i] {
+			t.Fatalf("result %d out of order: %s", i, r.URL)
+		}
+		if r.OK == (r.URL == target+"/broken") {
+			t.Fatalf("unexpected result: %+v", r)
+		}
+	}
+	if first.Load()+second.Load() != 3 {
+		t.Fatalf("expected 3 batches, got %d", first.Load()+second.Load())
+	}
+}
+
+func TestCheckWithAgentsRequeuesFailedBatches(t *testing.T) {
+	target := newAgentTarget(t)
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	var requests atomic.Int32
+	agents, closeAgents, err := openAgents([]string{down.URL, newHTTPAgent(t, &requests)}, time.Minute, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeAgents()
+	urls := []string{target + "/a", target + "/b", target + "/c"}
+	for _, r := range checkWithAgents(context.Background(), nil, agents, urls, 1, nil) {
+		if !r.OK {
+			t.Fatalf("batch was not retried on the healthy agent: %+v", r)
+		}
+	}
+}
+
+func TestCheckWithAgentsAllDown(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	agents, closeAgents, err := openAgents([]string{down.URL}, time.Minute, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeAgents()
+	results := checkWithAgents(context.Background(), nil, agents, []string{"https://a.example"}, 0, nil)
+	if len(results) != 1 || results[0].ErrorKind != urlcheck.KindNotAttempted || results[0].URL != "https://a.example" {
+		t.Fatalf("unexpected result: %+v", results)
+	}
+}
+
+func TestCheckWithGRPCAgent(t *testing.T) {
+	target := newAgentTarget(t)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := newGRPCServer(&checkService{
+		newChecker: func(opts ...urlcheck.Option) *urlcheck.Checker {
+			return urlcheck.NewChecker(2, time.Second, 0, nil, opts...)
+		},
+		maxURLs: 10,
+	})
+	go srv.Serve(lis)
+	defer srv.Stop()
+	agents, closeAgents, err := openAgents([]string{"grpc://" + lis.Addr().String()}, time.Minute, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeAgents()
+	results := checkWithAgents(context.Background(), nil, agents, []string{target + "/ok", target + "/broken"}, 0, nil)
+	if !results[0].OK || results[1].OK || results[1].Status != http.StatusNotFound {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+}
+
+func TestNewAgentClientRejectsBadAddress(t *testing.T) {
+	for _, addr := range []string{"localhost:8080", "ftp://host", "http://"} {
+		if _, err := newAgentClient(addr, 0, ""); err == nil {
+			t.Fatalf("expected error for %q", addr)
+		}
+	}
+}
+
+func TestCheckWithAgentsTimesOutHungAgent(t *testing.T) {
+	release := make(chan struct{})
+	hung := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { <-release }))
+	defer hung.Close()
+	defer close(release)
+	agents, closeAgents, err := openAgents([]string{hung.URL}, 100*time.Millisecond, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeAgents()
+	results := checkWithAgents(context.Background(), nil, agents, []string{"https://a.example"}, 0, nil)
+	if results[0].ErrorKind != urlcheck.KindNotAttempted || results[0].Error == "" {
+		t.Fatalf("expected the hung agent to time out, got %+v", results[0])
+	}
+}
+
+func TestCheckWithAgentsStopsOnInterrupt(t *testing.T) {
+	var requests atomic.Int32
+	agents, closeAgents, err := openAgents([]string{newHTTPAgent(t, &requests)}, time.Minute, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeAgents()
+	stop := make(chan struct{})
+	close(stop)
+	results := checkWithAgents(context.Background(), stop, agents, []string{"https://a.example", "https://b.example"}, 1, nil)
+	if requests.Load() != 0 {
+		t.Fatalf("no batches should be sent after an interrupt, got %d", requests.Load())
+	}
+	for _, r := range results {
+		if r.SkipReason == "" || r.ErrorKind != urlcheck.KindNotAttempted {
+			t.Fatalf("unexpected result: %+v", r)
+		}
+	}
+}
+
+func TestHTTPAgentSendsToken(t *testing.T) {
+	target := newAgentTarget(t)
+	api := requireToken("s3cret", apiHandler(context.Background(), urlcheck.NewChecker(1, time.Second, 0, nil), newJobStore(), 2))
+	agent := httptest.NewServer(api)
+	defer agent.Close()
+	for token, wantOK := range map[string]bool{"s3cret": true, "": false} {
+		agents, closeAgents, err := openAgents([]string{agent.URL}, time.Minute, token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results := checkWithAgents(context.Background(), nil, agents, []string{target + "/ok"}, 0, nil)
+		closeAgents()
+		if results[0].OK != wantOK {
+			t.Fatalf("token %q: unexpected result %+v", token, results[0])
+		}
+	}
+}
+
+func TestAgentDroppedSettings(t *testing.T) {
+	cfg := config{effective: map[string]string{"agent": "http://a", "concurrency": "4", "output": "json", "seed": "1"}}
+	if dropped := agentDroppedSettings(cfg); len(dropped) != 0 {
+		t.Fatalf("run-level flags should be allowed with -agent, got %v", dropped)
+	}
+	cfg.effective["method"] = "HEAD"
+	cfg.effective["timeout"] = "3s"
+	cfg.hosts = []urlcheck.HostOverride{{Host: "a.example"}}
+	want := []string{"-method", "-timeout", "hosts (from -config)"}
+	if dropped := agentDroppedSettings(cfg); !slices.Equal(dropped, want) {
+		t.Fatalf("got %v, want %v", dropped, want)
+	}
+}
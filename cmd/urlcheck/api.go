@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+	"google.golang.org/grpc"
+)
+
+const maxStoredJobs = 1000
+
+var errTooManyJobs = errors.New("too many jobs in flight")
+
+type checkRequest struct {
+	URLs    []string      `json:"urls"`
+	Targets []checkTarget `json:"targets"`
+	Async   bool          `json:"async"`
+}
+
+type checkTarget struct {
+	URL    string            `json:"url"`
+	Labels map[string]string `json:"labels"`
+}
+
+func (req checkRequest) count() int {
+	return len(req.URLs) + len(req.Targets)
+}
+
+func (req checkRequest) source() urlcheck.Source {
+	rows := make([]inputRow, 0, req.count())
+	for _, u := range req.URLs {
+		rows = append(rows, inputRow{url: u})
+	}
+	for _, t := range req.Targets {
+		rows = append(rows, inputRow{url: t.URL, labels: t.Labels})
+	}
+	return rowSource("", rows)
+}
+
+type job struct {
+	ID       string            `json:"id"`
+	Status   string            `json:"status"`
+	Started  time.Time         `json:"started"`
+	Finished *time.Time        `json:"finished,omitempty"`
+	Error    string            `json:"error,omitempty"`
+	Summary  *urlcheck.Summary `json:"summary,omitempty"`
+	Results  []urlcheck.Result `json:"results,omitempty"`
+}
+
+type jobStore struct {
+	mu      sync.Mutex
+	jobs    map[string]*job
+	order   []string
+	running int
+	// maxRunning caps async jobs in flight; 0 means unlimited.
+	maxRunning int
+}
+
+func newJobStore() *jobStore {
+	return &jobStore{jobs: make(map[string]*job)}
+}
+
+func (s *jobStore) start() (*job, error) {
+	var b [8]byte
+	rand.Read(b[:])
+	j := &job{ID: hex.EncodeToString(b[:]), Status: "running", Started: time.Now()}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.maxRunning > 0 && s.running >= s.maxRunning {
+		return nil, errTooManyJobs
+	}
+	s.running++
+	s.jobs[j.ID] = j
+	s.order = append(s.order, j.ID)
+	if len(s.order) > maxStoredJobs {
+		delete(s.jobs, s.order[0])
+		s.order = s.order[1:]
+	}
+	return j, nil
+}
+
+func (s *jobStore) finish(id string, results []urlcheck.Result, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running--
+	j, ok := s.jobs[id]
+	if !ok {
+		return
+	}
+	now := time.Now()
+	j.Finished = &now
+	if err != nil {
+		j.Status, j.Error = "failed", err.Error()
+		return
+	}
+	summary := urlcheck.Summarize(results, now.Sub(j.Started))
+	j.Status, j.Summary, j.Results = "done", &summary, results
+}
+
+func (s *jobStore) get(id string) (job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return job{}, false
+	}
+	return *j, true
+}
+
+func apiHandler(ctx context.Context, checker *urlcheck.Checker, jobs *jobStore, maxURLs int) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSONResponse(w, map[string]string{"status": "ok"})
+	})
+	mux.HandleFunc("POST /check", func(w http.ResponseWriter, r *http.Request) {
+		var req checkRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.count() == 0 {
+			http.Error(w, "no urls", http.StatusBadRequest)
+			return
+		}
+		if req.count() > maxURLs {
+			http.Error(w, fmt.Sprintf("too many urls: %d > %d", req.count(), maxURLs), http.StatusRequestEntityTooLarge)
+			return
+		}
+		if req.Async {
+			j, err := jobs.start()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusTooManyRequests)
+				return
+			}
+			go func() {
+				results, err := checker.CheckSource(ctx, req.source())
+				jobs.finish(j.ID, results, err)
+			}()
+			w.Header().Set("Location", "/jobs/"+j.ID)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(j)
+			return
+		}
+		started := time.Now()
+		results, err := checker.CheckSource(r.Context(), req.source())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSONResponse(w, jsonReport{Summary: urlcheck.Summarize(results, time.Since(started)), Results: results})
+	})
+	mux.HandleFunc("GET /jobs/{id}", func(w http.ResponseWriter, r *http.Request) {
+		j, ok := jobs.get(r.PathValue("id"))
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSONResponse(w, j)
+	})
+	return mux
+}
+
+// requireToken rejects requests without "Authorization: Bearer <token>",
+// leaving /healthz open for load balancers. An empty token disables the check.
+func requireToken(token string, next http.Handler) http.Handler {
+	if token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" && !validToken(token, r.Header.Get("Authorization")) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func validToken(token, header string) bool {
+	got, ok := strings.CutPrefix(header, "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
+
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", "127.0.0.1:8080", "listen address; anything beyond loopback should also set -token")
+	concurrency := fs.Int("concurrency", 5, "maximum concurrent checks per request")
+	timeout := fs.Duration("timeout", 5*time.Second, "per-request timeout")
+	retries := fs.Int("retries", 1, "retries on network errors")
+	maxURLs := fs.Int("max-urls", 1000, "maximum urls accepted per request")
+	maxJobs := fs.Int("max-jobs", 10, "maximum async jobs in flight; further async requests get 429")
+	grpcAddr := fs.String("grpc", "", "also serve the grpc CheckService on this address (e.g. 127.0.0.1:9090)")
+	token := fs.String("token", os.Getenv("URLCHECK_API_TOKEN"), "require this bearer token on api and grpc requests (default $URLCHECK_API_TOKEN)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *concurrency <= 0 || *maxURLs <= 0 || *maxJobs <= 0 {
+		return fmt.Errorf("-concurrency, -max-urls and -max-jobs must be positive")
+	}
+	for _, a := range []string{*addr, *grpcAddr} {
+		if a != "" && *token == "" && !isLoopbackAddr(a) {
+			slog.Warn("listening beyond loopback without -token; anyone who can reach it can make this host fetch arbitrary urls", "addr", a)
+		}
+	}
+	ctx, cancelJobs := context.WithCancel(context.Background())
+	defer cancelJobs()
+	newChecker := func(opts ...urlcheck.Option) *urlcheck.Checker {
+		return urlcheck.NewChecker(*concurrency, *timeout, *retries, nil, opts...)
+	}
+	errs := make(chan error, 2)
+	var grpcServer *grpc.Server
+	if *grpcAddr != "" {
+		lis, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			return err
+		}
+		slog.Info("serving grpc", "addr", *grpcAddr)
+		grpcServer = newGRPCServer(&checkService{newChecker: newChecker, maxURLs: *maxURLs}, tokenInterceptors(*token)...)
+		go func() {
+			errs <- grpcServer.Serve(lis)
+		}()
+	}
+	lis, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	slog.Info("serving api", "addr", *addr)
+	jobs := newJobStore()
+	jobs.maxRunning = *maxJobs
+	srv := &http.Server{Handler: requireToken(*token, apiHandler(ctx, newChecker(), jobs, *maxURLs))}
+	go func() {
+		errs <- srv.Serve(lis)
+	}()
+	stop := shutdownOnSignal()
+	notifySystemd("READY=1")
+	startWatchdog(stop, nil)
+	select {
+	case err := <-errs:
+		return err
+	case <-stop:
+	}
+	cancelJobs()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), interruptGrace)
+	defer cancel()
+	if grpcServer != nil {
+		go func() {
+			<-shutdownCtx.Done()
+			grpcServer.Stop()
+		}()
+		grpcServer.GracefulStop()
+	}
+	return srv.Shutdown(shutdownCtx)
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func newTestAPI(t *testing.T) (*httptest.Server, string) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(target.Close)
+	checker := urlcheck.NewChecker(2, time.Second, 0, nil)
+	api := httptest.NewServer(apiHandler(context.Background(), checker, newJobStore(), 2))
+	t.Cleanup(api.Close)
+	return api, target.URL
+}
+
+func TestAPICheckSync(t *testing.T) {
+	api, target := newTestAPI(t)
+	resp, err := http.Post(api.URL+"/check", "application/json", strings.NewReader(`{"urls":["`+target+`/ok","`+target+`/broken"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var report jsonReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || report.Summary.Total != 2 || report.Summary.Broken != 1 {
+		t.Fatalf("unexpected response %d: %+v", resp.StatusCode, report.Summary)
+	}
+}
+
+func TestAPICheckTargetLabels(t *testing.T) {
+	api, target := newTestAPI(t)
+	body := `{"urls":["` + target + `/ok"],"targets":[{"url":"` + target + `/broken","labels":{"service":"billing"}}]}`
+	resp, err := http.Post(api.URL+"/check", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var report jsonReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Results) != 2 || report.Results[0].Labels != nil || report.Results[1].Labels["service"] != "billing" {
+		t.Fatalf("labels not carried into results: %+v", report.Results)
+	}
+}
+
+func TestAPICheckAsyncJob(t *testing.T) {
+	api, target := newTestAPI(t)
+	resp, err := http.Post(api.URL+"/check", "application/json", strings.NewReader(`{"urls":["`+target+`/ok"],"async":true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var j job
+	json.NewDecoder(resp.Body).Decode(&j)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted || j.ID == "" || resp.Header.Get("Location") != "/jobs/"+j.ID {
+		t.Fatalf("unexpected async response %d: %+v", resp.StatusCode, j)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for j.Status == "running" && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		resp, err := http.Get(api.URL + "/jobs/" + j.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		json.NewDecoder(resp.Body).Decode(&j)
+		resp.Body.Close()
+	}
+	if j.Status != "done" || len(j.Results) != 1 || !j.Results[0].OK || j.Summary.OK != 1 {
+		t.Fatalf("unexpected job: %+v", j)
+	}
+}
+
+func TestAPIRejectsBadRequests(t *testing.T) {
+	api, _ := newTestAPI(t)
+	cases := map[string]int{
+		`{"urls":[]}`:            http.StatusBadRequest,
+		`not json`:               http.StatusBadRequest,
+		`{"urls":["a","b","c"]}`: http.StatusRequestEntityTooLarge,
+	}
+	for body, want := range cases {
+		resp, err := http.Post(api.URL+"/check", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("%s: got %d, want %d", body, resp.StatusCode, want)
+		}
+	}
+	for path, want := range map[string]int{"/healthz": http.StatusOK, "/jobs/missing": http.StatusNotFound} {
+		resp, err := http.Get(api.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("%s: got %d, want %d", path, resp.StatusCode, want)
+		}
+	}
+}
+
+func TestAPIRequiresToken(t *testing.T) {
+	checker := urlcheck.NewChecker(1, time.Second, 0, nil)
+	api := httptest.NewServer(requireToken("s3cret", apiHandler(context.Background(), checker, newJobStore(), 2)))
+	defer api.Close()
+	for auth, want := range map[string]int{"": http.StatusUnauthorized, "Bearer nope": http.StatusUnauthorized, "Bearer s3cret": http.StatusBadRequest} {
+		req, _ := http.NewRequest(http.MethodPost, api.URL+"/check", strings.NewReader(`{"urls":[]}`))
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("%q: got %d, want %d", auth, resp.StatusCode, want)
+		}
+	}
+	resp, err := http.Get(api.URL + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("healthz should stay open, got %d", resp.StatusCode)
+	}
+}
+
+func TestAPICapsAsyncJobs(t *testing.T) {
+	release := make(chan struct{})
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { <-release }))
+	defer target.Close()
+	defer close(release)
+	jobs := newJobStore()
+	jobs.maxRunning = 1
+	api := httptest.NewServer(apiHandler(context.Background(), urlcheck.NewChecker(1, 5*time.Second, 0, nil), jobs, 2))
+	defer api.Close()
+	body := `{"urls":["` + target.URL + `"],"async":true}`
+	for _, want := range []int{http.StatusAccepted, http.StatusTooManyRequests} {
+		resp, err := http.Post(api.URL+"/check", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Fatalf("got %d, want %d", resp.StatusCode, want)
+		}
+	}
+}
+
+func TestIsLoopbackAddr(t *testing.T) {
+	for addr, want := range map[string]bool{"127.0.0.1:8080": true, "localhost:1": true, "[::1]:80": true, ":8080": false, "0.0.0.0:8080": false} {
+		if got := isLoopbackAddr(addr); got != want {
+			t.Errorf("%s: got %v, want %v", addr, got, want)
+		}
+	}
+}
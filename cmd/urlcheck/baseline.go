@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+type baseline struct {
+	path     string
+	expected map[string]int
+	update   bool
+}
+
+func loadBaseline(path string, update bool) (*baseline, error) {
+	b := &baseline{path: path, expected: map[string]int{}, update: update}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) && update {
+		return b, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &b.expected); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (b *baseline) Process(_ context.Context, results []urlcheck.Result) ([]urlcheck.Result, error) {
+	var deviations []urlcheck.Result
+	observed := make(map[string]int)
+	for _, r := range results {
+		if r.SkipReason != "" {
+			deviations = append(deviations, r)
+			continue
+		}
+		observed[r.URL] = r.Status
+		want, known := b.expected[r.URL]
+		if known && want == r.Status {
+			continue
+		}
+		if known {
+			r.BaselineStatus = want
+		}
+		deviations = append(deviations, r)
+	}
+	if b.update {
+		data, err := json.MarshalIndent(observed, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(b.path, append(data, '\n'), 0o644); err != nil {
+			return nil, err
+		}
+	}
+	return deviations, nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestBaselineReportsOnlyDeviations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := os.WriteFile(path, []byte(`{"https://moved.example": 301, "https://ok.example": 200, "https://fixed.example": 404}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	b, err := loadBaseline(path, true)
+	if err != nil {
+		t.Fatalf("loadBaseline: %v", err)
+	}
+	results := []urlcheck.Result{
+		{URL: "https://moved.example", Status: 301, OK: true},
+		{URL: "https://ok.example", Status: 500},
+		{URL: "https://fixed.example", Status: 200, OK: true},
+		{URL: "https://new.example", Status: 200, OK: true},
+	}
+	got, err := b.Process(context.Background(), results)
+	if err != nil {
+		t.Fatalf("process: %v", err)
+	}
+	if len(got) != 3 || got[0].URL != "https://ok.example" || got[0].BaselineStatus != 200 || got[2].BaselineStatus != 0 {
+		t.Fatalf("unexpected deviations: %+v", got)
+	}
+	updated, err := loadBaseline(path, false)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if updated.expected["https://ok.example"] != 500 || updated.expected["https://new.example"] != 200 {
+		t.Fatalf("baseline not updated: %v", updated.expected)
+	}
+}
+
+func TestLoadBaselineMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.json")
+	if _, err := loadBaseline(path, false); err == nil {
+		t.Fatal("expected error for missing baseline")
+	}
+	if b, err := loadBaseline(path, true); err != nil || len(b.expected) != 0 {
+		t.Fatalf("expected empty baseline when updating, got %v (%v)", b, err)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"text/tabwriter"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func runBench(cfg config, stdin io.Reader, out io.Writer) error {
+	urls, _, err := loadInputs(cfg, stdin)
+	if err != nil {
+		return err
+	}
+	filter, err := newURLFilter(cfg)
+	if err != nil {
+		return err
+	}
+	urls, _ = filter.apply(urls)
+	if len(urls) == 0 {
+		return errors.New("no urls provided")
+	}
+	opts, closeOptions, err := checkerOptions(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeOptions()
+	opts = append(opts, urlcheck.WithLogger(slog.Default()))
+	checker := urlcheck.NewChecker(cfg.concurrency, cfg.timeout, cfg.retries, nil, opts...)
+	results, err := checker.Bench(context.Background(), urls, cfg.benchRuns)
+	if err != nil {
+		return err
+	}
+	if cfg.format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+	return writeBench(out, results)
+}
+
+func writeBench(out io.Writer, results []urlcheck.BenchResult) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "URL\tREQUESTS\tERRORS\tMIN\tP50\tP95\tP99\tMAX")
+	for _, r := range results {
+		l := r.Latency
+		fmt.Fprintf(w, "%s\t%d\t%d (%.1f%%)\t%s\t%s\t%s\t%s\t%s\n", r.URL, r.Requests, r.Errors, r.ErrorRate*100,
+			l.Min.Round(time.Millisecond), l.P50.Round(time.Millisecond), l.P95.Round(time.Millisecond),
+			l.P99.Round(time.Millisecond), l.Max.Round(time.Millisecond))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestWriteBench(t *testing.T) {
+	results := []urlcheck.BenchResult{{
+		URL: "https://a.example", Requests: 10, Errors: 1, ErrorRate: 0.1,
+		Latency: urlcheck.Latency{Min: time.Millisecond, P50: 12 * time.Millisecond, P95: 30 * time.Millisecond, P99: 41 * time.Millisecond, Max: 41 * time.Millisecond},
+	}}
+	var buf bytes.Buffer
+	if err := writeBench(&buf, results); err != nil {
+		t.Fatalf("writeBench: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "URL") || strings.Join(strings.Fields(lines[1]), " ") != "https://a.example 10 1 (10.0%) 1ms 12ms 30ms 41ms 41ms" {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+}
+
+func TestRunBenchJSON(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer server.Close()
+	cfg := parseFlags("bench", []string{"-runs", "3", "-concurrency", "1", "-format", "json", server.URL})
+	var buf bytes.Buffer
+	if err := runBench(cfg, strings.NewReader(""), &buf); err != nil {
+		t.Fatalf("runBench: %v", err)
+	}
+	var results []urlcheck.BenchResult
+	if err := json.Unmarshal(buf.Bytes(), &results); err != nil {
+		t.Fatalf("parse json: %v", err)
+	}
+	if hits != 3 || len(results) != 1 || results[0].Requests != 3 || results[0].Errors != 0 {
+		t.Fatalf("unexpected results %+v after %d hits", results, hits)
+	}
+}
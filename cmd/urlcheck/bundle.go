@@ -0,0 +1,151 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+type bundleFile struct {
+	Name   string `json:"name"`
+	Source string `json:"source,omitempty"`
+	Size   int    `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+type bundleManifest struct {
+	Started  time.Time    `json:"started"`
+	Finished time.Time    `json:"finished"`
+	Args     []string     `json:"args"`
+	Total    int          `json:"total"`
+	Failed   int          `json:"failed"`
+	Files    []bundleFile `json:"files"`
+}
+
+type bundleSource struct {
+	dir  string
+	path string
+}
+
+func bundleSources(cfg config) []bundleSource {
+	var sources []bundleSource
+	for _, path := range splitFiles(cfg.outDir) {
+		sources = append(sources, bundleSource{"out", path})
+	}
+	for _, path := range reportFiles(cfg.outputs) {
+		sources = append(sources, bundleSource{"reports", path})
+	}
+	if cfg.har != "" {
+		sources = append(sources, bundleSource{"archives", cfg.har})
+	}
+	return sources
+}
+
+func writeBundle(path string, manifest bundleManifest, results []urlcheck.Result, sources []bundleSource) error {
+	type entry struct {
+		name string
+		data []byte
+	}
+	var entries []entry
+	used := make(map[string]bool)
+	add := func(name, source string, data []byte) {
+		base := name
+		for n := 2; used[name]; n++ {
+			ext := filepath.Ext(base)
+			name = strings.TrimSuffix(base, ext) + "-" + strconv.Itoa(n) + ext
+		}
+		used[name] = true
+		sum := sha256.Sum256(data)
+		manifest.Files = append(manifest.Files, bundleFile{Name: name, Source: source, Size: len(data), SHA256: hex.EncodeToString(sum[:])})
+		entries = append(entries, entry{name, data})
+	}
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	add("results.json", "", data)
+	for _, src := range sources {
+		data, err := os.ReadFile(src.path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		add(src.dir+"/"+filepath.Base(src.path), src.path, data)
+	}
+	for _, r := range results {
+		if failed(r) {
+			manifest.Failed++
+		}
+	}
+	manifest.Total = len(results)
+	data, err = json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	entries = append([]entry{{"manifest.json", data}}, entries...)
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if strings.HasSuffix(path, ".zip") {
+		zw := zip.NewWriter(f)
+		for _, e := range entries {
+			w, err := zw.CreateHeader(&zip.FileHeader{Name: e.name, Method: zip.Deflate, Modified: manifest.Finished})
+			if err == nil {
+				_, err = w.Write(e.data)
+			}
+			if err != nil {
+				f.Close()
+				return err
+			}
+		}
+		if err := zw.Close(); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+	var w io.Writer = f
+	var gz *gzip.Writer
+	if !strings.HasSuffix(path, ".tar") {
+		gz = gzip.NewWriter(f)
+		w = gz
+	}
+	tw := tar.NewWriter(w)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Mode: 0o644, Size: int64(len(e.data)), ModTime: manifest.Finished}
+		if err := tw.WriteHeader(hdr); err != nil {
+			f.Close()
+			return err
+		}
+		if _, err := tw.Write(e.data); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		f.Close()
+		return err
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
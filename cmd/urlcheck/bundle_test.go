@@ -0,0 +1,74 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestWriteBundleLayout(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a", "report.json")
+	b := filepath.Join(dir, "b", "report.json")
+	for _, p := range []string{a, b} {
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	results := []urlcheck.Result{{URL: "https://a.example", OK: true}, {URL: "https://b.example"}}
+	manifest := bundleManifest{Started: time.Unix(0, 0), Finished: time.Unix(10, 0), Args: []string{"-bundle", "x"}}
+	sources := []bundleSource{{"reports", a}, {"reports", b}, {"archives", filepath.Join(dir, "missing.har")}}
+	path := filepath.Join(dir, "run.tar.gz")
+	if err := writeBundle(path, manifest, results, sources); err != nil {
+		t.Fatalf("writeBundle: %v", err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	var names []string
+	var got bundleManifest
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, hdr.Name)
+		if hdr.Name == "manifest.json" {
+			if err := json.NewDecoder(tr).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	want := []string{"manifest.json", "results.json", "reports/report.json", "reports/report-2.json"}
+	if len(names) != len(want) {
+		t.Fatalf("unexpected entries: %v", names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("unexpected entries: %v", names)
+		}
+	}
+	if got.Total != 2 || got.Failed != 1 || len(got.Files) != 3 || got.Files[2].Source != b {
+		t.Fatalf("unexpected manifest: %+v", got)
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+type resultCache struct {
+	dir string
+	ttl time.Duration
+	// fingerprint identifies the check configuration; a url that passed
+	// under one configuration is not reused under another.
+	fingerprint string
+}
+
+type cacheEntry struct {
+	CheckedAt time.Time       `json:"checked_at"`
+	Result    urlcheck.Result `json:"result"`
+}
+
+func (c resultCache) path(u string) string {
+	sum := sha256.Sum256([]byte(c.fingerprint + "\n" + u))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c resultCache) load(urls []string, now time.Time) map[string]urlcheck.Result {
+	fresh := map[string]urlcheck.Result{}
+	for _, u := range urls {
+		data, err := os.ReadFile(c.path(u))
+		if err != nil {
+			continue
+		}
+		var e cacheEntry
+		if json.Unmarshal(data, &e) != nil || e.Result.URL != u || !e.Result.OK || now.Sub(e.CheckedAt) >= c.ttl {
+			continue
+		}
+		e.Result.Cached = true
+		fresh[u] = e.Result
+	}
+	return fresh
+}
+
+func (c resultCache) store(results []urlcheck.Result, now time.Time) error {
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+	for _, r := range results {
+		if r.Cached || r.ErrorKind == urlcheck.KindNotAttempted {
+			continue
+		}
+		path := c.path(r.URL)
+		if !r.OK {
+			if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+			continue
+		}
+		data, err := json.Marshal(cacheEntry{CheckedAt: now, Result: r})
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path+".tmp", data, 0o644); err != nil {
+			return err
+		}
+		if err := os.Rename(path+".tmp", path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cacheNeutralFlags only affect reporting, notification or how the run is
+// driven, never the outcome of a single check, so they stay out of the cache
+// fingerprint. Every other flag, including ones added later, invalidates it.
+var cacheNeutralFlags = map[string]bool{
+	"file": true, "scan": true, "fix": true, "fix-dry-run": true, "runs": true, "sitemap": true,
+	"include-domain": true, "exclude-domain": true, "match": true, "exclude": true, "shard": true,
+	"sample": true, "sample-n": true, "sample-per-host": true, "concurrency": true,
+	"json": true, "format": true, "output": true, "report": true, "har": true, "save-failures": true,
+	"template": true, "color": true, "only-failures": true, "print": true, "group-by": true,
+	"stats": true, "emit-curl": true, "sort": true, "out-dir": true, "no-split": true, "o": true,
+	"bundle": true, "serve": true, "history": true, "no-progress": true, "post-process": true,
+	"dry-run": true, "quiet": true, "progress-format": true, "checkpoint-every": true,
+	"checkpoint-interval": true, "checkpoint": true, "pprof": true, "debug-stats": true,
+	"priority": true, "resume": true, "cache": true, "cache-ttl": true, "no-cache": true,
+	"stream": true, "follow": true, "chdir": true, "agent-batch": true, "agent-token": true, "exit-zero": true, "max-failures": true,
+	"max-failure-rate": true, "baseline": true, "update-baseline": true, "diff": true, "state": true,
+	"webhook": true, "webhook-template": true, "slack-webhook": true, "slack-token": true,
+	"slack-channel": true, "report-url": true, "smtp": true, "smtp-user": true, "email-from": true,
+	"email-to": true, "email-attach": true, "interval": true, "schedule": true, "v": true,
+	"log-level": true, "log-format": true, "otlp-endpoint": true, "schema": true, "watch": true,
+}
+
+// cacheFingerprint hashes the check-affecting flags that were set along with
+// the contents of files they point at (config, assertions, request body).
+func cacheFingerprint(fs *flag.FlagSet, files ...string) string {
+	var set []string
+	fs.Visit(func(f *flag.Flag) {
+		if !cacheNeutralFlags[f.Name] {
+			set = append(set, f.Name+"="+f.Value.String())
+		}
+	})
+	sort.Strings(set)
+	h := sha256.New()
+	for _, kv := range set {
+		h.Write([]byte(kv + "\n"))
+	}
+	for _, name := range files {
+		if name == "" {
+			continue
+		}
+		data, _ := os.ReadFile(name)
+		sum := sha256.Sum256(data)
+		h.Write([]byte(name + "=" + hex.EncodeToString(sum[:]) + "\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
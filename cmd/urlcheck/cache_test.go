@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestResultCache(t *testing.T) {
+	cache := resultCache{dir: t.TempDir(), ttl: time.Hour}
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	err := cache.store([]urlcheck.Result{
+		{URL: "https://a.example", OK: true, Status: 200},
+		{URL: "https://b.example", Status: 500, Error: "status 500"},
+		{URL: "https://c.example", OK: true, Status: 200},
+	}, now)
+	if err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	urls := []string{"https://a.example", "https://b.example", "https://c.example", "https://d.example"}
+	fresh := cache.load(urls, now.Add(30*time.Minute))
+	if len(fresh) != 2 || !fresh["https://a.example"].Cached || fresh["https://a.example"].Status != 200 {
+		t.Fatalf("unexpected cached results: %+v", fresh)
+	}
+	if pending := pendingURLs(urls, fresh); len(pending) != 2 || pending[0] != "https://b.example" {
+		t.Fatalf("unexpected pending urls: %v", pending)
+	}
+	if stale := cache.load(urls, now.Add(2*time.Hour)); len(stale) != 0 {
+		t.Fatalf("expected entries to expire, got %+v", stale)
+	}
+
+	later := now.Add(90 * time.Minute)
+	err = cache.store([]urlcheck.Result{
+		fresh["https://a.example"],
+		{URL: "https://c.example", Status: 404, Error: "status 404"},
+	}, later)
+	if err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	if _, err := os.Stat(cache.path("https://c.example")); !os.IsNotExist(err) {
+		t.Fatalf("expected failed url to be evicted, got %v", err)
+	}
+	if got := cache.load(urls, now.Add(61*time.Minute)); len(got) != 0 {
+		t.Fatalf("a result served from the cache must not refresh its age, got %+v", got)
+	}
+}
+
+func TestResultCacheKeyedByCheckConfig(t *testing.T) {
+	fingerprint := func(args ...string) string {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("method", "GET", "")
+		fs.String("forbid", "", "")
+		fs.String("format", "table", "")
+		if err := fs.Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		return cacheFingerprint(fs)
+	}
+	base := fingerprint()
+	if fingerprint("-format", "json") != base {
+		t.Fatal("output flags must not change the fingerprint")
+	}
+	if fingerprint("-method", "HEAD") == base || fingerprint("-forbid", "login") == base {
+		t.Fatal("check flags must change the fingerprint")
+	}
+	dir := t.TempDir()
+	now := time.Now()
+	loose := resultCache{dir: dir, ttl: time.Hour, fingerprint: base}
+	if err := loose.store([]urlcheck.Result{{URL: "https://a.example", OK: true}}, now); err != nil {
+		t.Fatal(err)
+	}
+	strict := resultCache{dir: dir, ttl: time.Hour, fingerprint: fingerprint("-forbid", "login")}
+	if got := strict.load([]string{"https://a.example"}, now); len(got) != 0 {
+		t.Fatalf("result cached under another configuration was reused: %+v", got)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func loadPairs(path string) ([]urlcheck.Pair, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var pairs []urlcheck.Pair
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: want \"candidate reference\", got %q", path, line, text)
+		}
+		pairs = append(pairs, urlcheck.Pair{Candidate: fields[0], Reference: fields[1]})
+	}
+	return pairs, scanner.Err()
+}
+
+func runCanary(cfg config) (int, error) {
+	pairs, err := loadPairs(cfg.canary)
+	if err != nil {
+		return exitToolError, err
+	}
+	opts, closeOptions, err := checkerOptions(cfg)
+	if err != nil {
+		return exitToolError, err
+	}
+	defer closeOptions()
+	opts = append(opts, urlcheck.WithLogger(slog.Default()))
+	headers := cfg.compareHdr
+	if len(headers) == 0 {
+		headers = []string{"Content-Type"}
+	}
+	checker := urlcheck.NewChecker(cfg.concurrency, cfg.timeout, cfg.retries, nil, opts...)
+	comps := checker.Compare(context.Background(), pairs, headers, cfg.minSimilar)
+	if cfg.format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(comps)
+	} else {
+		err = writeComparisons(os.Stdout, comps)
+	}
+	if err != nil {
+		return exitToolError, err
+	}
+	if !cfg.exitZero {
+		for _, c := range comps {
+			if len(c.Drift) > 0 {
+				return exitFailures, nil
+			}
+		}
+	}
+	return exitOK, nil
+}
+
+func writeComparisons(out io.Writer, comps []urlcheck.Comparison) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CANDIDATE\tREFERENCE\tSTATUS\tSIMILARITY\tDRIFT")
+	for _, c := range comps {
+		drift := "-"
+		if len(c.Drift) > 0 {
+			drift = strings.Join(c.Drift, "; ")
+		}
+		if c.Error != "" {
+			drift += " (" + c.Error + ")"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d/%d\t%.2f\t%s\n", c.Candidate, c.Reference, c.CandidateStatus, c.ReferenceStatus, c.Similarity, drift)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestLoadPairs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pairs.txt")
+	data := "# blue/green\nhttps://green.example/ https://blue.example/\n\nhttps://new.example/a  https://old.example/a\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pairs, err := loadPairs(path)
+	if err != nil {
+		t.Fatalf("loadPairs: %v", err)
+	}
+	if len(pairs) != 2 || pairs[1].Candidate != "https://new.example/a" || pairs[1].Reference != "https://old.example/a" {
+		t.Fatalf("unexpected pairs: %+v", pairs)
+	}
+	if err := os.WriteFile(path, []byte("https://only.example/\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadPairs(path); err == nil || !strings.Contains(err.Error(), ":1:") {
+		t.Fatalf("expected line error, got %v", err)
+	}
+}
+
+func TestWriteComparisons(t *testing.T) {
+	comps := []urlcheck.Comparison{
+		{Pair: urlcheck.Pair{Candidate: "https://green.example", Reference: "https://blue.example"}, CandidateStatus: 200, ReferenceStatus: 200, Similarity: 1},
+		{Pair: urlcheck.Pair{Candidate: "https://new.example", Reference: "https://old.example"}, CandidateStatus: 404, ReferenceStatus: 200, Similarity: 0.25, Drift: []string{"status 404 != 200"}},
+	}
+	var buf bytes.Buffer
+	if err := writeComparisons(&buf, comps); err != nil {
+		t.Fatalf("writeComparisons: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "200/200") || !strings.Contains(out, "404/200  0.25        status 404 != 200") {
+		t.Fatalf("unexpected output: %q", out)
+	}
+}
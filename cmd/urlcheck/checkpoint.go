@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+type checkpointEvent struct {
+	Event       string    `json:"event"`
+	Time        time.Time `json:"time"`
+	Total       int       `json:"total"`
+	Done        int       `json:"done"`
+	Failed      int       `json:"failed"`
+	FailureRate float64   `json:"failure_rate"`
+	Rate        float64   `json:"rate"`
+	ETA         string    `json:"eta"`
+}
+
+type checkpointer struct {
+	mu     sync.Mutex
+	out    io.Writer
+	asJSON bool
+	every  int
+	total  int
+	done   int
+	failed int
+	start  time.Time
+	now    func() time.Time
+	stop   chan struct{}
+}
+
+func newCheckpointer(out io.Writer, asJSON bool, total, every int) *checkpointer {
+	return &checkpointer{out: out, asJSON: asJSON, total: total, every: every, start: time.Now(), now: time.Now}
+}
+
+func (c *checkpointer) update(r urlcheck.Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.done++
+	if failed(r) {
+		c.failed++
+	}
+	if c.every > 0 && c.done%c.every == 0 && c.done < c.total {
+		c.report()
+	}
+}
+
+func (c *checkpointer) tick(interval time.Duration) {
+	c.stop = make(chan struct{})
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.stop:
+				return
+			case <-ticker.C:
+				c.mu.Lock()
+				c.report()
+				c.mu.Unlock()
+			}
+		}
+	}()
+}
+
+func (c *checkpointer) finish() {
+	if c.stop != nil {
+		close(c.stop)
+	}
+}
+
+func (c *checkpointer) report() {
+	now := c.now()
+	rate, eta := throughput(c.done, c.total, now.Sub(c.start))
+	failureRate := 0.0
+	if c.done > 0 {
+		failureRate = float64(c.failed) / float64(c.done)
+	}
+	if c.asJSON {
+		json.NewEncoder(c.out).Encode(checkpointEvent{
+			Event: "checkpoint", Time: now, Total: c.total, Done: c.done, Failed: c.failed,
+			FailureRate: failureRate, Rate: rate, ETA: eta,
+		})
+		return
+	}
+	fmt.Fprintf(c.out, "checkpoint: %d/%d checked, %d failed (%.1f%%), %.1f req/s, ETA %s\n",
+		c.done, c.total, c.failed, failureRate*100, rate, eta)
+}
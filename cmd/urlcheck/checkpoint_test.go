@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestCheckpointEveryN(t *testing.T) {
+	var buf bytes.Buffer
+	c := newCheckpointer(&buf, false, 5, 2)
+	now := c.start.Add(2 * time.Second)
+	c.now = func() time.Time { return now }
+	c.update(urlcheck.Result{OK: true})
+	c.update(urlcheck.Result{Status: 500})
+	c.update(urlcheck.Result{OK: true})
+	c.update(urlcheck.Result{OK: true})
+	c.update(urlcheck.Result{OK: true})
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected checkpoints at 2 and 4, got %q", buf.String())
+	}
+	if lines[0] != "checkpoint: 2/5 checked, 1 failed (50.0%), 1.0 req/s, ETA 3s" {
+		t.Fatalf("unexpected checkpoint: %q", lines[0])
+	}
+}
+
+func TestCheckpointJSON(t *testing.T) {
+	var buf bytes.Buffer
+	c := newCheckpointer(&buf, true, 4, 0)
+	c.update(urlcheck.Result{Status: 404})
+	c.report()
+	var ev checkpointEvent
+	if err := json.Unmarshal(buf.Bytes(), &ev); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if ev.Event != "checkpoint" || ev.Done != 1 || ev.Failed != 1 || ev.FailureRate != 1 {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+type codeQualityIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+type codeQualityLocation struct {
+	Path  string           `json:"path"`
+	Lines codeQualityLines `json:"lines"`
+}
+
+type codeQualityLines struct {
+	Begin int `json:"begin"`
+}
+
+func writeCodeQuality(out io.Writer, results []urlcheck.Result) error {
+	issues := []codeQualityIssue{}
+	for _, r := range results {
+		if r.OK || r.SkipReason != "" {
+			continue
+		}
+		loc := codeQualityLocation{Path: "urlcheck", Lines: codeQualityLines{Begin: 1}}
+		if r.Source != nil {
+			loc = codeQualityLocation{Path: r.Source.File, Lines: codeQualityLines{Begin: r.Source.Line}}
+		}
+		sum := sha256.Sum256([]byte(loc.Path + "\x00" + r.URL))
+		issues = append(issues, codeQualityIssue{
+			Description: r.URL + ": " + failureMessage(r),
+			CheckName:   "broken-link",
+			Fingerprint: hex.EncodeToString(sum[:16]),
+			Severity:    codeQualitySeverity(r),
+			Location:    loc,
+		})
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(issues)
+}
+
+func codeQualitySeverity(r urlcheck.Result) string {
+	switch r.ErrorKind {
+	case urlcheck.KindForbiddenContent, urlcheck.KindDirectoryListing, urlcheck.KindDefaultPage:
+		return "critical"
+	case urlcheck.KindTimeout:
+		return "minor"
+	}
+	return "major"
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestWriteCodeQuality(t *testing.T) {
+	results := []urlcheck.Result{
+		{URL: "https://ok.example", OK: true, Status: 200},
+		{URL: "https://bad.example", Status: 404, Source: &urlcheck.Location{File: "docs/a.md", Line: 3}},
+		{URL: "https://leak.example", Status: 200, ErrorKind: urlcheck.KindDirectoryListing, Error: "directory listing exposed"},
+	}
+	var buf bytes.Buffer
+	if err := writeCodeQuality(&buf, results); err != nil {
+		t.Fatalf("writeCodeQuality: %v", err)
+	}
+	var issues []codeQualityIssue
+	if err := json.Unmarshal(buf.Bytes(), &issues); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("expected two issues, got %d", len(issues))
+	}
+	if issues[0].Location.Path != "docs/a.md" || issues[0].Location.Lines.Begin != 3 || issues[0].Severity != "major" {
+		t.Fatalf("unexpected first issue: %+v", issues[0])
+	}
+	if issues[1].Severity != "critical" || issues[0].Fingerprint == issues[1].Fingerprint || len(issues[0].Fingerprint) != 32 {
+		t.Fatalf("unexpected second issue: %+v", issues[1])
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+const (
+	ansiReset   = "\x1b[0m"
+	ansiDefault = "\x1b[39m"
+	ansiRed     = "\x1b[31m"
+	ansiGreen   = "\x1b[32m"
+	ansiYellow  = "\x1b[33m"
+)
+
+func useColor(mode string, f *os.File, getenv func(string) string) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto", "":
+		if getenv("NO_COLOR") != "" || getenv("TERM") == "dumb" {
+			return false, nil
+		}
+		return isTerminal(f), nil
+	}
+	return false, fmt.Errorf("invalid -color %q, want auto|always|never", mode)
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func resultColor(r urlcheck.Result) string {
+	switch {
+	case r.SkipReason != "":
+		return ansiDefault
+	case r.ErrorKind == urlcheck.KindTimeout:
+		return ansiYellow
+	case r.OK && (r.Slow || r.Severity == urlcheck.SeverityWarn || r.Status >= http.StatusMultipleChoices):
+		return ansiYellow
+	case r.OK:
+		return ansiGreen
+	}
+	return ansiRed
+}
+
+func writeColorTable(out io.Writer, results []urlcheck.Result) error {
+	labels := hasLabels(results)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, colorCells(ansiDefault, tableHeaderFor(labels)))
+	for _, r := range results {
+		fmt.Fprintln(w, colorCells(resultColor(r), tableRowFor(r, labels)))
+	}
+	return w.Flush()
+}
+
+func colorCells(color string, cells []string) string {
+	colored := make([]string, len(cells))
+	for i, c := range cells {
+		colored[i] = color + c + ansiReset
+	}
+	return strings.Join(colored, "\t")
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestUseColor(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatalf("create temp: %v", err)
+	}
+	defer f.Close()
+	env := func(string) string { return "" }
+	if on, _ := useColor("auto", f, env); on {
+		t.Fatalf("auto must be off for regular files")
+	}
+	if on, _ := useColor("always", f, env); !on {
+		t.Fatalf("always must force color")
+	}
+	if _, err := useColor("sometimes", f, env); err == nil {
+		t.Fatalf("expected error for invalid mode")
+	}
+}
+
+func TestWriteColorTable(t *testing.T) {
+	results := []urlcheck.Result{
+		{URL: "https://ok.example", OK: true, Status: 200},
+		{URL: "https://moved.example", OK: true, Status: 301},
+		{URL: "https://bad.example", Status: 500},
+	}
+	var buf bytes.Buffer
+	if err := writeColorTable(&buf, results); err != nil {
+		t.Fatalf("writeColorTable: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+	if !strings.HasPrefix(lines[1], ansiGreen) || !strings.HasPrefix(lines[2], ansiYellow) || !strings.HasPrefix(lines[3], ansiRed) {
+		t.Fatalf("unexpected colors:\n%q", lines)
+	}
+	statusCol := strings.Index(lines[0], "STATUS")
+	if strings.Index(lines[1], "200") != statusCol || strings.Index(lines[3], "500") != statusCol {
+		t.Fatalf("columns misaligned:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+	"gopkg.in/yaml.v3"
+)
+
+type hostConfig struct {
+	Host    string            `yaml:"host"`
+	URL     string            `yaml:"url"`
+	Timeout string            `yaml:"timeout"`
+	Retries *int              `yaml:"retries"`
+	Method  string            `yaml:"method"`
+	Headers map[string]string `yaml:"headers"`
+	Module  string            `yaml:"module"`
+}
+
+type webhookTarget struct {
+	URL      string `yaml:"url"`
+	Template string `yaml:"template"`
+}
+
+func loadConfigFile(fs *flag.FlagSet, cfg *config, path, profile string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var doc map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	var profiles map[string]map[string]yaml.Node
+	if node, ok := doc["profiles"]; ok {
+		if err := node.Decode(&profiles); err != nil {
+			return fmt.Errorf("%s: profiles: %w", path, err)
+		}
+		delete(doc, "profiles")
+	}
+	if profile != "" {
+		overlay, ok := profiles[profile]
+		if !ok {
+			return fmt.Errorf("%s: unknown profile %q (have %s)", path, profile, strings.Join(slices.Sorted(maps.Keys(profiles)), ", "))
+		}
+		for key, node := range overlay {
+			if key == "profiles" {
+				return fmt.Errorf("%s: profile %q: profiles cannot be nested", path, profile)
+			}
+			doc[key] = node
+		}
+	}
+	if node, ok := doc["modules"]; ok {
+		if cfg.modules, err = loadModules(&node); err != nil {
+			return fmt.Errorf("%s: modules: %w", path, err)
+		}
+		delete(doc, "modules")
+	}
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+	for key, node := range doc {
+		if err := applyConfigKey(fs, cfg, key, &node, explicit); err != nil {
+			return fmt.Errorf("%s: %s: %w", path, key, err)
+		}
+	}
+	return nil
+}
+
+func applyConfigKey(fs *flag.FlagSet, cfg *config, key string, node *yaml.Node, explicit map[string]bool) error {
+	switch {
+	case key == "hosts":
+		var hosts []hostConfig
+		if err := node.Decode(&hosts); err != nil {
+			return err
+		}
+		for _, h := range hosts {
+			if h.Host == "" && h.URL == "" {
+				return fmt.Errorf("entry without host or url")
+			}
+			o := urlcheck.HostOverride{Host: h.Host, URL: h.URL, Retries: h.Retries, Method: strings.ToUpper(h.Method), Headers: h.Headers}
+			if h.Timeout != "" {
+				d, err := time.ParseDuration(h.Timeout)
+				if err != nil {
+					return err
+				}
+				o.Timeout = d
+			}
+			if h.Module != "" {
+				m, ok := cfg.modules[h.Module]
+				if !ok {
+					return fmt.Errorf("unknown module %q", h.Module)
+				}
+				checks, err := m.apply(h, &o)
+				if err != nil {
+					return fmt.Errorf("module %s: %w", h.Module, err)
+				}
+				if checks != nil {
+					cfg.modChecks = append(cfg.modChecks, *checks)
+				}
+			}
+			cfg.hosts = append(cfg.hosts, o)
+		}
+		return nil
+	case key == "assertions" && node.Kind == yaml.SequenceNode:
+		var raw []map[string]any
+		if err := node.Decode(&raw); err != nil {
+			return err
+		}
+		data, err := json.Marshal(raw)
+		if err != nil {
+			return err
+		}
+		cfg.assertList, err = urlcheck.ParseAssertions(data)
+		return err
+	case key == "webhooks":
+		var hooks []webhookTarget
+		if err := node.Decode(&hooks); err != nil {
+			return err
+		}
+		for _, h := range hooks {
+			if h.URL == "" {
+				return fmt.Errorf("entry without url")
+			}
+		}
+		cfg.webhooks = append(cfg.webhooks, hooks...)
+		return nil
+	case key == "config", key == "profile":
+		return fmt.Errorf("cannot be set in a config file")
+	}
+	if fs.Lookup(key) == nil {
+		if knownFlag(key) {
+			return nil
+		}
+		return fmt.Errorf("unknown setting")
+	}
+	if explicit[key] {
+		return nil
+	}
+	var values []string
+	switch node.Kind {
+	case yaml.ScalarNode:
+		values = []string{node.Value}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				return fmt.Errorf("list items must be scalars")
+			}
+			values = append(values, item.Value)
+		}
+	default:
+		return fmt.Errorf("want a scalar or a list")
+	}
+	for _, v := range values {
+		if err := fs.Set(key, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
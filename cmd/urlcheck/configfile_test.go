@@ -0,0 +1,148 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testFlagSet(cfg *config) *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.IntVar(&cfg.concurrency, "concurrency", 5, "")
+	fs.DurationVar(&cfg.timeout, "timeout", 5*time.Second, "")
+	fs.Var(&cfg.outputs, "output", "")
+	fs.StringVar(&cfg.assertFile, "assertions", "", "")
+	fs.StringVar(&cfg.configFile, "config", "", "")
+	return fs
+}
+
+func writeConfig(t *testing.T, body string) string {
+	path := filepath.Join(t.TempDir(), "urlcheck.yaml")
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	path := writeConfig(t, `
+concurrency: 20
+timeout: 3s
+output:
+  - table
+  - json=out.json
+hosts:
+  - host: "*.example.com"
+    timeout: 30s
+    headers:
+      Authorization: Bearer t
+  - url: https://legacy.example.net/report
+    timeout: 1m
+    retries: 0
+    method: head
+assertions:
+  - url: https://example.com/health
+    status: 200
+    body_contains: [ok]
+webhooks:
+  - url: https://hooks.example/one
+  - url: https://hooks.example/two
+    template: '{"text": {{json .Event}}}'
+`)
+	var cfg config
+	fs := testFlagSet(&cfg)
+	if err := fs.Parse([]string{"-timeout", "1s"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := loadConfigFile(fs, &cfg, path, ""); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.concurrency != 20 || cfg.timeout != time.Second {
+		t.Fatalf("expected file concurrency and flag timeout, got %d %v", cfg.concurrency, cfg.timeout)
+	}
+	if len(cfg.outputs) != 2 || cfg.outputs[1] != "json=out.json" {
+		t.Fatalf("unexpected outputs %v", cfg.outputs)
+	}
+	if len(cfg.hosts) != 2 || cfg.hosts[0].Timeout != 30*time.Second || cfg.hosts[0].Headers["Authorization"] != "Bearer t" {
+		t.Fatalf("unexpected hosts %+v", cfg.hosts)
+	}
+	if legacy := cfg.hosts[1]; legacy.URL != "https://legacy.example.net/report" || legacy.Retries == nil || *legacy.Retries != 0 || legacy.Method != "HEAD" {
+		t.Fatalf("unexpected url override %+v", legacy)
+	}
+	if len(cfg.assertList) != 1 || cfg.assertList[0].Status != 200 || cfg.assertList[0].BodyContains[0] != "ok" {
+		t.Fatalf("unexpected assertions %+v", cfg.assertList)
+	}
+	notifiers, err := notifiersFor(cfg)
+	if err != nil || len(notifiers) != 2 {
+		t.Fatalf("expected 2 webhook notifiers, got %d (%v)", len(notifiers), err)
+	}
+}
+
+func TestLoadConfigFileRejectsInvalid(t *testing.T) {
+	for _, body := range []string{
+		"bogus: 1",
+		"concurrency: many",
+		"hosts:\n  - timeout: 1s",
+		"hosts:\n  - host: a\n    timeout: soon",
+		"webhooks:\n  - template: x",
+		"output:\n  - {a: b}",
+		"config: other.yaml",
+		"profile: ci",
+		"profiles: [a]",
+		"concurrency: [",
+	} {
+		var cfg config
+		fs := testFlagSet(&cfg)
+		if err := loadConfigFile(fs, &cfg, writeConfig(t, body), ""); err == nil {
+			t.Errorf("expected error for %q", body)
+		}
+	}
+}
+
+func TestLoadConfigFileAssertionsPath(t *testing.T) {
+	var cfg config
+	fs := testFlagSet(&cfg)
+	if err := loadConfigFile(fs, &cfg, writeConfig(t, "assertions: checks.json"), ""); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.assertFile != "checks.json" || cfg.assertList != nil {
+		t.Fatalf("expected a scalar assertions value to set the flag, got %q %v", cfg.assertFile, cfg.assertList)
+	}
+}
+
+func TestLoadConfigFileProfiles(t *testing.T) {
+	path := writeConfig(t, `
+concurrency: 4
+timeout: 2s
+profiles:
+  ci:
+    concurrency: 16
+    output: [junit=report.xml]
+  docs:
+    timeout: 20s
+`)
+	var cfg config
+	fs := testFlagSet(&cfg)
+	if err := fs.Parse([]string{"-concurrency", "8"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := loadConfigFile(fs, &cfg, path, "ci"); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.concurrency != 8 || cfg.timeout != 2*time.Second || len(cfg.outputs) != 1 || cfg.outputs[0] != "junit=report.xml" {
+		t.Fatalf("unexpected ci profile config: %d %v %v", cfg.concurrency, cfg.timeout, cfg.outputs)
+	}
+	cfg = config{}
+	fs = testFlagSet(&cfg)
+	if err := loadConfigFile(fs, &cfg, path, "docs"); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.concurrency != 4 || cfg.timeout != 20*time.Second {
+		t.Fatalf("unexpected docs profile config: %d %v", cfg.concurrency, cfg.timeout)
+	}
+	if err := loadConfigFile(testFlagSet(&config{}), &config{}, path, "prod"); err == nil {
+		t.Fatal("expected error for unknown profile")
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	base := time.Date(2026, 10, 15, 10, 17, 30, 0, time.UTC)
+	cases := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 10, 15, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 10, 15, 10, 30, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)},
+		{"0 9-17/4 * * 1-5", time.Date(2026, 10, 15, 13, 0, 0, 0, time.UTC)},
+		{"30 6 1 * *", time.Date(2026, 11, 1, 6, 30, 0, 0, time.UTC)},
+		{"0 0 1 * 0", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range cases {
+		s, err := parseCron(tc.spec)
+		if err != nil {
+			t.Fatalf("parseCron(%q): %v", tc.spec, err)
+		}
+		if got := s.next(base); !got.Equal(tc.want) {
+			t.Errorf("%q: got %v, want %v", tc.spec, got, tc.want)
+		}
+	}
+}
+
+func TestParseCronRejectsInvalid(t *testing.T) {
+	for _, spec := range []string{"* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		if _, err := parseCron(spec); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+// startPprof serves the profiling endpoints. A bare port binds loopback only:
+// profiles expose heap contents, so reaching them from elsewhere has to be
+// asked for with an explicit host. The cmdline handler is left out because
+// the command line can carry tokens.
+func startPprof(addr string) (net.Listener, error) {
+	if host, port, err := net.SplitHostPort(addr); err == nil && host == "" {
+		addr = net.JoinHostPort("127.0.0.1", port)
+	}
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	go http.Serve(lis, mux)
+	return lis, nil
+}
+
+func logRuntimeStats(logger *slog.Logger, checker *urlcheck.Checker, total int) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	s := checker.RuntimeStats()
+	logger.Info("runtime stats",
+		"goroutines", runtime.NumGoroutine(),
+		"heap_bytes", mem.HeapAlloc,
+		"queued", int64(total)-s.Dispatched,
+		"in_flight", s.InFlight,
+		"requests_in_flight", s.Requests,
+		"completed", s.Completed)
+}
+
+func debugStats(logger *slog.Logger, checker *urlcheck.Checker, total int, interval time.Duration) func() {
+	stop := make(chan struct{})
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				logRuntimeStats(logger, checker, total)
+			}
+		}
+	}()
+	return func() { close(stop) }
+}
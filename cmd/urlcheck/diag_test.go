@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestStartPprof(t *testing.T) {
+	lis, err := startPprof("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	resp, err := http.Get("http://" + lis.Addr().String() + "/debug/pprof/goroutine?debug=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "goroutine profile") {
+		t.Fatalf("unexpected response %d %q", resp.StatusCode, body)
+	}
+	if _, err := startPprof(lis.Addr().String()); err == nil {
+		t.Fatal("expected an error for an address in use")
+	}
+	resp, err = http.Get("http://" + lis.Addr().String() + "/debug/pprof/cmdline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		t.Fatal("the cmdline handler should not be served")
+	}
+}
+
+func TestStartPprofBarePortBindsLoopback(t *testing.T) {
+	lis, err := startPprof(":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	if !isLoopbackAddr(lis.Addr().String()) {
+		t.Fatalf("expected a loopback listener, got %s", lis.Addr())
+	}
+}
+
+func TestLogRuntimeStats(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	logRuntimeStats(logger, urlcheck.NewChecker(1, time.Second, 0, nil), 5)
+	out := buf.String()
+	for _, want := range []string{"runtime stats", "goroutines=", "heap_bytes=", "queued=5", "in_flight=0", "requests_in_flight=0", "completed=0"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in %q", want, out)
+		}
+	}
+}
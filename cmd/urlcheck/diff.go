@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+const (
+	changeNew           = "new"
+	changeNewlyBroken   = "newly_broken"
+	changeNewlyFixed    = "newly_fixed"
+	changeStatusChanged = "status_changed"
+)
+
+// statePath is where the run's results are kept for the next -diff. Nothing
+// is written unless -state is given or -diff asks for the default location.
+func statePath(cfg config) string {
+	if cfg.state != "" {
+		return cfg.state
+	}
+	if cfg.diff && cfg.outDir != "" {
+		return filepath.Join(cfg.outDir, "last-run.json")
+	}
+	return ""
+}
+
+func saveState(path string, results []urlcheck.Result) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+type runDiff struct {
+	previous map[string]urlcheck.Result
+}
+
+func loadRunDiff(path string) (*runDiff, error) {
+	d := &runDiff{previous: map[string]urlcheck.Result{}}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return d, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var results []urlcheck.Result
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, err
+	}
+	for _, r := range results {
+		d.previous[r.URL] = r
+	}
+	return d, nil
+}
+
+func (d *runDiff) Process(_ context.Context, results []urlcheck.Result) ([]urlcheck.Result, error) {
+	var changed []urlcheck.Result
+	for _, r := range results {
+		if r.SkipReason != "" {
+			continue
+		}
+		prev, seen := d.previous[r.URL]
+		switch {
+		case !seen || prev.SkipReason != "":
+			r.Change = changeNew
+		case prev.OK && !r.OK:
+			r.Change = changeNewlyBroken
+		case !prev.OK && r.OK:
+			r.Change = changeNewlyFixed
+		case prev.Status != r.Status:
+			r.Change = changeStatusChanged
+		default:
+			continue
+		}
+		changed = append(changed, r)
+	}
+	return changed, nil
+}
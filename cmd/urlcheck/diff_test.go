@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestRunDiffReportsChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "last-run.json")
+	previous := []urlcheck.Result{
+		{URL: "https://same.example", OK: true, Status: 200},
+		{URL: "https://breaks.example", OK: true, Status: 200},
+		{URL: "https://heals.example", Status: 503},
+		{URL: "https://moves.example", OK: true, Status: 200},
+	}
+	if err := saveState(path, previous); err != nil {
+		t.Fatalf("saveState: %v", err)
+	}
+	d, err := loadRunDiff(path)
+	if err != nil {
+		t.Fatalf("loadRunDiff: %v", err)
+	}
+	current := []urlcheck.Result{
+		{URL: "https://same.example", OK: true, Status: 200},
+		{URL: "https://breaks.example", Status: 404},
+		{URL: "https://heals.example", OK: true, Status: 200},
+		{URL: "https://moves.example", OK: true, Status: 301},
+		{URL: "https://added.example", OK: true, Status: 200},
+		{URL: "https://skipped.example", SkipReason: "excluded"},
+	}
+	got, err := d.Process(context.Background(), current)
+	if err != nil {
+		t.Fatalf("process: %v", err)
+	}
+	want := map[string]string{
+		"https://breaks.example": changeNewlyBroken,
+		"https://heals.example":  changeNewlyFixed,
+		"https://moves.example":  changeStatusChanged,
+		"https://added.example":  changeNew,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected changes: %+v", got)
+	}
+	for _, r := range got {
+		if want[r.URL] != r.Change {
+			t.Fatalf("%s: got change %q, want %q", r.URL, r.Change, want[r.URL])
+		}
+	}
+}
+
+func TestStatePathDefaultsToOutDir(t *testing.T) {
+	if got := statePath(config{outDir: ".out", diff: true}); got != filepath.Join(".out", "last-run.json") {
+		t.Fatalf("unexpected state path %q", got)
+	}
+	if got := statePath(config{outDir: ".out"}); got != "" {
+		t.Fatalf("state should only be saved with -diff or -state, got %q", got)
+	}
+	if got := statePath(config{state: "prev.json"}); got != "prev.json" {
+		t.Fatalf("unexpected state path %q", got)
+	}
+	if got := statePath(config{}); got != "" {
+		t.Fatalf("expected no state path, got %q", got)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func writeDryRun(out io.Writer, plan []urlcheck.PlannedCheck, skipped []urlcheck.Result, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Checks  []urlcheck.PlannedCheck `json:"checks"`
+			Skipped []urlcheck.Result       `json:"skipped,omitempty"`
+		}{plan, skipped})
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "URL\tCHECK\tMETHOD\tTIMEOUT\tRETRIES\tHEADERS\tNOTES")
+	for _, p := range plan {
+		var headers []string
+		for _, k := range slices.Sorted(maps.Keys(p.Headers)) {
+			headers = append(headers, k+": "+p.Headers[k])
+		}
+		notes := slices.Clone(p.Normalization)
+		if p.Duplicates > 0 {
+			notes = append(notes, fmt.Sprintf("%d duplicate(s) folded", p.Duplicates))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n", p.RequestedURL, p.Check, dash(p.Method), p.Timeout, p.Retries,
+			dash(strings.Join(headers, "; ")), dash(strings.Join(notes, ", ")))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	for _, r := range skipped {
+		fmt.Fprintf(out, "skip %s: %s\n", r.URL, r.SkipReason)
+	}
+	_, err := fmt.Fprintf(out, "%d url(s) would be checked, %d skipped; no requests were made\n", len(plan), len(skipped))
+	return err
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
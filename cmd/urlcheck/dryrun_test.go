@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestWriteDryRun(t *testing.T) {
+	plan := []urlcheck.PlannedCheck{
+		{URL: "Example.com", RequestedURL: "https://example.com", Normalization: []string{"added scheme https"}, Duplicates: 2, Check: "http", Method: "GET", Timeout: 5 * time.Second, Retries: 1, Headers: map[string]string{"X-B": "2", "X-A": "1"}},
+		{URL: "mailto:ops@example.com", RequestedURL: "mailto:ops@example.com", Check: "mailto", Timeout: 5 * time.Second},
+	}
+	skipped := []urlcheck.Result{{URL: "https://internal.example", SkipReason: "excluded by pattern internal"}}
+	var out bytes.Buffer
+	if err := writeDryRun(&out, plan, skipped, false); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"https://example.com     http    GET     5s       1        X-A: 1; X-B: 2  added scheme https, 2 duplicate(s) folded",
+		"mailto:ops@example.com  mailto  -       5s       0        -               -",
+		"skip https://internal.example: excluded by pattern internal",
+		"2 url(s) would be checked, 1 skipped; no requests were made",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("missing %q in:\n%s", want, out.String())
+		}
+	}
+	out.Reset()
+	if err := writeDryRun(&out, plan, skipped, true); err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Checks  []urlcheck.PlannedCheck `json:"checks"`
+		Skipped []urlcheck.Result       `json:"skipped"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil || len(decoded.Checks) != 2 || decoded.Checks[0].Duplicates != 2 || len(decoded.Skipped) != 1 {
+		t.Fatalf("unexpected json %s (%v)", out.String(), err)
+	}
+}
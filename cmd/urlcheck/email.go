@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+var attachmentTypes = map[string]string{
+	"csv":  "text/csv",
+	"html": "text/html",
+	"json": "application/json",
+}
+
+type emailNotifier struct {
+	addr   string
+	from   string
+	to     []string
+	auth   smtp.Auth
+	attach []string
+	send   func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func newEmailNotifier(cfg config, password string) (*emailNotifier, error) {
+	host, _, err := net.SplitHostPort(cfg.smtp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -smtp %q, want host:port", cfg.smtp)
+	}
+	if cfg.emailFrom == "" || len(cfg.emailTo) == 0 {
+		return nil, fmt.Errorf("-smtp requires -email-from and -email-to")
+	}
+	e := &emailNotifier{addr: cfg.smtp, from: cfg.emailFrom, to: cfg.emailTo, send: smtp.SendMail}
+	if cfg.smtpUser != "" {
+		e.auth = smtp.PlainAuth("", cfg.smtpUser, password, host)
+	}
+	for _, name := range strings.Split(cfg.emailAttach, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := attachmentTypes[name]; !ok {
+			return nil, fmt.Errorf("invalid -email-attach %q, want csv|html|json", name)
+		}
+		e.attach = append(e.attach, name)
+	}
+	return e, nil
+}
+
+func emailSubject(n notification) string {
+	s := n.Summary
+	if n.Event == eventStateChange {
+		return fmt.Sprintf("urlcheck: %d url(s) changed state, %d of %d failing", len(n.Changes), s.Broken+s.Errored, s.Total)
+	}
+	return fmt.Sprintf("urlcheck: %d of %d urls failing", s.Broken+s.Errored, s.Total)
+}
+
+func emailDigest(n notification) string {
+	var b strings.Builder
+	s := n.Summary
+	fmt.Fprintf(&b, "urlcheck run at %s\n\n", n.Time.Format(time.RFC1123))
+	fmt.Fprintf(&b, "total %d, ok %d, broken %d, errored %d, skipped %d\n", s.Total, s.OK, s.Broken, s.Errored, s.Skipped)
+	if len(n.Changes) > 0 {
+		b.WriteString("\nchanged state:\n")
+		for _, c := range n.Changes {
+			fmt.Fprintf(&b, "  %s: %s -> %s\n", c.URL, c.From, c.To)
+		}
+	}
+	if len(n.Failures) > 0 {
+		b.WriteString("\nfailing urls:\n")
+		for _, r := range n.Failures {
+			fmt.Fprintf(&b, "  %s  %s\n", r.URL, failureReason(r))
+		}
+	}
+	return b.String()
+}
+
+func (e *emailNotifier) message(n notification) ([]byte, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/plain; charset=utf-8"}})
+	if err != nil {
+		return nil, err
+	}
+	part.Write([]byte(emailDigest(n)))
+	for _, name := range e.attach {
+		var report bytes.Buffer
+		if err := formats[name](&report, n.Results); err != nil {
+			return nil, err
+		}
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {attachmentTypes[name]},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {`attachment; filename="urlcheck.` + name + `"`},
+		})
+		if err != nil {
+			return nil, err
+		}
+		encoded := base64.StdEncoding.EncodeToString(report.Bytes())
+		for len(encoded) > 76 {
+			fmt.Fprintf(part, "%s\r\n", encoded[:76])
+			encoded = encoded[76:]
+		}
+		part.Write([]byte(encoded))
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", e.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(e.to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", emailSubject(n)))
+	fmt.Fprintf(&msg, "Date: %s\r\n", n.Time.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", mw.Boundary())
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+func (e *emailNotifier) notify(_ context.Context, n notification) error {
+	msg, err := e.message(n)
+	if err != nil {
+		return err
+	}
+	return e.send(e.addr, e.auth, e.from, e.to, msg)
+}
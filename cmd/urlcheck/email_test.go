@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestEmailNotifierSendsDigestWithAttachments(t *testing.T) {
+	cfg := config{smtp: "mail.example:587", smtpUser: "bot", emailFrom: "urlcheck@example.com", emailTo: stringList{"ops@example.com"}, emailAttach: "csv,html"}
+	e, err := newEmailNotifier(cfg, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sentTo []string
+	var raw []byte
+	e.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		if addr != "mail.example:587" || a == nil || from != "urlcheck@example.com" {
+			t.Errorf("unexpected envelope %s %v %s", addr, a, from)
+		}
+		sentTo, raw = to, msg
+		return nil
+	}
+	results := []urlcheck.Result{{URL: "https://ok.example", OK: true, Status: 200}, {URL: "https://broken.example", Status: 404}}
+	if err := e.notify(context.Background(), newNotification(eventFailures, results, time.Second, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if len(sentTo) != 1 || sentTo[0] != "ops@example.com" {
+		t.Fatalf("unexpected recipients %v", sentTo)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if subject != "urlcheck: 1 of 2 urls failing" {
+		t.Fatalf("unexpected subject %q", subject)
+	}
+	_, params, _ := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	var parts []string
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(p)
+		if p.Header.Get("Content-Transfer-Encoding") == "base64" {
+			data, err = base64.StdEncoding.DecodeString(strings.ReplaceAll(string(data), "\r\n", ""))
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		parts = append(parts, p.FileName()+":"+string(data))
+	}
+	if len(parts) != 3 || !strings.Contains(parts[0], "https://broken.example  status 404") {
+		t.Fatalf("unexpected parts %q", parts)
+	}
+	if !strings.HasPrefix(parts[1], "urlcheck.csv:") || !strings.Contains(parts[1], "https://broken.example") || !strings.HasPrefix(parts[2], "urlcheck.html:") {
+		t.Fatalf("unexpected attachments %q", parts[1:])
+	}
+}
+
+func TestNewEmailNotifierValidates(t *testing.T) {
+	for _, cfg := range []config{
+		{smtp: "no-port", emailFrom: "a@x", emailTo: stringList{"b@x"}},
+		{smtp: "mail:25", emailTo: stringList{"b@x"}},
+		{smtp: "mail:25", emailFrom: "a@x", emailTo: stringList{"b@x"}, emailAttach: "pdf"},
+	} {
+		if _, err := newEmailNotifier(cfg, ""); err == nil {
+			t.Errorf("expected error for %+v", cfg)
+		}
+	}
+}
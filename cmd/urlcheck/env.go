@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+const envPrefix = "URLCHECK_"
+
+func envName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// envCommaLists are repeatable flags whose values are names or addresses, so
+// their environment variables may also separate entries with commas. Other
+// repeatable flags take regexes, text or commands, where a comma is part of
+// the value; their entries are separated by newlines only.
+var envCommaLists = map[string]bool{
+	"scan": true, "sitemap": true, "include-domain": true, "exclude-domain": true,
+	"output": true, "agent": true, "compare-header": true, "email-to": true,
+}
+
+func applyEnv(fs *flag.FlagSet, environ []string) ([]string, error) {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	byEnv := make(map[string]*flag.Flag)
+	fs.VisitAll(func(f *flag.Flag) { byEnv[envName(f.Name)] = f })
+	var unknown []string
+	for _, kv := range environ {
+		key, value, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(key, envPrefix) {
+			continue
+		}
+		f, ok := byEnv[key]
+		if !ok {
+			if !knownEnv(key) {
+				unknown = append(unknown, key)
+			}
+			continue
+		}
+		if set[f.Name] {
+			continue
+		}
+		values := []string{value}
+		if _, repeatable := f.Value.(*stringList); repeatable {
+			values = strings.FieldsFunc(value, func(r rune) bool { return r == '\n' || (r == ',' && envCommaLists[f.Name]) })
+		}
+		for _, v := range values {
+			if err := fs.Set(f.Name, strings.TrimSpace(v)); err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+		}
+	}
+	return unknown, nil
+}
+
+func knownEnv(key string) bool {
+	known := false
+	allFlags().VisitAll(func(f *flag.Flag) {
+		known = known || envName(f.Name) == key
+	})
+	return known
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApplyEnvPrecedence(t *testing.T) {
+	var cfg config
+	fs := testFlagSet(&cfg)
+	if err := fs.Parse([]string{"-timeout", "1s"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := loadConfigFile(fs, &cfg, writeConfig(t, "concurrency: 20"), ""); err != nil {
+		t.Fatal(err)
+	}
+	unknown, err := applyEnv(fs, []string{
+		"URLCHECK_TIMEOUT=9s",
+		"URLCHECK_CONCURRENCY=30",
+		"URLCHECK_OUTPUT=table, json=out.json",
+		"URLCHECK_BOGUS=1",
+		"HOME=/root",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.timeout != time.Second || cfg.concurrency != 20 {
+		t.Fatalf("flags and file should beat env, got %v %d", cfg.timeout, cfg.concurrency)
+	}
+	if len(cfg.outputs) != 2 || cfg.outputs[1] != "json=out.json" {
+		t.Fatalf("expected comma-separated env list, got %v", cfg.outputs)
+	}
+	if len(unknown) != 1 || unknown[0] != "URLCHECK_BOGUS" {
+		t.Fatalf("unexpected unknown vars %v", unknown)
+	}
+}
+
+func TestApplyEnvRejectsBadValue(t *testing.T) {
+	var cfg config
+	fs := testFlagSet(&cfg)
+	if _, err := applyEnv(fs, []string{"URLCHECK_TIMEOUT=soon"}); err == nil {
+		t.Fatal("expected error for invalid duration")
+	}
+	if envName("max-failure-rate") != "URLCHECK_MAX_FAILURE_RATE" {
+		t.Fatalf("unexpected env name %s", envName("max-failure-rate"))
+	}
+}
+
+func TestApplyEnvKeepsCommasInPatterns(t *testing.T) {
+	var cfg config
+	fs := newFlagSet("check", &cfg)
+	_, err := applyEnv(fs, []string{
+		"URLCHECK_MATCH=a{1,3}\n^https://docs",
+		"URLCHECK_FORBID_FOR=re=a, b",
+		"URLCHECK_INCLUDE_DOMAIN=a.example,b.example",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.match) != 2 || cfg.match[0] != "a{1,3}" || cfg.match[1] != "^https://docs" {
+		t.Fatalf("regex list should split on newlines only, got %q", cfg.match)
+	}
+	if len(cfg.forbidFor) != 1 || cfg.forbidFor[0] != "re=a, b" {
+		t.Fatalf("text values should keep their commas, got %q", cfg.forbidFor)
+	}
+	if len(cfg.includeDom) != 2 {
+		t.Fatalf("domain lists should still split on commas, got %q", cfg.includeDom)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+const (
+	exitOK        = 0
+	exitFailures  = 1
+	exitToolError = 2
+	exitPartial   = 3
+)
+
+type exitPolicy struct {
+	exitZero    bool
+	maxFailures int
+	maxRate     float64
+}
+
+func newExitPolicy(cfg config) (exitPolicy, error) {
+	p := exitPolicy{exitZero: cfg.exitZero, maxFailures: cfg.maxFailures, maxRate: -1}
+	if cfg.maxFailRate != "" {
+		rate, err := parseRate(cfg.maxFailRate)
+		if err != nil {
+			return p, fmt.Errorf("invalid -max-failure-rate %q: %w", cfg.maxFailRate, err)
+		}
+		p.maxRate = rate
+	}
+	return p, nil
+}
+
+func parseRate(spec string) (float64, error) {
+	percent := strings.HasSuffix(spec, "%")
+	v, err := strconv.ParseFloat(strings.TrimSuffix(spec, "%"), 64)
+	if err != nil {
+		return 0, err
+	}
+	if percent {
+		v /= 100
+	}
+	if v < 0 || v > 1 {
+		return 0, fmt.Errorf("must be in [0%%, 100%%]")
+	}
+	return v, nil
+}
+
+func (p exitPolicy) tolerates(failures, checked int) bool {
+	if p.maxFailures < 0 && p.maxRate < 0 {
+		return failures == 0
+	}
+	if p.maxFailures >= 0 && failures > p.maxFailures {
+		return false
+	}
+	if p.maxRate >= 0 && checked > 0 && float64(failures)/float64(checked) > p.maxRate {
+		return false
+	}
+	return true
+}
+
+type tally struct {
+	checked  int
+	failures int
+	skipped  int
+	partial  bool
+}
+
+func (t *tally) add(r urlcheck.Result) {
+	if r.ErrorKind == urlcheck.KindNotAttempted {
+		t.partial = true
+	}
+	if r.SkipReason != "" {
+		t.skipped++
+		return
+	}
+	t.checked++
+	if failed(r) {
+		t.failures++
+	}
+}
+
+func (t tally) code(p exitPolicy) int {
+	if p.exitZero {
+		return exitOK
+	}
+	if !p.tolerates(t.failures, t.checked) {
+		return exitFailures
+	}
+	if t.partial {
+		return exitPartial
+	}
+	return exitOK
+}
+
+func exitCode(results []urlcheck.Result, p exitPolicy) int {
+	var t tally
+	for _, r := range results {
+		t.add(r)
+	}
+	return t.code(p)
+}
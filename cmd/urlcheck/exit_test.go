@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestExitCode(t *testing.T) {
+	ok := urlcheck.Result{URL: "https://a.example", OK: true}
+	broken := urlcheck.Result{URL: "https://b.example", Status: 404}
+	skipped := urlcheck.Result{URL: "https://c.example", SkipReason: "excluded"}
+	unattempted := urlcheck.Result{URL: "https://d.example", SkipReason: "not attempted (budget exhausted)", ErrorKind: urlcheck.KindNotAttempted}
+	strict := exitPolicy{maxFailures: -1, maxRate: -1}
+	cases := []struct {
+		name    string
+		results []urlcheck.Result
+		policy  exitPolicy
+		want    int
+	}{
+		{"all ok", []urlcheck.Result{ok, skipped}, strict, exitOK},
+		{"broken", []urlcheck.Result{ok, broken, unattempted}, strict, exitFailures},
+		{"partial", []urlcheck.Result{ok, unattempted}, strict, exitPartial},
+		{"exit zero", []urlcheck.Result{broken}, exitPolicy{exitZero: true}, exitOK},
+		{"within max failures", []urlcheck.Result{ok, broken}, exitPolicy{maxFailures: 1, maxRate: -1}, exitOK},
+		{"over max failures", []urlcheck.Result{broken, broken}, exitPolicy{maxFailures: 1, maxRate: -1}, exitFailures},
+		{"within max rate", []urlcheck.Result{ok, ok, ok, broken, skipped}, exitPolicy{maxFailures: -1, maxRate: 0.25}, exitOK},
+		{"over max rate", []urlcheck.Result{ok, broken}, exitPolicy{maxFailures: -1, maxRate: 0.25}, exitFailures},
+	}
+	for _, tc := range cases {
+		if got := exitCode(tc.results, tc.policy); got != tc.want {
+			t.Errorf("%s: got %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestNewExitPolicyParsesRate(t *testing.T) {
+	p, err := newExitPolicy(config{maxFailures: -1, maxFailRate: "5%"})
+	if err != nil || p.maxRate != 0.05 {
+		t.Fatalf("unexpected policy %+v (%v)", p, err)
+	}
+	if _, err := newExitPolicy(config{maxFailRate: "150%"}); err == nil {
+		t.Fatal("expected error for rate above 100%")
+	}
+}
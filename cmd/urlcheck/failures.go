@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+// maxSavedBody bounds what is held per response until the run ends; failed
+// pages are for diagnosis, not archival.
+const maxSavedBody = 256 << 10
+
+type capturedResponse struct {
+	URL       string
+	Method    string
+	Status    int
+	Proto     string
+	Header    http.Header
+	At        time.Time
+	body      bytes.Buffer
+	truncated bool
+}
+
+type captureBody struct {
+	io.ReadCloser
+	capture *capturedResponse
+}
+
+func (b captureBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if room := maxSavedBody - b.capture.body.Len(); n > room {
+		b.capture.body.Write(p[:room])
+		b.capture.truncated = true
+	} else {
+		b.capture.body.Write(p[:n])
+	}
+	return n, err
+}
+
+type failureRecorder struct {
+	base http.RoundTripper
+	mu   sync.Mutex
+	last map[string]*capturedResponse
+	// hops lists the keys in last recorded for each originating url, so a
+	// passing check can release every response of its redirect chain.
+	hops map[string][]string
+}
+
+func (f *failureRecorder) wrap(base http.RoundTripper) http.RoundTripper {
+	f.base = base
+	f.last = make(map[string]*capturedResponse)
+	f.hops = make(map[string][]string)
+	return f
+}
+
+func (f *failureRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := f.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	capture := &capturedResponse{
+		URL:    req.URL.String(),
+		Method: req.Method,
+		Status: resp.StatusCode,
+		Proto:  resp.Proto,
+		Header: resp.Header.Clone(),
+		At:     time.Now(),
+	}
+	resp.Body = captureBody{ReadCloser: resp.Body, capture: capture}
+	origin := req
+	for origin.Response != nil && origin.Response.Request != nil {
+		origin = origin.Response.Request
+	}
+	f.mu.Lock()
+	f.last[req.URL.String()] = capture
+	f.last[origin.URL.String()] = capture
+	f.hops[origin.URL.String()] = append(f.hops[origin.URL.String()], req.URL.String())
+	f.mu.Unlock()
+	return resp, nil
+}
+
+// forget drops the responses captured for r once it is known to have passed,
+// so memory only grows with the number of failures.
+func (f *failureRecorder) forget(r urlcheck.Result) {
+	if failed(r) {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, u := range []string{r.RequestedURL, r.URL} {
+		for _, k := range f.hops[u] {
+			delete(f.last, k)
+		}
+		delete(f.hops, u)
+		delete(f.last, u)
+	}
+}
+
+func (f *failureRecorder) lookup(r urlcheck.Result) *capturedResponse {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, u := range []string{r.FinalURL, r.RequestedURL, r.URL} {
+		if c, ok := f.last[u]; ok && u != "" {
+			return c
+		}
+	}
+	return nil
+}
+
+type savedFailure struct {
+	URL          string             `json:"url"`
+	RequestedURL string             `json:"requested_url,omitempty"`
+	Status       int                `json:"status,omitempty"`
+	Error        string             `json:"error,omitempty"`
+	ErrorKind    urlcheck.ErrorKind `json:"error_kind,omitempty"`
+	Attempts     int                `json:"attempts"`
+	Duration     string             `json:"duration"`
+	Response     *savedResponse     `json:"response,omitempty"`
+}
+
+type savedResponse struct {
+	URL       string      `json:"url"`
+	Method    string      `json:"method"`
+	Status    int         `json:"status"`
+	Proto     string      `json:"proto"`
+	Received  time.Time   `json:"received"`
+	Headers   http.Header `json:"headers"`
+	BodyFile  string      `json:"body_file"`
+	BodySize  int         `json:"body_size"`
+	Truncated bool        `json:"truncated,omitempty"`
+}
+
+var unsafeName = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+func (f *failureRecorder) save(dir string, results []urlcheck.Result) (int, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, r := range results {
+		if !failed(r) {
+			continue
+		}
+		n++
+		name := fmt.Sprintf("%03d", n)
+		if u, err := url.Parse(r.URL); err == nil && u.Hostname() != "" {
+			name += "-" + unsafeName.ReplaceAllString(u.Hostname(), "_")
+		}
+		meta := savedFailure{
+			URL:          r.URL,
+			RequestedURL: r.RequestedURL,
+			Status:       r.Status,
+			Error:        r.Error,
+			ErrorKind:    r.ErrorKind,
+			Attempts:     r.Attempts,
+			Duration:     r.Duration.String(),
+		}
+		if c := f.lookup(r); c != nil {
+			meta.Response = &savedResponse{
+				URL:       c.URL,
+				Method:    c.Method,
+				Status:    c.Status,
+				Proto:     c.Proto,
+				Received:  c.At,
+				Headers:   c.Header,
+				BodyFile:  name + ".body",
+				BodySize:  c.body.Len(),
+				Truncated: c.truncated,
+			}
+			if err := os.WriteFile(filepath.Join(dir, name+".body"), c.body.Bytes(), 0o644); err != nil {
+				return n, err
+			}
+		}
+		data, err := json.MarshalIndent(meta, "", "  ")
+		if err != nil {
+			return n, err
+		}
+		if err := os.WriteFile(filepath.Join(dir, name+".json"), append(data, '\n'), 0o644); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestFailureRecorderSavesFailedResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.Write([]byte("fine"))
+		case "/old":
+			http.Redirect(w, r, "/broken", http.StatusFound)
+		default:
+			w.Header().Set("X-Trace", "abc123")
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("<h1>boom</h1>"))
+		}
+	}))
+	defer server.Close()
+	rec := &failureRecorder{}
+	checker := urlcheck.NewChecker(2, time.Second, 0, nil, urlcheck.WithTransport(rec.wrap))
+	results, err := checker.Check(context.Background(), []string{server.URL + "/ok", server.URL + "/old", "http://127.0.0.1:1/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(t.TempDir(), "failures")
+	n, err := rec.save(dir, results)
+	if err != nil || n != 2 {
+		t.Fatalf("save: %d, %v", n, err)
+	}
+	body, err := os.ReadFile(filepath.Join(dir, "001-127.0.0.1.body"))
+	if err != nil || string(body) != "<h1>boom</h1>" {
+		t.Fatalf("unexpected body %q (%v)", body, err)
+	}
+	var meta savedFailure
+	data, err := os.ReadFile(filepath.Join(dir, "001-127.0.0.1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		t.Fatal(err)
+	}
+	if meta.URL != server.URL+"/old" || meta.Status != 500 || meta.Response == nil || meta.Response.URL != server.URL+"/broken" || meta.Response.Headers.Get("X-Trace") != "abc123" {
+		t.Fatalf("unexpected metadata %+v", meta)
+	}
+	data, err = os.ReadFile(filepath.Join(dir, "002-127.0.0.1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta = savedFailure{}
+	if err := json.Unmarshal(data, &meta); err != nil || meta.Response != nil || meta.Error == "" {
+		t.Fatalf("expected connection failure without a response, got %+v (%v)", meta, err)
+	}
+}
+
+func TestFailureRecorderForgetsPassingResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/ok", http.StatusFound)
+			return
+		}
+		w.Write([]byte("fine"))
+	}))
+	defer server.Close()
+	rec := &failureRecorder{}
+	checker := urlcheck.NewChecker(1, time.Second, 0, nil, urlcheck.WithTransport(rec.wrap), urlcheck.WithOnResult(rec.forget))
+	if _, err := checker.Check(context.Background(), []string{server.URL + "/old"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.last) != 0 || len(rec.hops) != 0 {
+		t.Fatalf("passing responses still held: %v", rec.last)
+	}
+}
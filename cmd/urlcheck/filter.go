@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+type urlFilter struct {
+	includeDomains []string
+	excludeDomains []string
+	match          []*regexp.Regexp
+	exclude        []*regexp.Regexp
+	shard          shard
+}
+
+func newURLFilter(cfg config) (urlFilter, error) {
+	f := urlFilter{}
+	for _, p := range cfg.includeDom {
+		if _, err := path.Match(p, ""); err != nil {
+			return f, fmt.Errorf("invalid -include-domain %q: %w", p, err)
+		}
+		f.includeDomains = append(f.includeDomains, strings.ToLower(p))
+	}
+	for _, p := range cfg.excludeDom {
+		if _, err := path.Match(p, ""); err != nil {
+			return f, fmt.Errorf("invalid -exclude-domain %q: %w", p, err)
+		}
+		f.excludeDomains = append(f.excludeDomains, strings.ToLower(p))
+	}
+	for _, p := range cfg.match {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return f, fmt.Errorf("invalid -match %q: %w", p, err)
+		}
+		f.match = append(f.match, re)
+	}
+	for _, p := range cfg.exclude {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return f, fmt.Errorf("invalid -exclude %q: %w", p, err)
+		}
+		f.exclude = append(f.exclude, re)
+	}
+	sh, err := parseShard(cfg.shard)
+	if err != nil {
+		return f, err
+	}
+	f.shard = sh
+	return f, nil
+}
+
+func (f urlFilter) apply(urls []string) ([]string, []urlcheck.Result) {
+	var kept []string
+	var skipped []urlcheck.Result
+	for _, u := range urls {
+		if !f.shard.keep(u) {
+			continue
+		}
+		if reason := f.skipReason(u); reason != "" {
+			skipped = append(skipped, urlcheck.Result{URL: u, SkipReason: reason})
+			continue
+		}
+		kept = append(kept, u)
+	}
+	return kept, skipped
+}
+
+func (f urlFilter) skipReason(raw string) string {
+	for _, re := range f.exclude {
+		if re.MatchString(raw) {
+			return "excluded by pattern " + re.String()
+		}
+	}
+	if len(f.match) > 0 && !matchesAny(f.match, raw) {
+		return "no -match pattern matched"
+	}
+	return f.domainSkipReason(raw)
+}
+
+func matchesAny(patterns []*regexp.Regexp, s string) bool {
+	for _, re := range patterns {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f urlFilter) domainSkipReason(raw string) string {
+	if len(f.includeDomains) == 0 && len(f.excludeDomains) == 0 {
+		return ""
+	}
+	// Match the host the checker will actually contact: a bare "example.com/x"
+	// is fetched as https://example.com/x.
+	normalized, _, err := urlcheck.NormalizeURL(raw)
+	var parsed *url.URL
+	if err == nil {
+		parsed, err = url.Parse(normalized)
+	}
+	if err != nil {
+		if len(f.includeDomains) > 0 {
+			return "domain not included (invalid url)"
+		}
+		return ""
+	}
+	if parsed.Hostname() == "" {
+		return ""
+	}
+	host := strings.ToLower(parsed.Hostname())
+	for _, p := range f.excludeDomains {
+		if ok, _ := path.Match(p, host); ok {
+			return "excluded domain " + p
+		}
+	}
+	if len(f.includeDomains) == 0 {
+		return ""
+	}
+	for _, p := range f.includeDomains {
+		if ok, _ := path.Match(p, host); ok {
+			return ""
+		}
+	}
+	return "domain not included"
+}
@@ -0,0 +1,101 @@
+package main
+
+import "testing"
+
+func TestURLFilterDomains(t *testing.T) {
+	cfg := config{
+		includeDom: stringList{"*.example.com", "example.org"},
+		excludeDom: stringList{"legacy.example.com"},
+	}
+	f, err := newURLFilter(cfg)
+	if err != nil {
+		t.Fatalf("newURLFilter: %v", err)
+	}
+	urls := []string{
+		"https://docs.example.com/a",
+		"https://legacy.example.com/b",
+		"https://example.org/",
+		"https://other.net/",
+	}
+	kept, skipped := f.apply(urls)
+	if len(kept) != 2 || kept[0] != urls[0] || kept[1] != urls[2] {
+		t.Fatalf("unexpected kept urls: %#v", kept)
+	}
+	if len(skipped) != 2 || skipped[0].URL != urls[1] || skipped[1].URL != urls[3] {
+		t.Fatalf("unexpected skipped results: %+v", skipped)
+	}
+	for _, r := range skipped {
+		if r.SkipReason == "" {
+			t.Fatalf("expected skip reason, got %+v", r)
+		}
+	}
+}
+
+func TestURLFilterRejectsBadGlob(t *testing.T) {
+	if _, err := newURLFilter(config{excludeDom: stringList{"[bad"}}); err == nil {
+		t.Fatalf("expected error for malformed glob")
+	}
+}
+
+func TestURLFilterRegex(t *testing.T) {
+	cfg := config{
+		match:   stringList{`^https://`},
+		exclude: stringList{`/archive/`},
+	}
+	f, err := newURLFilter(cfg)
+	if err != nil {
+		t.Fatalf("newURLFilter: %v", err)
+	}
+	urls := []string{
+		"https://a.example/docs",
+		"https://a.example/archive/2019",
+		"http://a.example/docs",
+	}
+	kept, skipped := f.apply(urls)
+	if len(kept) != 1 || kept[0] != urls[0] {
+		t.Fatalf("unexpected kept urls: %#v", kept)
+	}
+	if len(skipped) != 2 {
+		t.Fatalf("expected two skipped, got %+v", skipped)
+	}
+	if _, err := newURLFilter(config{match: stringList{"("}}); err == nil {
+		t.Fatalf("expected error for bad regex")
+	}
+}
+
+func TestURLFilterShardDropsOtherShardsSilently(t *testing.T) {
+	urls := []string{"https://a.example", "https://b.example", "https://c.example", "https://d.example"}
+	seen := 0
+	for _, spec := range []string{"1/2", "2/2"} {
+		f, err := newURLFilter(config{shard: spec})
+		if err != nil {
+			t.Fatal(err)
+		}
+		kept, skipped := f.apply(urls)
+		if len(skipped) != 0 {
+			t.Fatalf("shard %s reported other shards as skipped: %+v", spec, skipped)
+		}
+		seen += len(kept)
+	}
+	if seen != len(urls) {
+		t.Fatalf("shards covered %d of %d urls", seen, len(urls))
+	}
+	if _, err := newURLFilter(config{shard: "3/2"}); err == nil {
+		t.Fatal("expected error for out-of-range shard")
+	}
+}
+
+func TestURLFilterDomainsWithoutScheme(t *testing.T) {
+	f, err := newURLFilter(config{includeDom: stringList{"example.org"}})
+	if err != nil {
+		t.Fatalf("newURLFilter: %v", err)
+	}
+	urls := []string{"evil.com/x", "evil.com:8080", "Example.org/ok", "https://exa mple.org/"}
+	kept, skipped := f.apply(urls)
+	if len(kept) != 1 || kept[0] != "Example.org/ok" {
+		t.Fatalf("bare hosts should be matched against -include-domain, kept %#v", kept)
+	}
+	if len(skipped) != 3 {
+		t.Fatalf("unexpected skipped results: %+v", skipped)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func fixableFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown", ".html", ".htm":
+		return true
+	}
+	return false
+}
+
+func fixLinks(paths []string, results []urlcheck.Result, dryRun bool, diff io.Writer) (int, error) {
+	moves := make(map[string]string)
+	for _, r := range results {
+		if r.OK && r.MovedTo != "" && r.MovedTo != r.URL {
+			moves[moveKey(r.URL)] = r.MovedTo
+		}
+	}
+	if len(moves) == 0 {
+		return 0, nil
+	}
+	changed := 0
+	for _, path := range paths {
+		if !fixableFile(path) {
+			continue
+		}
+		n, err := fixFile(path, moves, dryRun, diff)
+		if err != nil {
+			return changed, err
+		}
+		changed += n
+	}
+	return changed, nil
+}
+
+// moveKey matches a link in a file to a checked url regardless of how it was
+// written: the checker compares normalized urls, which drop the fragment.
+func moveKey(u string) string {
+	if normalized, _, err := urlcheck.NormalizeURL(u); err == nil {
+		return normalized
+	}
+	return u
+}
+
+func fixFile(path string, moves map[string]string, dryRun bool, diff io.Writer) (int, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	changed := 0
+	header := false
+	for i, line := range lines {
+		fixed := linkPattern.ReplaceAllStringFunc(string(line), func(m string) string {
+			u := strings.TrimRight(m, ".,;:!?*_")
+			to, ok := moves[moveKey(u)]
+			if !ok {
+				return m
+			}
+			changed++
+			if _, fragment, found := strings.Cut(u, "#"); found && !strings.Contains(to, "#") {
+				to += "#" + fragment
+			}
+			return to + m[len(u):]
+		})
+		if fixed == string(line) {
+			continue
+		}
+		if dryRun {
+			if !header {
+				fmt.Fprintf(diff, "--- %s\n+++ %s\n", path, path)
+				header = true
+			}
+			fmt.Fprintf(diff, "@@ line %d @@\n-%s\n+%s\n", i+1, strings.TrimRight(string(line), "\r\n"), strings.TrimRight(fixed, "\r\n"))
+		}
+		lines[i] = []byte(fixed)
+	}
+	if changed == 0 || dryRun {
+		return changed, nil
+	}
+	return changed, os.WriteFile(path, bytes.Join(lines, nil), info.Mode().Perm())
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestFixLinksRewritesPermanentMoves(t *testing.T) {
+	dir := t.TempDir()
+	doc := filepath.Join(dir, "README.md")
+	yaml := filepath.Join(dir, "ci.yml")
+	content := "See https://old.example/a. and https://keep.example/\n<a href=\"https://old.example/a\">x</a>\n"
+	for _, p := range []string{doc, yaml} {
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	results := []urlcheck.Result{
+		{URL: "https://old.example/a", OK: true, MovedTo: "https://new.example/a"},
+		{URL: "https://keep.example/", OK: true},
+	}
+	var diff strings.Builder
+	n, err := fixLinks([]string{doc, yaml}, results, true, &diff)
+	if err != nil || n != 2 {
+		t.Fatalf("dry run: expected 2 changes, got %d (%v)", n, err)
+	}
+	if got, _ := os.ReadFile(doc); string(got) != content {
+		t.Fatalf("dry run modified file: %q", got)
+	}
+	want := "--- " + doc + "\n+++ " + doc + "\n@@ line 1 @@\n-See https://old.example/a. and https://keep.example/\n+See https://new.example/a. and https://keep.example/\n"
+	if !strings.HasPrefix(diff.String(), want) {
+		t.Fatalf("unexpected diff:\n%s", diff.String())
+	}
+	if _, err := fixLinks([]string{doc, yaml}, results, false, nil); err != nil {
+		t.Fatalf("fix: %v", err)
+	}
+	got, _ := os.ReadFile(doc)
+	if string(got) != strings.ReplaceAll(content, "old.example", "new.example") {
+		t.Fatalf("unexpected rewrite: %q", got)
+	}
+	if info, _ := os.Stat(doc); info.Mode().Perm() != 0o600 {
+		t.Fatalf("permissions changed: %v", info.Mode())
+	}
+	if got, _ := os.ReadFile(yaml); string(got) != content {
+		t.Fatalf("non-markdown file modified: %q", got)
+	}
+}
+
+func TestFixLinksKeepsFragments(t *testing.T) {
+	doc := filepath.Join(t.TempDir(), "guide.md")
+	content := "[x](https://a.example/old#section), see https://a.example/old#setup.\n"
+	if err := os.WriteFile(doc, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	results := []urlcheck.Result{{URL: "https://a.example/old#section", OK: true, MovedTo: "https://a.example/new"}}
+	if n, err := fixLinks([]string{doc}, results, false, nil); err != nil || n != 2 {
+		t.Fatalf("expected 2 changes, got %d (%v)", n, err)
+	}
+	got, _ := os.ReadFile(doc)
+	if want := "[x](https://a.example/new#section), see https://a.example/new#setup.\n"; string(got) != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestFixLinksIgnoresFailedAndTemporary(t *testing.T) {
+	dir := t.TempDir()
+	doc := filepath.Join(dir, "index.html")
+	content := "https://a.example/ https://b.example/\n"
+	if err := os.WriteFile(doc, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	results := []urlcheck.Result{
+		{URL: "https://a.example/", OK: false, MovedTo: "https://c.example/"},
+		{URL: "https://b.example/", OK: true, FinalURL: "https://d.example/"},
+	}
+	if n, err := fixLinks([]string{doc}, results, false, nil); err != nil || n != 0 {
+		t.Fatalf("expected no changes, got %d (%v)", n, err)
+	}
+}
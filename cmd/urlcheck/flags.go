@@ -0,0 +1,347 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+var flagGroups = map[string]func(*flag.FlagSet, *config){
+	"input":   inputFlags,
+	"scan":    scanFlags,
+	"crawl":   crawlFlags,
+	"filter":  filterFlags,
+	"request": requestFlags,
+	"output":  outputFlags,
+	"run":     runFlags,
+	"notify":  notifyFlags,
+	"watch":   watchFlags,
+	"bench":   benchFlags,
+	"global":  globalFlags,
+}
+
+var commands = map[string]struct {
+	usage  string
+	groups []string
+}{
+	"":      {"[flags]", []string{"input", "scan", "crawl", "filter", "request", "output", "run", "notify", "watch", "global"}},
+	"check": {"check [flags] [url...]", []string{"input", "crawl", "filter", "request", "output", "run", "notify", "global"}},
+	"scan":  {"scan [flags] path...", []string{"scan", "filter", "request", "output", "run", "notify", "global"}},
+	"crawl": {"crawl [flags] sitemap-url...", []string{"filter", "request", "output", "run", "notify", "global"}},
+	"bench": {"bench [flags] [url...]", []string{"input", "bench", "filter", "request", "output", "global"}},
+	"watch": {"watch [flags]", []string{"input", "scan", "crawl", "filter", "request", "output", "notify", "watch", "global"}},
+}
+
+const commandList = `Commands:
+  check    check urls from -file, stdin or the arguments
+  scan     extract and check urls found in files or directories
+  crawl    check every url listed in sitemaps
+  watch    re-check urls on an interval or cron schedule
+  bench    hit each url repeatedly and report latency percentiles and error rate
+  serve    run the http/grpc checking api
+  service  install, uninstall, start or stop watch mode as a windows service
+  history  query a -history database
+  uptime   report availability from a -history database
+  report   chart availability and latency trends from a -history database
+`
+
+func inputFlags(fs *flag.FlagSet, cfg *config) {
+	fs.StringVar(&cfg.file, "file", "", "path to file with urls, one per line, or a .csv/.json of url, timeout, retries, method and labels (defaults to stdin)")
+}
+
+func scanFlags(fs *flag.FlagSet, cfg *config) {
+	fs.Var(&cfg.scan, "scan", "extract links from markdown, html, terraform or yaml files with file:line attribution (repeatable)")
+	fs.BoolVar(&cfg.fix, "fix", false, "rewrite 301/308-redirected links in scanned markdown and html files to their final destination")
+	fs.BoolVar(&cfg.fixDryRun, "fix-dry-run", false, "print the -fix rewrites as a diff on stderr without modifying files")
+}
+
+func benchFlags(fs *flag.FlagSet, cfg *config) {
+	fs.IntVar(&cfg.benchRuns, "runs", 10, "requests per url")
+}
+
+func crawlFlags(fs *flag.FlagSet, cfg *config) {
+	fs.Var(&cfg.sitemaps, "sitemap", "also check every <loc> in this sitemap url (repeatable)")
+}
+
+func filterFlags(fs *flag.FlagSet, cfg *config) {
+	fs.Var(&cfg.includeDom, "include-domain", "only check hosts matching this glob (repeatable)")
+	fs.Var(&cfg.excludeDom, "exclude-domain", "skip hosts matching this glob (repeatable)")
+	fs.Var(&cfg.match, "match", "only check urls matching this regex (repeatable)")
+	fs.Var(&cfg.exclude, "exclude", "skip urls matching this regex (repeatable)")
+	fs.StringVar(&cfg.shard, "shard", "", "check only shard K of N (e.g. 3/10), split by normalized url so parallel jobs cover the list exactly once")
+	fs.StringVar(&cfg.sample, "sample", "", "check a random subset, as a fraction or percentage (e.g. 5%)")
+	fs.IntVar(&cfg.sampleN, "sample-n", 0, "check at most this many randomly chosen urls")
+	fs.IntVar(&cfg.samplePer, "sample-per-host", 0, "when sampling, always include at least this many urls per host")
+	fs.Uint64Var(&cfg.seed, "seed", 0, "random seed for sampling (0 picks one and reports it)")
+}
+
+func requestFlags(fs *flag.FlagSet, cfg *config) {
+	fs.IntVar(&cfg.concurrency, "concurrency", 5, "maximum concurrent checks")
+	fs.DurationVar(&cfg.timeout, "timeout", 5*time.Second, "per-request timeout")
+	fs.IntVar(&cfg.retries, "retries", 1, "retries on network errors")
+	fs.StringVar(&cfg.checkType, "check", string(urlcheck.CheckHTTP), "what to check: http fetches each url, dns only resolves its hostname (A/AAAA/CNAME), tcp only connects to host:port, tls also completes a TLS handshake, grpc-health calls grpc.health.v1.Health/Check (implied for grpc:// and grpcs:// urls), graphql posts -graphql-query and fails responses with errors")
+	fs.StringVar(&cfg.sshKey, "ssh-key", "", "private key used to log in to sftp:// urls (a password in the url works too)")
+	fs.StringVar(&cfg.knownHosts, "ssh-known-hosts", "", "known_hosts file used to verify sftp:// servers (defaults to ~/.ssh/known_hosts)")
+	fs.StringVar(&cfg.certWarn, "cert-expiry-warn", "14d", "warn when a url's TLS certificate expires within this window (e.g. 30d, 72h; 0 disables)")
+	fs.BoolVar(&cfg.warnErrors, "warn-as-error", false, "fail urls that only have warnings (permanent redirects, slow responses, expiring certificates)")
+	fs.BoolVar(&cfg.httpsProbe, "check-https-upgrade", false, "for http:// urls also probe the https:// equivalent and report whether it redirects there, is available, or is unavailable")
+	fs.StringVar(&cfg.acceptEnc, "accept-encoding", "", "comma-separated encodings to request (gzip, br, deflate, identity); fails urls whose response uses another encoding and reports compressed and decoded sizes")
+	fs.BoolVar(&cfg.bucketAuth, "bucket-auth", false, "also treat https S3 and GCS object urls like s3:// and gs:// urls: HEAD them signed with the AWS or Google default credentials")
+	fs.BoolVar(&cfg.allowFile, "allow-file", false, "check file:// urls by opening the local path (off by default so url lists cannot probe the filesystem)")
+	fs.StringVar(&cfg.graphQuery, "graphql-query", "", "query sent by -check=graphql (defaults to 'query { __typename }')")
+	fs.Var(&cfg.forbid, "forbid", "fail urls whose body contains this text (repeatable)")
+	fs.BoolVar(&cfg.dedupe, "dedupe-redirects", false, "check each final redirect target once and report the url mapping")
+	fs.BoolVar(&cfg.misconfig, "detect-misconfig", false, "fail directory listings and stock web server default pages")
+	fs.BoolVar(&cfg.parked, "detect-parked", false, "fail urls that answer 200 with a registrar parking or domain-for-sale page")
+	fs.Var(&cfg.forbidFor, "forbid-for", "regex=text: forbid text only for urls matching regex (repeatable)")
+	fs.StringVar(&cfg.method, "method", "", "http method (defaults to GET, or POST when a body is set)")
+	fs.StringVar(&cfg.bodyFile, "body-file", "", "send this file as the request body")
+	fs.IntVar(&cfg.bodySize, "body-size", 0, "send a generated body of this many bytes")
+	fs.DurationVar(&cfg.expect, "expect-continue", 0, "send Expect: 100-continue and wait this long for the server (0 disables)")
+	fs.IntVar(&cfg.maxRedirect, "max-redirects", 10, "fail urls whose redirect chain is longer than this (0 fails any redirect); loops are always reported as redirect_loop")
+	fs.BoolVar(&cfg.vhostAudit, "audit-vhost", false, "re-probe ok urls with a bogus Host header to detect default-vhost fallthrough")
+	fs.BoolVar(&cfg.wayback, "wayback", false, "look up dead urls in the internet archive and report the closest snapshot to link instead")
+	fs.StringVar(&cfg.verifyNX, "verify-nxdomain", "", "re-check NXDOMAIN failures against this resolver (host:port) before reporting")
+	fs.BoolVar(&cfg.fingerprint, "fingerprint", false, "identify the serving provider from headers, cert issuer and ip asn")
+	fs.BoolVar(&cfg.auditSec, "audit-security", false, "report missing or weak security headers (hsts, csp, x-content-type-options, x-frame-options, referrer-policy) on ok responses")
+	fs.StringVar(&cfg.auditMode, "audit-security-mode", "warn", "what -audit-security does to urls with findings: warn|fail")
+	fs.BoolVar(&cfg.mixed, "mixed-content", false, "flag http:// scripts, stylesheets, images and frames referenced from html pages served over https")
+	fs.StringVar(&cfg.mixedMode, "mixed-content-mode", "warn", "what -mixed-content does to pages with findings: warn|fail")
+	fs.BoolVar(&cfg.canonical, "canonical", false, "verify rel=canonical and hreflang links on html pages are reachable and consistent (hreflang pages must link back)")
+	fs.StringVar(&cfg.canonMode, "canonical-mode", "warn", "what -canonical does to pages with findings: warn|fail")
+	fs.BoolVar(&cfg.render, "render", false, "also load ok pages in headless chrome and fail on js errors, failed subresources or a blank page")
+	fs.DurationVar(&cfg.renderTO, "render-timeout", 15*time.Second, "give up rendering a page after this long")
+	fs.DurationVar(&cfg.renderWait, "render-settle", 500*time.Millisecond, "after the load event, wait this long for scripts to finish before inspecting the page")
+	fs.StringVar(&cfg.chrome, "chrome", "", "path to the chrome/chromium binary for -render (found on PATH by default)")
+	fs.DurationVar(&cfg.maxLatency, "max-latency", 0, "flag ok urls slower than this (0 disables)")
+	fs.StringVar(&cfg.slowMode, "max-latency-mode", "fail", "what -max-latency does to slow urls: fail|warn")
+	fs.StringVar(&cfg.assertFile, "assertions", "", "json file of per-url or per-pattern assertions (status, headers, body, json schema, latency, final url)")
+	fs.DurationVar(&cfg.budget, "budget", 0, "stop dispatching new checks after this long and report coverage")
+	fs.IntVar(&cfg.retryBudget, "retry-budget", 0, "cap the retries spent across the whole run; once used up, failing urls get a single attempt (0 means no cap)")
+	fs.DurationVar(&cfg.maxDuration, "max-duration", 0, "bound the whole run; in-flight and remaining urls are reported as not checked (deadline) once it passes")
+	fs.DurationVar(&cfg.delay, "delay", 0, "wait this long between consecutive requests to the same host")
+	fs.DurationVar(&cfg.delayJitter, "delay-jitter", 0, "add a random extra wait of up to this long to -delay")
+	fs.IntVar(&cfg.transport.MaxIdleConns, "max-idle-conns", 0, "idle connections kept open across all hosts (0 keeps the go default)")
+	fs.IntVar(&cfg.transport.MaxIdleConnsPerHost, "max-idle-conns-per-host", 0, "idle connections kept open per host (0 keeps the go default of 2)")
+	fs.IntVar(&cfg.transport.MaxConnsPerHost, "max-conns-per-host", 0, "limit connections per host, including active ones (0 is unlimited)")
+	fs.DurationVar(&cfg.transport.IdleConnTimeout, "idle-conn-timeout", 0, "close idle connections after this long (0 keeps the go default)")
+	fs.DurationVar(&cfg.transport.TLSHandshakeTimeout, "tls-handshake-timeout", 0, "limit the tls handshake to this long (0 keeps the go default)")
+	fs.DurationVar(&cfg.dial.Timeout, "dial-timeout", 0, "limit establishing a tcp connection to this long (0 leaves it to -timeout)")
+	fs.DurationVar(&cfg.dial.KeepAlive, "keepalive-period", 0, "interval between tcp keep-alive probes (0 keeps the go default, negative disables them)")
+	fs.DurationVar(&cfg.dial.FallbackDelay, "fallback-delay", 0, "how long to wait on ipv6 before racing an ipv4 connection on dual-stack hosts (0 keeps the go default of 300ms, negative disables the race)")
+	fs.BoolVar(&cfg.noKeepAlive, "no-keepalive", false, "open a fresh connection for every request instead of reusing warm ones")
+}
+
+func outputFlags(fs *flag.FlagSet, cfg *config) {
+	fs.BoolVar(&cfg.asJSON, "json", false, "output as json instead of table (same as -format=json)")
+	fs.StringVar(&cfg.format, "format", "table", "output format: "+formatNames())
+	fs.Var(&cfg.outputs, "output", "format[=path], a file path for -format, or statsd=host:port; repeatable, defaults to -format on stdout")
+	fs.StringVar(&cfg.report, "report", "", "also write an html report to this path")
+	fs.StringVar(&cfg.har, "har", "", "record every request and response to this HAR file")
+	fs.StringVar(&cfg.saveFails, "save-failures", "", "write the response body, headers and metadata of each failed url into this directory")
+	fs.StringVar(&cfg.template, "template", "", "render each result with this text/template (e.g. '{{.URL}} {{.Status}}')")
+	fs.StringVar(&cfg.color, "color", "auto", "colorize table output: auto|always|never")
+	fs.BoolVar(&cfg.onlyFails, "only-failures", false, "report only urls that failed")
+	fs.StringVar(&cfg.print, "print", "", "write only the valid|invalid urls to stdout, one per line, for use in pipelines")
+	fs.StringVar(&cfg.groupBy, "group-by", "", "cluster table output by this key: domain")
+	fs.BoolVar(&cfg.hostStats, "stats", false, "add a per-host breakdown (totals, failures, latency, histogram) under the table summary and in json")
+	fs.BoolVar(&cfg.emitCurl, "emit-curl", false, "attach an equivalent curl command (method, headers, body, proxy, timeout) to every failure; printed under the table, \"curl\" in json")
+	fs.StringVar(&cfg.sortBy, "sort", "", "order reported results by status|url|duration|attempts, append :desc to reverse")
+	fs.StringVar(&cfg.outDir, "out-dir", ".out", "directory for valid.txt, invalid.txt and skipped.txt")
+	fs.BoolVar(&cfg.noSplit, "no-split", false, "do not write the valid/invalid/skipped split files")
+	fs.StringVar(&cfg.outFile, "o", "", "write the selected -format to this file instead of stdout")
+	fs.StringVar(&cfg.bundle, "bundle", "", "package results, reports and archives from the run into this .tar.gz, .tar or .zip")
+	fs.StringVar(&cfg.serve, "serve", "", "serve results for grafana json/infinity datasources on this address (after the run, or live with -watch)")
+	fs.StringVar(&cfg.history, "history", "", "append every run's results to this sqlite database (query with: urlcheck history)")
+}
+
+func runFlags(fs *flag.FlagSet, cfg *config) {
+	fs.BoolVar(&cfg.reverify, "verify-failures", false, "re-check failures once more at the end of the run before reporting")
+	fs.DurationVar(&cfg.reverifyTO, "verify-timeout", 0, "timeout for -verify-failures re-checks (defaults to twice -timeout)")
+	fs.BoolVar(&cfg.noProgress, "no-progress", false, "disable the live progress line on stderr")
+	fs.Var(&cfg.postProcess, "post-process", "pipe the full result set as json through this command before reporting (repeatable)")
+	fs.BoolVar(&cfg.dryRun, "dry-run", false, "print the urls that would be checked after normalizing, filtering and folding duplicates, with their effective method, timeout, retries and headers, then exit without making requests")
+	fs.BoolVar(&cfg.quiet, "quiet", false, "print nothing; report only through the exit status")
+	fs.StringVar(&cfg.progressFmt, "progress-format", "text", "progress on stderr: text (tty only) or json event lines")
+	fs.IntVar(&cfg.ckptEvery, "checkpoint-every", 0, "log an intermediate summary on stderr every N results")
+	fs.DurationVar(&cfg.ckptPeriod, "checkpoint-interval", 0, "log an intermediate summary on stderr at this interval")
+	fs.StringVar(&cfg.ckptFile, "checkpoint", "", "append each completed result to this file so an interrupted run can be continued with -resume")
+	fs.StringVar(&cfg.pprof, "pprof", "", "serve net/http/pprof profiles on this address (e.g. 127.0.0.1:6060; a bare :port binds loopback) while the run is going")
+	fs.DurationVar(&cfg.debugStats, "debug-stats", 0, "log goroutines, heap, queue depth and in-flight requests on stderr at this interval")
+	fs.StringVar(&cfg.priority, "priority", "", "check urls in this order instead of input order: failures-first (from -history), label:KEY=VALUE,... or domains:LIST-OR-FILE; results keep input order")
+	fs.BoolVar(&cfg.resume, "resume", false, "skip urls already recorded in -checkpoint and merge their results into this run")
+	fs.StringVar(&cfg.cacheDir, "cache", "", "directory of results kept across runs; urls that passed within -cache-ttl are reported from it instead of re-checked")
+	fs.DurationVar(&cfg.cacheTTL, "cache-ttl", time.Hour, "how long a passing result in -cache stays fresh")
+	fs.BoolVar(&cfg.noCache, "no-cache", false, "re-check every url even if -cache has a fresh result (the cache is still refreshed)")
+	fs.BoolVar(&cfg.stream, "stream", false, "read urls lazily and write ndjson results as they complete, keeping memory flat for huge lists")
+	fs.BoolVar(&cfg.follow, "follow", false, "keep reading stdin or a growing -file (tail -f style) and check urls as they arrive; implies -stream")
+	fs.Var(&cfg.agents, "agent", "http(s):// or grpc:// address of a 'urlcheck serve' agent; repeatable, shards the list across agents instead of checking locally (agents use their own check settings)")
+	fs.IntVar(&cfg.agentBatch, "agent-batch", defaultAgentBatch, "urls sent to an agent per request (keep at or below the agent's -max-urls)")
+	fs.StringVar(&cfg.agentToken, "agent-token", "", "bearer token sent to -agent servers started with -token")
+	fs.BoolVar(&cfg.exitZero, "exit-zero", false, "exit 0 even when urls fail or the run is partial (tool errors still exit 2)")
+	fs.IntVar(&cfg.maxFailures, "max-failures", -1, "tolerate up to this many failed urls before exiting 1")
+	fs.StringVar(&cfg.maxFailRate, "max-failure-rate", "", "tolerate failures up to this fraction or percentage of checked urls (e.g. 5%)")
+	fs.StringVar(&cfg.baseline, "baseline", "", "json file of expected status per url; report only deviations from it")
+	fs.BoolVar(&cfg.updateBase, "update-baseline", false, "rewrite -baseline with the statuses observed in this run")
+	fs.BoolVar(&cfg.diff, "diff", false, "report only urls whose state changed since the previous run")
+	fs.StringVar(&cfg.state, "state", "", "where the previous run's results are kept (with -diff, defaults to last-run.json in -out-dir)")
+	fs.StringVar(&cfg.canary, "canary", "", "compare url pairs (\"candidate reference\" per line) from this file and report drift")
+	fs.Var(&cfg.compareHdr, "compare-header", "response header to compare in -canary mode (repeatable, defaults to Content-Type)")
+	fs.Float64Var(&cfg.minSimilar, "min-similarity", 0.9, "report body drift in -canary mode below this word similarity (0-1)")
+}
+
+func notifyFlags(fs *flag.FlagSet, cfg *config) {
+	fs.StringVar(&cfg.webhook, "webhook", "", "POST a json notification here when failures breach the exit thresholds (in -watch mode: when a url changes state)")
+	fs.StringVar(&cfg.webhookTmpl, "webhook-template", "", "go template for the -webhook body, executed with .Event .Summary .Failures .Changes")
+	fs.StringVar(&cfg.slackHook, "slack-webhook", "", "post a summary of the top failures to this slack incoming webhook")
+	fs.StringVar(&cfg.slackToken, "slack-token", "", "slack bot token for chat.postMessage (default $SLACK_TOKEN), used with -slack-channel")
+	fs.StringVar(&cfg.slackChan, "slack-channel", "", "slack channel for -slack-token")
+	fs.StringVar(&cfg.reportURL, "report-url", "", "link to the published html report, included in notifications")
+	fs.StringVar(&cfg.smtp, "smtp", "", "email a failure digest through this smtp server (host:port)")
+	fs.StringVar(&cfg.smtpUser, "smtp-user", "", "smtp username; the password is read from $SMTP_PASSWORD")
+	fs.StringVar(&cfg.emailFrom, "email-from", "", "sender address for -smtp")
+	fs.Var(&cfg.emailTo, "email-to", "recipient address for -smtp (repeatable)")
+	fs.StringVar(&cfg.emailAttach, "email-attach", "csv", "comma-separated reports attached to the email: csv|html|json")
+}
+
+func watchFlags(fs *flag.FlagSet, cfg *config) {
+	fs.DurationVar(&cfg.interval, "interval", 5*time.Minute, "time between -watch cycles")
+	fs.StringVar(&cfg.schedule, "schedule", "", "json file of url groups with cron schedules, run by -watch instead of -interval")
+	fs.StringVar(&cfg.chdir, "chdir", "", "change to this directory before reading any files, so relative paths resolve against it (command line only; 'service install' sets it)")
+}
+
+func globalFlags(fs *flag.FlagSet, cfg *config) {
+	fs.BoolVar(&cfg.verbose, "v", false, "verbose logging, same as -log-level=debug (logs every attempt and retry)")
+	fs.StringVar(&cfg.logLevel, "log-level", "info", "log level: debug|info|warn|error")
+	fs.StringVar(&cfg.logFormat, "log-format", "text", "log format on stderr: text|json")
+	fs.StringVar(&cfg.otlp, "otlp-endpoint", "", "export a trace span per run and per url to this OTLP/HTTP collector (e.g. http://localhost:4318)")
+	fs.StringVar(&cfg.configFile, "config", "", "yaml file of settings keyed by flag name, plus hosts, assertions and webhooks; flags override it, and it overrides URLCHECK_* environment variables")
+	fs.BoolVar(&cfg.schema, "schema", false, "print the json schema of the -format json output and exit")
+	fs.StringVar(&cfg.profile, "profile", "", "named profile from the -config file's profiles section, layered over its top-level settings")
+}
+
+func newFlagSet(command string, cfg *config) *flag.FlagSet {
+	name := "urlcheck"
+	if command != "" {
+		name += " " + command
+	}
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	for _, group := range commands[command].groups {
+		flagGroups[group](fs, cfg)
+	}
+	if command == "" {
+		fs.BoolVar(&cfg.watch, "watch", false, "keep running and re-check the urls every -interval; SIGHUP reloads the url sources")
+	}
+	fs.Usage = func() {
+		out := fs.Output()
+		fmt.Fprintf(out, "Usage: urlcheck %s\n", commands[command].usage)
+		fs.PrintDefaults()
+		fmt.Fprintf(out, "\nEvery flag can also be set as a -config key or as %s<NAME> (e.g. %s).\n", envPrefix, envName("max-failures"))
+		fmt.Fprintln(out, "Repeatable flags take one entry per line in the environment; lists of names or addresses")
+		fmt.Fprintf(out, "(e.g. %s, %s) may also be comma-separated.\n", envName("output"), envName("include-domain"))
+		fmt.Fprintln(out, "Precedence: flags > -config file > environment > defaults.")
+		if command == "" {
+			fmt.Fprint(out, "\n"+commandList)
+		}
+	}
+	return fs
+}
+
+var allFlags = sync.OnceValue(func() *flag.FlagSet {
+	return newFlagSet("", &config{})
+})
+
+func knownFlag(name string) bool {
+	return allFlags().Lookup(name) != nil
+}
+
+func parseFlags(command string, args []string) config {
+	cfg := config{}
+	fs := newFlagSet(command, &cfg)
+	fs.Parse(args)
+	if cfg.chdir != "" {
+		if err := os.Chdir(cfg.chdir); err != nil {
+			fatal("config error", "flag", "-chdir", "error", err)
+		}
+	}
+	switch command {
+	case "check":
+		cfg.args = fs.Args()
+	case "scan":
+		if fs.NArg() == 0 {
+			fatal("config error", "error", "scan needs at least one path")
+		}
+		cfg.scan = append(cfg.scan, fs.Args()...)
+	case "crawl":
+		if fs.NArg() == 0 {
+			fatal("config error", "error", "crawl needs at least one sitemap url")
+		}
+		cfg.sitemaps = append(cfg.sitemaps, fs.Args()...)
+	case "watch":
+		cfg.watch = true
+	case "bench":
+		cfg.args = fs.Args()
+		cfg.bench = true
+	}
+
+	if cfg.profile == "" {
+		cfg.profile = os.Getenv(envName("profile"))
+	}
+	if cfg.profile != "" && cfg.configFile == "" {
+		fatal("config error", "error", "-profile requires -config")
+	}
+	if cfg.configFile != "" {
+		if err := loadConfigFile(fs, &cfg, cfg.configFile, cfg.profile); err != nil {
+			fatal("config error", "error", err)
+		}
+	}
+	unknown, err := applyEnv(fs, os.Environ())
+	if err != nil {
+		fatal("config error", "error", err)
+	}
+	for _, name := range unknown {
+		slog.Warn("ignoring unknown environment variable", "name", name)
+	}
+	cfg.effective = effectiveConfig(fs)
+	cfg.cacheKey = cacheFingerprint(fs, cfg.configFile, cfg.assertFile, cfg.bodyFile)
+	if cfg.slackToken == "" && cfg.slackChan != "" {
+		cfg.slackToken = os.Getenv("SLACK_TOKEN")
+	}
+	if cfg.noSplit {
+		cfg.outDir = ""
+	}
+	if cfg.follow {
+		cfg.stream = true
+	}
+	if cfg.asJSON {
+		cfg.format = "json"
+	}
+	if cfg.template != "" {
+		cfg.format = "template"
+	}
+	cfg.outputs = outputSpecs(cfg.outputs, cfg.format)
+	if cfg.outFile != "" {
+		cfg.outputs = append(cfg.outputs, cfg.format+"="+cfg.outFile)
+	}
+	if cfg.report != "" {
+		if len(cfg.outputs) == 0 {
+			cfg.outputs = append(cfg.outputs, cfg.format)
+		}
+		cfg.outputs = append(cfg.outputs, "html="+cfg.report)
+	}
+	if cfg.concurrency < 1 {
+		cfg.concurrency = 1
+	}
+	if cfg.timeout <= 0 {
+		cfg.timeout = 5 * time.Second
+	}
+	if cfg.retries < 0 {
+		cfg.retries = 0
+	}
+	return cfg
+}
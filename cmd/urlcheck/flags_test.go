@@ -0,0 +1,84 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCommandFlagSetsAreFocused(t *testing.T) {
+	cases := []struct {
+		command string
+		has     []string
+		lacks   []string
+	}{
+		{"", []string{"file", "scan", "sitemap", "watch", "interval", "canary"}, nil},
+		{"check", []string{"file", "sitemap", "canary", "baseline"}, []string{"scan", "watch", "interval", "schedule"}},
+		{"scan", []string{"concurrency", "output"}, []string{"file", "sitemap", "interval"}},
+		{"crawl", []string{"concurrency", "output"}, []string{"file", "scan", "interval"}},
+		{"watch", []string{"file", "scan", "interval", "schedule"}, []string{"watch", "canary", "quiet"}},
+	}
+	for _, tc := range cases {
+		fs := newFlagSet(tc.command, &config{})
+		for _, name := range tc.has {
+			if fs.Lookup(name) == nil {
+				t.Errorf("%q: missing -%s", tc.command, name)
+			}
+		}
+		for _, name := range tc.lacks {
+			if fs.Lookup(name) != nil {
+				t.Errorf("%q: unexpected -%s", tc.command, name)
+			}
+		}
+	}
+}
+
+func TestParseFlagsPositionalArgs(t *testing.T) {
+	cfg := parseFlags("check", []string{"-concurrency", "3", "https://a.example", "https://b.example"})
+	if cfg.concurrency != 3 || len(cfg.args) != 2 {
+		t.Fatalf("unexpected check config: %d %v", cfg.concurrency, cfg.args)
+	}
+	cfg = parseFlags("scan", []string{"docs", "README.md"})
+	if len(cfg.scan) != 2 || cfg.scan[0] != "docs" {
+		t.Fatalf("unexpected scan paths %v", cfg.scan)
+	}
+	cfg = parseFlags("crawl", []string{"https://example.com/sitemap.xml"})
+	if len(cfg.sitemaps) != 1 {
+		t.Fatalf("unexpected sitemaps %v", cfg.sitemaps)
+	}
+	if cfg = parseFlags("watch", []string{"-file", "urls.txt"}); !cfg.watch {
+		t.Fatal("expected watch command to enable watch mode")
+	}
+}
+
+func TestConfigKeysForOtherCommandsAreIgnored(t *testing.T) {
+	var cfg config
+	fs := newFlagSet("check", &cfg)
+	if err := loadConfigFile(fs, &cfg, writeConfig(t, "interval: 1m\nconcurrency: 9"), ""); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.concurrency != 9 {
+		t.Fatalf("expected concurrency from file, got %d", cfg.concurrency)
+	}
+	unknown, err := applyEnv(fs, []string{"URLCHECK_SCHEDULE=groups.json", "URLCHECK_NOPE=1"})
+	if err != nil || len(unknown) != 1 || unknown[0] != "URLCHECK_NOPE" {
+		t.Fatalf("unexpected env result %v (%v)", unknown, err)
+	}
+}
+
+func TestChdirResolvesRelativeConfig(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "urlcheck.yaml"), []byte("interval: 90s\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	cfg := parseFlags("watch", []string{"-chdir", dir, "-config", "urlcheck.yaml", "-file", "urls.txt"})
+	if cfg.interval != 90*time.Second {
+		t.Fatalf("-config was not read relative to -chdir: interval %v", cfg.interval)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"io"
+	"os"
+	"time"
+)
+
+const followPoll = 250 * time.Millisecond
+
+type followReader struct {
+	f    *os.File
+	stop <-chan struct{}
+	poll time.Duration
+}
+
+func (r *followReader) Read(p []byte) (int, error) {
+	for {
+		n, err := r.f.Read(p)
+		if n > 0 || err != io.EOF {
+			return n, err
+		}
+		if err := r.rewindIfTruncated(); err != nil {
+			return 0, err
+		}
+		select {
+		case <-r.stop:
+			return 0, io.EOF
+		case <-time.After(r.poll):
+		}
+	}
+}
+
+func (r *followReader) rewindIfTruncated() error {
+	pos, err := r.f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	info, err := r.f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() < pos {
+		_, err = r.f.Seek(0, io.SeekStart)
+	}
+	return err
+}
+
+func follow(reader io.Reader, stop <-chan struct{}) io.Reader {
+	f, ok := reader.(*os.File)
+	if !ok {
+		return reader
+	}
+	if info, err := f.Stat(); err != nil || !info.Mode().IsRegular() {
+		return reader
+	}
+	return &followReader{f: f, stop: stop, poll: followPoll}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFollowReaderTailsGrowingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "urls.log")
+	if err := os.WriteFile(path, []byte("https://a.example\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	stop := make(chan struct{})
+	r := follow(f, stop)
+	if fr, ok := r.(*followReader); !ok {
+		t.Fatalf("expected a followReader for a regular file, got %T", r)
+	} else {
+		fr.poll = 5 * time.Millisecond
+	}
+	lines := make(chan string)
+	go func() {
+		sc := bufio.NewScanner(r)
+		for sc.Scan() {
+			lines <- sc.Text()
+		}
+		close(lines)
+	}()
+	expect := func(want string) {
+		t.Helper()
+		select {
+		case got := <-lines:
+			if got != want {
+				t.Fatalf("got %q, want %q", got, want)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for %q", want)
+		}
+	}
+	expect("https://a.example")
+	appendLine(t, path, "https://b.exa")
+	appendLine(t, path, "mple\n")
+	expect("https://b.example")
+	if err := os.WriteFile(path, []byte("https://c.example\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	expect("https://c.example")
+	close(stop)
+	select {
+	case _, ok := <-lines:
+		if ok {
+			t.Fatal("unexpected line after stop")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("reader did not stop")
+	}
+}
+
+func TestFollowLeavesPipesAlone(t *testing.T) {
+	r := strings.NewReader("https://a.example\n")
+	if got := follow(r, nil); got != r {
+		t.Fatalf("expected non-file readers to be returned as is, got %T", got)
+	}
+}
+
+func appendLine(t *testing.T, path, s string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(s); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func writeGitHub(out io.Writer, results []urlcheck.Result) error {
+	w := bufio.NewWriter(out)
+	failed := 0
+	for _, r := range results {
+		if r.OK || r.SkipReason != "" {
+			continue
+		}
+		failed++
+		props := []string{"title=" + escapeProperty("Broken link")}
+		if r.Source != nil {
+			props = append([]string{
+				"file=" + escapeProperty(r.Source.File),
+				fmt.Sprintf("line=%d", r.Source.Line),
+			}, props...)
+		}
+		fmt.Fprintf(w, "::error %s::%s\n", strings.Join(props, ","), escapeData(r.URL+": "+failureMessage(r)))
+	}
+	fmt.Fprintf(w, "::notice title=urlcheck::%s\n", escapeData(fmt.Sprintf("%d of %d urls failed", failed, len(results))))
+	return w.Flush()
+}
+
+func escapeData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	return strings.ReplaceAll(s, "\n", "%0A")
+}
+
+func escapeProperty(s string) string {
+	s = escapeData(s)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	return strings.ReplaceAll(s, ",", "%2C")
+}
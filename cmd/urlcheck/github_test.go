@@ -0,0 +1,34 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestWriteGitHubAnnotations(t *testing.T) {
+	results := []urlcheck.Result{
+		{URL: "https://ok.example", OK: true, Status: 200},
+		{URL: "https://bad.example/100%", Status: 404, Source: &urlcheck.Location{File: "docs/a,b.md", Line: 7}},
+		{URL: "https://down.example", Error: "dial tcp: refused\nretry"},
+	}
+	var buf bytes.Buffer
+	if err := writeGitHub(&buf, results); err != nil {
+		t.Fatalf("writeGitHub: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+	if lines[0] != "::error file=docs/a%2Cb.md,line=7,title=Broken link::https://bad.example/100%25: unexpected status 404" {
+		t.Fatalf("unexpected first annotation: %s", lines[0])
+	}
+	if lines[1] != "::error title=Broken link::https://down.example: dial tcp: refused%0Aretry" {
+		t.Fatalf("unexpected second annotation: %s", lines[1])
+	}
+	if lines[2] != "::notice title=urlcheck::2 of 3 urls failed" {
+		t.Fatalf("unexpected notice: %s", lines[2])
+	}
+}
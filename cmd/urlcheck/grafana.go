@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+type storedRun struct {
+	At      time.Time         `json:"at"`
+	Results []urlcheck.Result `json:"results"`
+}
+
+type resultStore struct {
+	mu      sync.RWMutex
+	runs    []storedRun
+	maxRuns int
+}
+
+func newResultStore(maxRuns int) *resultStore {
+	return &resultStore{maxRuns: maxRuns}
+}
+
+func (s *resultStore) add(at time.Time, results []urlcheck.Result) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runs = append(s.runs, storedRun{At: at, Results: results})
+	if s.maxRuns > 0 && len(s.runs) > s.maxRuns {
+		s.runs = s.runs[len(s.runs)-s.maxRuns:]
+	}
+}
+
+func (s *resultStore) snapshot() []storedRun {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]storedRun(nil), s.runs...)
+}
+
+func (s *resultStore) latest() storedRun {
+	runs := s.snapshot()
+	if len(runs) == 0 {
+		return storedRun{Results: []urlcheck.Result{}}
+	}
+	return runs[len(runs)-1]
+}
+
+var grafanaTargets = []string{"availability", "latency", "failures", "results"}
+
+type grafanaQuery struct {
+	Range struct {
+		From time.Time `json:"from"`
+		To   time.Time `json:"to"`
+	} `json:"range"`
+	Targets []struct {
+		Target string `json:"target"`
+	} `json:"targets"`
+}
+
+type grafanaSeries struct {
+	Target     string       `json:"target"`
+	Datapoints [][2]float64 `json:"datapoints"`
+}
+
+type grafanaColumn struct {
+	Text string `json:"text"`
+	Type string `json:"type"`
+}
+
+type grafanaTable struct {
+	Type    string          `json:"type"`
+	Columns []grafanaColumn `json:"columns"`
+	Rows    [][]any         `json:"rows"`
+}
+
+func grafanaHandler(store *resultStore) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	search := func(w http.ResponseWriter, r *http.Request) {
+		writeJSONResponse(w, grafanaTargets)
+	}
+	mux.HandleFunc("POST /search", search)
+	mux.HandleFunc("POST /metrics", func(w http.ResponseWriter, r *http.Request) {
+		var out []map[string]string
+		for _, t := range grafanaTargets {
+			out = append(out, map[string]string{"label": t, "value": t})
+		}
+		writeJSONResponse(w, out)
+	})
+	mux.HandleFunc("POST /query", func(w http.ResponseWriter, r *http.Request) {
+		var q grafanaQuery
+		if err := json.NewDecoder(r.Body).Decode(&q); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		runs := store.snapshot()
+		var out []any
+		for _, t := range q.Targets {
+			if t.Target == "results" {
+				out = append(out, resultsTable(store.latest().Results))
+				continue
+			}
+			out = append(out, series(t.Target, runs, q.Range.From, q.Range.To))
+		}
+		writeJSONResponse(w, out)
+	})
+	mux.HandleFunc("GET /api/results", func(w http.ResponseWriter, r *http.Request) {
+		writeJSONResponse(w, store.latest())
+	})
+	mux.HandleFunc("GET /api/history", func(w http.ResponseWriter, r *http.Request) {
+		writeJSONResponse(w, store.snapshot())
+	})
+	return mux
+}
+
+func series(target string, runs []storedRun, from, to time.Time) grafanaSeries {
+	s := grafanaSeries{Target: target, Datapoints: [][2]float64{}}
+	for _, run := range runs {
+		if !from.IsZero() && run.At.Before(from) || !to.IsZero() && run.At.After(to) {
+			continue
+		}
+		var ok, checked int
+		var total time.Duration
+		for _, r := range run.Results {
+			if r.SkipReason != "" {
+				continue
+			}
+			checked++
+			total += r.Duration
+			if r.OK {
+				ok++
+			}
+		}
+		if checked == 0 {
+			continue
+		}
+		var v float64
+		switch target {
+		case "availability":
+			v = float64(ok) * 100 / float64(checked)
+		case "latency":
+			v = float64(total.Milliseconds()) / float64(checked)
+		case "failures":
+			v = float64(checked - ok)
+		default:
+			continue
+		}
+		s.Datapoints = append(s.Datapoints, [2]float64{v, float64(run.At.UnixMilli())})
+	}
+	return s
+}
+
+func resultsTable(results []urlcheck.Result) grafanaTable {
+	t := grafanaTable{
+		Type: "table",
+		Columns: []grafanaColumn{
+			{Text: "URL", Type: "string"},
+			{Text: "Status", Type: "number"},
+			{Text: "OK", Type: "boolean"},
+			{Text: "Duration (ms)", Type: "number"},
+			{Text: "Error", Type: "string"},
+		},
+		Rows: [][]any{},
+	}
+	for _, r := range results {
+		t.Rows = append(t.Rows, []any{r.URL, r.Status, r.OK, r.Duration.Milliseconds(), errorText(r)})
+	}
+	return t
+}
+
+func writeJSONResponse(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestGrafanaQuery(t *testing.T) {
+	store := newResultStore(10)
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.add(t0, []urlcheck.Result{
+		{URL: "https://a.example", OK: true, Status: 200, Duration: 100 * time.Millisecond},
+		{URL: "https://b.example", Status: 500, Duration: 300 * time.Millisecond},
+	})
+	store.add(t0.Add(time.Hour), []urlcheck.Result{
+		{URL: "https://a.example", OK: true, Status: 200, Duration: 100 * time.Millisecond},
+		{URL: "https://b.example", OK: true, Status: 200, Duration: 100 * time.Millisecond},
+	})
+	server := httptest.NewServer(grafanaHandler(store))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/")
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("health check failed: %v %v", resp, err)
+	}
+	body := `{"range":{"from":"2025-12-31T00:00:00Z","to":"2026-01-02T00:00:00Z"},"targets":[{"target":"availability"},{"target":"results"}]}`
+	resp, err = http.Post(server.URL+"/query", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	defer resp.Body.Close()
+	var out []json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	var availability grafanaSeries
+	if err := json.Unmarshal(out[0], &availability); err != nil {
+		t.Fatalf("decode series: %v", err)
+	}
+	if len(availability.Datapoints) != 2 || availability.Datapoints[0][0] != 50 || availability.Datapoints[1][0] != 100 {
+		t.Fatalf("unexpected availability: %+v", availability)
+	}
+	if availability.Datapoints[0][1] != float64(t0.UnixMilli()) {
+		t.Fatalf("unexpected timestamp: %v", availability.Datapoints[0][1])
+	}
+	var table grafanaTable
+	if err := json.Unmarshal(out[1], &table); err != nil {
+		t.Fatalf("decode table: %v", err)
+	}
+	if table.Type != "table" || len(table.Rows) != 2 {
+		t.Fatalf("unexpected table: %+v", table)
+	}
+}
+
+func TestResultStoreKeepsLastRuns(t *testing.T) {
+	store := newResultStore(2)
+	for i := 0; i < 3; i++ {
+		store.add(time.Unix(int64(i), 0), nil)
+	}
+	runs := store.snapshot()
+	if len(runs) != 2 || runs[0].At.Unix() != 1 {
+		t.Fatalf("unexpected runs: %+v", runs)
+	}
+}
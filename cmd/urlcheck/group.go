@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func groupByDomain(render formatter) formatter {
+	return func(out io.Writer, results []urlcheck.Result) error {
+		groups := make(map[string][]urlcheck.Result)
+		failures := make(map[string]int)
+		var hosts []string
+		for _, r := range results {
+			host := hostOf(r.URL)
+			if host == "" {
+				host = "(no host)"
+			}
+			if _, ok := groups[host]; !ok {
+				hosts = append(hosts, host)
+			}
+			groups[host] = append(groups[host], r)
+			if failed(r) {
+				failures[host]++
+			}
+		}
+		sort.SliceStable(hosts, func(i, j int) bool {
+			if failures[hosts[i]] != failures[hosts[j]] {
+				return failures[hosts[i]] > failures[hosts[j]]
+			}
+			return hosts[i] < hosts[j]
+		})
+		for i, host := range hosts {
+			if i > 0 {
+				fmt.Fprintln(out)
+			}
+			fmt.Fprintf(out, "== %s (%d urls, %d failed) ==\n", host, len(groups[host]), failures[host])
+			if err := render(out, groups[host]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
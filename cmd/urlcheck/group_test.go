@@ -0,0 +1,31 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestGroupByDomainOrdersByFailures(t *testing.T) {
+	results := []urlcheck.Result{
+		{URL: "https://a.example/1", OK: true, Status: 200},
+		{URL: "https://b.example/1", Status: 500},
+		{URL: "https://a.example/2", OK: true, Status: 200},
+		{URL: "https://b.example/2", Status: 404},
+	}
+	var buf bytes.Buffer
+	if err := groupByDomain(writeTable)(&buf, results); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	out := buf.String()
+	b := strings.Index(out, "== b.example (2 urls, 2 failed) ==")
+	a := strings.Index(out, "== a.example (2 urls, 0 failed) ==")
+	if b < 0 || a < 0 || b > a {
+		t.Fatalf("expected b.example first, got %q", out)
+	}
+	if strings.Count(out, "URL ") != 2 {
+		t.Fatalf("expected a table per host, got %q", out)
+	}
+}
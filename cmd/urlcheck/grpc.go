@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/checkpb"
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type checkService struct {
+	checkpb.UnimplementedCheckServiceServer
+	newChecker func(...urlcheck.Option) *urlcheck.Checker
+	maxURLs    int
+}
+
+func (s *checkService) validate(req *checkpb.CheckRequest) error {
+	switch {
+	case len(req.Urls) == 0:
+		return status.Error(codes.InvalidArgument, "no urls")
+	case len(req.Urls) > s.maxURLs:
+		return status.Errorf(codes.InvalidArgument, "too many urls: %d > %d", len(req.Urls), s.maxURLs)
+	}
+	return nil
+}
+
+func (s *checkService) Check(ctx context.Context, req *checkpb.CheckRequest) (*checkpb.CheckResponse, error) {
+	if err := s.validate(req); err != nil {
+		return nil, err
+	}
+	started := time.Now()
+	results, err := s.newChecker().Check(ctx, req.Urls)
+	if err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	resp := &checkpb.CheckResponse{Summary: summaryProto(urlcheck.Summarize(results, time.Since(started)))}
+	for _, r := range results {
+		resp.Results = append(resp.Results, resultProto(r))
+	}
+	return resp, nil
+}
+
+func (s *checkService) CheckStream(req *checkpb.CheckRequest, stream grpc.ServerStreamingServer[checkpb.Result]) error {
+	if err := s.validate(req); err != nil {
+		return err
+	}
+	var sendErr error
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	checker := s.newChecker(urlcheck.WithOnResult(func(r urlcheck.Result) {
+		if sendErr != nil {
+			return
+		}
+		if sendErr = stream.Send(resultProto(r)); sendErr != nil {
+			cancel()
+		}
+	}))
+	if _, err := checker.Check(ctx, req.Urls); err != nil && sendErr == nil {
+		return status.FromContextError(err).Err()
+	}
+	return sendErr
+}
+
+func resultProto(r urlcheck.Result) *checkpb.Result {
+	return &checkpb.Result{
+		Url:        r.URL,
+		Ok:         r.OK,
+		Status:     int32(r.Status),
+		Error:      r.Error,
+		ErrorKind:  string(r.ErrorKind),
+		Attempts:   int32(r.Attempts),
+		DurationMs: r.Duration.Milliseconds(),
+		FinalUrl:   r.FinalURL,
+		SkipReason: r.SkipReason,
+	}
+}
+
+func summaryProto(s urlcheck.Summary) *checkpb.Summary {
+	return &checkpb.Summary{
+		Total:           int32(s.Total),
+		Ok:              int32(s.OK),
+		Broken:          int32(s.Broken),
+		Errored:         int32(s.Errored),
+		Skipped:         int32(s.Skipped),
+		P50Ms:           s.P50.Milliseconds(),
+		P95Ms:           s.P95.Milliseconds(),
+		TotalDurationMs: s.TotalDuration.Milliseconds(),
+	}
+}
+
+// tokenInterceptors require "authorization: Bearer <token>" metadata on every
+// call. An empty token returns no options.
+func tokenInterceptors(token string) []grpc.ServerOption {
+	if token == "" {
+		return nil
+	}
+	check := func(ctx context.Context) error {
+		md, _ := metadata.FromIncomingContext(ctx)
+		for _, v := range md.Get("authorization") {
+			if validToken(token, v) {
+				return nil
+			}
+		}
+		return status.Error(codes.Unauthenticated, "missing or invalid bearer token")
+	}
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			if err := check(ctx); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := check(ss.Context()); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	}
+}
+
+func newGRPCServer(svc *checkService, opts ...grpc.ServerOption) *grpc.Server {
+	srv := grpc.NewServer(opts...)
+	checkpb.RegisterCheckServiceServer(srv, svc)
+	return srv
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/checkpb"
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestGRPC(t *testing.T) (checkpb.CheckServiceClient, string) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(target.Close)
+	lis := bufconn.Listen(1 << 20)
+	srv := newGRPCServer(&checkService{
+		newChecker: func(opts ...urlcheck.Option) *urlcheck.Checker {
+			return urlcheck.NewChecker(2, time.Second, 0, nil, opts...)
+		},
+		maxURLs: 2,
+	})
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return checkpb.NewCheckServiceClient(conn), target.URL
+}
+
+func TestGRPCCheck(t *testing.T) {
+	client, target := newTestGRPC(t)
+	resp, err := client.Check(context.Background(), &checkpb.CheckRequest{Urls: []string{target + "/ok", target + "/broken"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Summary.Total != 2 || resp.Summary.Broken != 1 || len(resp.Results) != 2 || resp.Results[1].Status != http.StatusNotFound {
+		t.Fatalf("unexpected response: %v", resp)
+	}
+	_, err = client.Check(context.Background(), &checkpb.CheckRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for empty request, got %v", err)
+	}
+}
+
+func TestGRPCCheckStream(t *testing.T) {
+	client, target := newTestGRPC(t)
+	stream, err := client.CheckStream(context.Background(), &checkpb.CheckRequest{Urls: []string{target + "/ok", target + "/broken"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]int32)
+	for {
+		r, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen[r.Url] = r.Status
+	}
+	if len(seen) != 2 || seen[target+"/broken"] != http.StatusNotFound {
+		t.Fatalf("unexpected streamed results: %v", seen)
+	}
+}
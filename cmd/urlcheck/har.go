@@ -0,0 +1,216 @@
+package main
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"net/http/httptrace"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+type harLog struct {
+	Log harBody `json:"log"`
+}
+
+type harBody struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Error           string      `json:"_error,omitempty"`
+	started         time.Time
+}
+
+type harRequest struct {
+	Method      string  `json:"method"`
+	URL         string  `json:"url"`
+	HTTPVersion string  `json:"httpVersion"`
+	Headers     []harNV `json:"headers"`
+	QueryString []harNV `json:"queryString"`
+	Cookies     []harNV `json:"cookies"`
+	HeadersSize int     `json:"headersSize"`
+	BodySize    int64   `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int        `json:"status"`
+	StatusText  string     `json:"statusText"`
+	HTTPVersion string     `json:"httpVersion"`
+	Headers     []harNV    `json:"headers"`
+	Cookies     []harNV    `json:"cookies"`
+	Content     harContent `json:"content"`
+	RedirectURL string     `json:"redirectURL"`
+	HeadersSize int        `json:"headersSize"`
+	BodySize    int64      `json:"bodySize"`
+}
+
+type harContent struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+}
+
+type harNV struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	SSL     float64 `json:"ssl"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+type harRecorder struct {
+	base    http.RoundTripper
+	mu      sync.Mutex
+	entries []harEntry
+}
+
+func (h *harRecorder) wrap(base http.RoundTripper) http.RoundTripper {
+	h.base = base
+	return h
+}
+
+func (h *harRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var mu sync.Mutex
+	var dnsStart, dnsDone, connStart, connDone, tlsStart, tlsDone, wrote, firstByte time.Time
+	mark := func(t *time.Time) {
+		mu.Lock()
+		*t = time.Now()
+		mu.Unlock()
+	}
+	trace := &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { mark(&dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { mark(&dnsDone) },
+		ConnectStart:         func(string, string) { mark(&connStart) },
+		ConnectDone:          func(string, string, error) { mark(&connDone) },
+		TLSHandshakeStart:    func() { mark(&tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { mark(&tlsDone) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { mark(&wrote) },
+		GotFirstResponseByte: func() { mark(&firstByte) },
+	}
+	start := time.Now()
+	resp, err := h.base.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+	end := time.Now()
+	mu.Lock()
+	defer mu.Unlock()
+	entry := harEntry{
+		StartedDateTime: start.UTC().Format(time.RFC3339Nano),
+		Time:            millis(end.Sub(start)),
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         req.URL.String(),
+			HTTPVersion: req.Proto,
+			Headers:     harHeaders(req.Header),
+			QueryString: harQuery(req),
+			Cookies:     []harNV{},
+			HeadersSize: -1,
+			BodySize:    req.ContentLength,
+		},
+		Timings: harTimings{
+			Blocked: -1,
+			DNS:     span(dnsStart, dnsDone),
+			Connect: span(connStart, connDone),
+			SSL:     span(tlsStart, tlsDone),
+			Send:    0,
+			Wait:    max(span(wrote, firstByte), 0),
+			Receive: 0,
+		},
+		started: start,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+		entry.Response = harResponse{Headers: []harNV{}, Cookies: []harNV{}, HeadersSize: -1, BodySize: -1}
+	} else {
+		entry.Response = harResponse{
+			Status:      resp.StatusCode,
+			StatusText:  http.StatusText(resp.StatusCode),
+			HTTPVersion: resp.Proto,
+			Headers:     harHeaders(resp.Header),
+			Cookies:     []harNV{},
+			Content:     harContent{Size: resp.ContentLength, MimeType: resp.Header.Get("Content-Type")},
+			RedirectURL: resp.Header.Get("Location"),
+			HeadersSize: -1,
+			BodySize:    resp.ContentLength,
+		}
+	}
+	h.mu.Lock()
+	h.entries = append(h.entries, entry)
+	h.mu.Unlock()
+	return resp, err
+}
+
+func (h *harRecorder) writeFile(path string) error {
+	h.mu.Lock()
+	entries := append([]harEntry{}, h.entries...)
+	h.mu.Unlock()
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].started.Before(entries[j].started) })
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(harLog{Log: harBody{
+		Version: "1.2",
+		Creator: harCreator{Name: "urlcheck", Version: "dev"},
+		Entries: entries,
+	}}); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func harHeaders(h http.Header) []harNV {
+	out := []harNV{}
+	for name, values := range h {
+		for _, v := range values {
+			out = append(out, harNV{Name: name, Value: v})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func harQuery(req *http.Request) []harNV {
+	out := []harNV{}
+	for name, values := range req.URL.Query() {
+		for _, v := range values {
+			out = append(out, harNV{Name: name, Value: v})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func span(start, end time.Time) float64 {
+	if start.IsZero() || end.IsZero() {
+		return -1
+	}
+	return millis(end.Sub(start))
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestHARRecorderCapturesRedirectHops(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new?x=1", http.StatusMovedPermanently)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	rec := &harRecorder{}
+	checker := urlcheck.NewChecker(1, time.Second, 0, server.Client(), urlcheck.WithTransport(rec.wrap))
+	if _, err := checker.Check(context.Background(), []string{server.URL + "/old"}); err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "out.har")
+	if err := rec.writeFile(path); err != nil {
+		t.Fatalf("writeFile: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read har: %v", err)
+	}
+	var log harLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatalf("parse har: %v", err)
+	}
+	if log.Log.Version != "1.2" || len(log.Log.Entries) != 2 {
+		t.Fatalf("expected two entries, got %+v", log.Log)
+	}
+	first, second := log.Log.Entries[0], log.Log.Entries[1]
+	if first.Response.Status != http.StatusMovedPermanently || first.Response.RedirectURL != "/new?x=1" {
+		t.Fatalf("unexpected first hop: %+v", first.Response)
+	}
+	if second.Response.Status != http.StatusOK || len(second.Request.QueryString) != 1 {
+		t.Fatalf("unexpected second hop: %+v", second)
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+	_ "modernc.org/sqlite"
+)
+
+const historySchema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	started_at TEXT NOT NULL,
+	finished_at TEXT NOT NULL,
+	total INTEGER NOT NULL,
+	failed INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS results (
+	run_id INTEGER NOT NULL REFERENCES runs(id),
+	url TEXT NOT NULL,
+	ok INTEGER NOT NULL,
+	status INTEGER NOT NULL,
+	error TEXT NOT NULL,
+	error_kind TEXT NOT NULL,
+	attempts INTEGER NOT NULL,
+	duration_ms REAL NOT NULL
+);
+CREATE INDEX IF NOT EXISTS results_url ON results(url);
+`
+
+type historyRow struct {
+	runID     int64
+	startedAt time.Time
+	ok        bool
+	status    int
+	err       string
+	duration  time.Duration
+}
+
+type flakyURL struct {
+	url      string
+	runs     int
+	failures int
+}
+
+func openHistory(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(historySchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func recordRun(db *sql.DB, started, finished time.Time, results []urlcheck.Result) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	failures := 0
+	for _, r := range results {
+		if failed(r) {
+			failures++
+		}
+	}
+	res, err := tx.Exec(`INSERT INTO runs (started_at, finished_at, total, failed) VALUES (?, ?, ?, ?)`,
+		started.UTC().Format(time.RFC3339Nano), finished.UTC().Format(time.RFC3339Nano), len(results), failures)
+	if err != nil {
+		return err
+	}
+	runID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare(`INSERT INTO results (run_id, url, ok, status, error, error_kind, attempts, duration_ms) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, r := range results {
+		if r.SkipReason != "" {
+			continue
+		}
+		if _, err := stmt.Exec(runID, r.URL, r.OK, r.Status, r.Error, string(r.ErrorKind), r.Attempts, float64(r.Duration)/float64(time.Millisecond)); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func saveHistory(path string, started time.Time, results []urlcheck.Result) error {
+	db, err := openHistory(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return recordRun(db, started, time.Now(), results)
+}
+
+func urlHistory(db *sql.DB, url string, limit int) ([]historyRow, error) {
+	rows, err := db.Query(`SELECT r.run_id, runs.started_at, r.ok, r.status, r.error, r.duration_ms
+		FROM results r JOIN runs ON runs.id = r.run_id
+		WHERE r.url = ? ORDER BY r.run_id DESC LIMIT ?`, url, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []historyRow
+	for rows.Next() {
+		var h historyRow
+		var started string
+		var ms float64
+		if err := rows.Scan(&h.runID, &started, &h.ok, &h.status, &h.err, &ms); err != nil {
+			return nil, err
+		}
+		h.startedAt, _ = time.Parse(time.RFC3339Nano, started)
+		h.duration = time.Duration(ms * float64(time.Millisecond))
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
+
+func flakyURLs(db *sql.DB, lastRuns int) ([]flakyURL, error) {
+	rows, err := db.Query(`SELECT url, COUNT(*), SUM(CASE WHEN ok THEN 0 ELSE 1 END) AS failures
+		FROM results WHERE run_id IN (SELECT id FROM runs ORDER BY id DESC LIMIT ?)
+		GROUP BY url HAVING failures > 0 AND failures < COUNT(*)
+		ORDER BY failures DESC, url`, lastRuns)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []flakyURL
+	for rows.Next() {
+		var f flakyURL
+		if err := rows.Scan(&f.url, &f.runs, &f.failures); err != nil {
+			return nil, err
+		}
+		out = append(out, f)
+	}
+	return out, rows.Err()
+}
+
+func recentFailures(db *sql.DB, lastRuns int) (map[string]int, error) {
+	rows, err := db.Query(`SELECT url, SUM(CASE WHEN ok THEN 0 ELSE 1 END)
+		FROM results WHERE run_id IN (SELECT id FROM runs ORDER BY id DESC LIMIT ?)
+		GROUP BY url`, lastRuns)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := map[string]int{}
+	for rows.Next() {
+		var url string
+		var failures int
+		if err := rows.Scan(&url, &failures); err != nil {
+			return nil, err
+		}
+		out[url] = failures
+	}
+	return out, rows.Err()
+}
+
+func runHistory(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	dbPath := fs.String("db", "", "history database written by -history")
+	url := fs.String("url", "", "show the recorded results for this url")
+	flaky := fs.Bool("flaky", false, "list urls that both passed and failed within the last -limit runs")
+	limit := fs.Int("limit", 20, "number of runs to look back")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *dbPath == "" || (*url == "") == !*flaky {
+		return fmt.Errorf("usage: urlcheck history -db results.db (-url URL | -flaky) [-limit N]")
+	}
+	db, err := openHistory(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if *flaky {
+		found, err := flakyURLs(db, *limit)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "URL\tRUNS\tFAILURES")
+		for _, f := range found {
+			fmt.Fprintf(w, "%s\t%d\t%d\n", f.url, f.runs, f.failures)
+		}
+		return w.Flush()
+	}
+	rows, err := urlHistory(db, *url, *limit)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "RUN\tSTARTED\tSTATUS\tOK\tDURATION\tERROR")
+	for _, h := range rows {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%s\t%s\t%s\n", h.runID, h.startedAt.Format(time.RFC3339), h.status,
+			strconv.FormatBool(h.ok), h.duration.Round(time.Millisecond), h.err)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestHistoryRecordsRunsAndFindsFlakyURLs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.db")
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	runs := [][]urlcheck.Result{
+		{{URL: "https://stable.example", OK: true, Status: 200}, {URL: "https://flaky.example", OK: true, Status: 200}},
+		{{URL: "https://stable.example", OK: true, Status: 200}, {URL: "https://flaky.example", Status: 503, Error: "busy"}, {URL: "https://skip.example", SkipReason: "excluded"}},
+	}
+	for i, results := range runs {
+		if err := saveHistory(path, start.Add(time.Duration(i)*time.Hour), results); err != nil {
+			t.Fatalf("saveHistory: %v", err)
+		}
+	}
+	db, err := openHistory(path)
+	if err != nil {
+		t.Fatalf("openHistory: %v", err)
+	}
+	defer db.Close()
+	rows, err := urlHistory(db, "https://flaky.example", 10)
+	if err != nil {
+		t.Fatalf("urlHistory: %v", err)
+	}
+	if len(rows) != 2 || rows[0].status != 503 || rows[0].err != "busy" || !rows[1].ok || !rows[1].startedAt.Equal(start) {
+		t.Fatalf("unexpected history: %+v", rows)
+	}
+	flaky, err := flakyURLs(db, 10)
+	if err != nil {
+		t.Fatalf("flakyURLs: %v", err)
+	}
+	if len(flaky) != 1 || flaky[0].url != "https://flaky.example" || flaky[0].runs != 2 || flaky[0].failures != 1 {
+		t.Fatalf("unexpected flaky urls: %+v", flaky)
+	}
+	var out bytes.Buffer
+	if err := runHistory([]string{"-db", path, "-url", "https://flaky.example"}, &out); err != nil {
+		t.Fatalf("runHistory: %v", err)
+	}
+	if !strings.Contains(out.String(), "503") || !strings.Contains(out.String(), "2026-01-02T03:04:05Z") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+	if err := runHistory([]string{"-db", path}, &out); err == nil {
+		t.Fatal("expected usage error without -url or -flaky")
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+type htmlReport struct {
+	Generated string            `json:"generated"`
+	Results   []urlcheck.Result `json:"results"`
+}
+
+func writeHTML(out io.Writer, results []urlcheck.Result) error {
+	if results == nil {
+		results = []urlcheck.Result{}
+	}
+	return htmlTemplate.Execute(out, htmlReport{Generated: time.Now().UTC().Format(time.RFC3339), Results: results})
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>urlcheck report</title>
+<style>
+body{font-family:system-ui,sans-serif;margin:2em;color:#222}
+table{border-collapse:collapse;width:100%;margin-bottom:2em}
+th,td{border:1px solid #ddd;padding:4px 8px;text-align:left;font-size:14px}
+th{background:#f4f4f4;cursor:pointer;user-select:none}
+tr.failed td{background:#fdecea}
+tr.skipped td{color:#888}
+#stats span{display:inline-block;margin-right:2em;font-size:18px}
+input,select{margin:0 1em 1em 0;padding:4px}
+</style>
+</head>
+<body>
+<h1>urlcheck report</h1>
+<p id="generated"></p>
+<div id="stats"></div>
+<h2>Domains</h2>
+<table id="domains"><thead><tr><th data-key="host">Host</th><th data-key="total">URLs</th><th data-key="failed">Failed</th><th data-key="rate">Failure %</th><th data-key="avg">Avg (ms)</th><th data-key="top">Top errors</th></tr></thead><tbody></tbody></table>
+<h2>Results</h2>
+<input id="filter" type="search" placeholder="Filter urls or errors">
+<select id="state"><option value="">all</option><option value="failed">failed</option><option value="ok">ok</option><option value="skipped">skipped</option></select>
+<table id="results"><thead><tr>
+<th data-key="url">URL</th><th data-key="status">Status</th><th data-key="state">State</th><th data-key="attempts">Attempts</th><th data-key="ms">Duration (ms)</th><th data-key="error">Error</th><th data-key="labels">Labels</th>
+</tr></thead><tbody></tbody></table>
+<script type="application/json" id="report-data">{{.}}</script>
+<script>
+(function(){
+  var report = JSON.parse(document.getElementById("report-data").textContent);
+  var rows = report.results.map(function(r){
+    var state = r.skip_reason ? "skipped" : (r.ok ? "ok" : "failed");
+    var host = "";
+    try { host = new URL(r.requested_url || r.url).hostname; } catch (e) {}
+    return {url: r.url, host: host, status: r.status, state: state, attempts: r.attempts,
+      ms: Math.round((r.duration || 0) / 1e6), error: r.skip_reason ? "skipped: " + r.skip_reason : (r.error || ""),
+      labels: Object.keys(r.labels || {}).sort().map(function(k){ return k + "=" + r.labels[k]; }).join(", "),
+      archive: r.archive_url || "", cause: r.error_kind || (r.status ? "http_" + r.status : "other")};
+  });
+  document.getElementById("generated").textContent = "Generated " + report.generated;
+  var counts = {total: rows.length, ok: 0, failed: 0, skipped: 0}, domains = {};
+  rows.forEach(function(r){
+    counts[r.state]++;
+    var d = domains[r.host] || (domains[r.host] = {host: r.host, total: 0, failed: 0, checked: 0, ms: 0, causes: {}});
+    d.total++;
+    if (r.state === "skipped") return;
+    d.checked++;
+    d.ms += r.ms;
+    if (r.state === "failed") { d.failed++; d.causes[r.cause] = (d.causes[r.cause] || 0) + 1; }
+  });
+  Object.keys(domains).forEach(function(k){
+    var d = domains[k];
+    d.rate = d.checked ? Math.round(1000 * d.failed / d.checked) / 10 : 0;
+    d.avg = d.checked ? Math.round(d.ms / d.checked) : 0;
+    d.top = Object.keys(d.causes).sort(function(a, b){ return d.causes[b] - d.causes[a] || a.localeCompare(b); })
+      .slice(0, 3).map(function(c){ return c + " " + d.causes[c]; }).join(", ");
+  });
+  var stats = document.getElementById("stats");
+  ["total", "ok", "failed", "skipped"].forEach(function(k){
+    var span = document.createElement("span");
+    span.textContent = k.charAt(0).toUpperCase() + k.slice(1) + ": " + counts[k];
+    stats.appendChild(span);
+  });
+  var domainRows = Object.keys(domains).map(function(k){ return domains[k]; });
+  domainRows.sort(function(a, b){ return b.failed - a.failed || a.host.localeCompare(b.host); });
+  function cell(tr, text){ var td = document.createElement("td"); td.textContent = text; tr.appendChild(td); return td; }
+  function render(id, data, columns, decorate){
+    var body = document.querySelector("#" + id + " tbody");
+    body.textContent = "";
+    data.forEach(function(item){
+      var tr = document.createElement("tr");
+      columns.forEach(function(c){ cell(tr, item[c]); });
+      if (decorate) decorate(tr, item);
+      body.appendChild(tr);
+    });
+  }
+  function sortable(id, data, redraw){
+    document.querySelectorAll("#" + id + " th").forEach(function(th){
+      th.addEventListener("click", function(){
+        var key = th.dataset.key, asc = th.dataset.asc !== "true";
+        th.dataset.asc = asc;
+        data.sort(function(a, b){
+          var x = a[key], y = b[key];
+          var c = (typeof x === "number" && typeof y === "number") ? x - y : String(x).localeCompare(String(y));
+          return asc ? c : -c;
+        });
+        redraw();
+      });
+    });
+  }
+  var resultColumns = ["url", "status", "state", "attempts", "ms", "error", "labels"];
+  function drawResults(){
+    var q = document.getElementById("filter").value.toLowerCase();
+    var st = document.getElementById("state").value;
+    var visible = rows.filter(function(r){
+      return (!st || r.state === st) && (r.url + " " + r.error + " " + r.labels).toLowerCase().indexOf(q) >= 0;
+    });
+    render("results", visible, resultColumns, function(tr, r){
+      tr.className = r.state;
+      if (r.archive) {
+        var link = document.createElement("a");
+        link.href = r.archive;
+        link.textContent = "archived copy";
+        tr.children[5].append(" ", link);
+      }
+    });
+  }
+  function drawDomains(){ render("domains", domainRows, ["host", "total", "failed", "rate", "avg", "top"]); }
+  sortable("results", rows, drawResults);
+  sortable("domains", domainRows, drawDomains);
+  document.getElementById("filter").addEventListener("input", drawResults);
+  document.getElementById("state").addEventListener("change", drawResults);
+  drawDomains();
+  drawResults();
+})();
+</script>
+</body>
+</html>
+`))
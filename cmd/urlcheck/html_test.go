@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestWriteHTMLEmbedsResultJSON(t *testing.T) {
+	results := []urlcheck.Result{
+		{URL: "https://a.example/ok", OK: true, Status: 200},
+		{URL: "https://a.example/</script><b>", Status: 500, Error: "boom"},
+		{URL: "https://b.example/", SkipReason: "excluded"},
+	}
+	var buf bytes.Buffer
+	if err := writeHTML(&buf, results); err != nil {
+		t.Fatalf("writeHTML: %v", err)
+	}
+	out := buf.String()
+	if strings.Contains(out, "</script><b>") {
+		t.Fatalf("expected embedded data to be escaped")
+	}
+	const open = `<script type="application/json" id="report-data">`
+	start := strings.Index(out, open)
+	if start < 0 {
+		t.Fatalf("missing embedded report data")
+	}
+	data := out[start+len(open):]
+	data = data[:strings.Index(data, "</script>")]
+	var report htmlReport
+	if err := json.Unmarshal([]byte(data), &report); err != nil {
+		t.Fatalf("embedded data is not json: %v\n%s", err, data)
+	}
+	if len(report.Results) != 3 || report.Results[1].URL != "https://a.example/</script><b>" {
+		t.Fatalf("unexpected embedded results: %+v", report.Results)
+	}
+	if strings.Contains(out, "<link ") || strings.Contains(out, " src=") {
+		t.Fatalf("report must not reference external assets")
+	}
+}
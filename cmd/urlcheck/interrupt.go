@@ -0,0 +1,24 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+const interruptGrace = 3 * time.Second
+
+func interruptOnSignal() <-chan struct{} {
+	stop := make(chan struct{})
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sig
+		signal.Stop(sig)
+		slog.Warn("interrupted: finishing in-flight checks, press ctrl-c again to abort", "grace", interruptGrace)
+		close(stop)
+	}()
+	return stop
+}
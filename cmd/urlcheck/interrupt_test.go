@@ -0,0 +1,21 @@
+//go:build unix
+
+package main
+
+import (
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestInterruptOnSignal(t *testing.T) {
+	stop := interruptOnSignal()
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGINT); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-stop:
+	case <-time.After(2 * time.Second):
+		t.Fatal("stop channel was not closed on SIGINT")
+	}
+}
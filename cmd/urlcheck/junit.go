@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name       string           `xml:"name,attr"`
+	ClassName  string           `xml:"classname,attr"`
+	Time       string           `xml:"time,attr"`
+	Properties *junitProperties `xml:"properties,omitempty"`
+	Failure    *junitFailure    `xml:"failure,omitempty"`
+	Skipped    *junitSkipped    `xml:"skipped,omitempty"`
+}
+
+type junitProperties struct {
+	Properties []junitProperty `xml:"property"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+func writeJUnit(out io.Writer, results []urlcheck.Result) error {
+	suite := junitSuite{Name: "urlcheck", Tests: len(results)}
+	var total float64
+	for _, r := range results {
+		total += r.Duration.Seconds()
+		tc := junitCase{
+			Name:      r.URL,
+			ClassName: hostOf(r.URL),
+			Time:      strconv.FormatFloat(r.Duration.Seconds(), 'f', 3, 64),
+		}
+		if len(r.Labels) > 0 {
+			tc.Properties = &junitProperties{}
+			for _, k := range slices.Sorted(maps.Keys(r.Labels)) {
+				tc.Properties.Properties = append(tc.Properties.Properties, junitProperty{Name: k, Value: r.Labels[k]})
+			}
+		}
+		switch {
+		case r.SkipReason != "":
+			suite.Skipped++
+			tc.Skipped = &junitSkipped{Message: r.SkipReason}
+		case !r.OK:
+			suite.Failures++
+			tc.Failure = &junitFailure{
+				Message: failureMessage(r),
+				Type:    string(r.ErrorKind),
+				Text:    fmt.Sprintf("status=%d attempts=%d\n%s", r.Status, r.Attempts, r.Error),
+			}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Time = strconv.FormatFloat(total, 'f', 3, 64)
+	if _, err := io.WriteString(out, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(out)
+	enc.Indent("", "  ")
+	if err := enc.Encode(junitSuites{Suites: []junitSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(out, "\n")
+	return err
+}
+
+func failureMessage(r urlcheck.Result) string {
+	if r.Error != "" {
+		return r.Error
+	}
+	return fmt.Sprintf("unexpected status %d", r.Status)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	results := []urlcheck.Result{
+		{URL: "https://ok.example/", OK: true, Status: 200, Attempts: 1},
+		{URL: "https://bad.example/", Status: 404, Attempts: 1},
+		{URL: "https://skip.example/", SkipReason: "excluded"},
+	}
+	var buf bytes.Buffer
+	if err := writeJUnit(&buf, results); err != nil {
+		t.Fatalf("writeJUnit: %v", err)
+	}
+	var parsed junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("parse junit: %v\n%s", err, buf.String())
+	}
+	suite := parsed.Suites[0]
+	if suite.Tests != 3 || suite.Failures != 1 || suite.Skipped != 1 {
+		t.Fatalf("unexpected suite counts: %+v", suite)
+	}
+	if suite.Cases[1].Failure == nil || suite.Cases[1].Failure.Message != "unexpected status 404" {
+		t.Fatalf("unexpected failure: %+v", suite.Cases[1].Failure)
+	}
+	if suite.Cases[0].ClassName != "ok.example" {
+		t.Fatalf("unexpected classname: %q", suite.Cases[0].ClassName)
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNewLoggerLevels(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := newLogger(&buf, "warn", "text", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("hidden")
+	logger.Warn("shown", "url", "https://example.com")
+	if out := buf.String(); strings.Contains(out, "hidden") || out != "level=WARN msg=shown url=https://example.com\n" {
+		t.Fatalf("unexpected text log %q", out)
+	}
+	buf.Reset()
+	logger, err = newLogger(&buf, "error", "json", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Debug("attempt", "attempt", 2)
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil || entry["msg"] != "attempt" || entry["attempt"] != 2.0 {
+		t.Fatalf("expected -v to enable debug json logs, got %q (%v)", buf.String(), err)
+	}
+}
+
+func TestNewLoggerRejectsInvalid(t *testing.T) {
+	if _, err := newLogger(&bytes.Buffer{}, "loud", "text", false); err == nil {
+		t.Fatal("expected error for unknown level")
+	}
+	if _, err := newLogger(&bytes.Buffer{}, "info", "xml", false); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+}
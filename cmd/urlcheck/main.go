@@ -2,17 +2,27 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"context"
-	"encoding/json"
-	"flag"
 	"fmt"
 	"io"
+	"log/slog"
+	"maps"
+	"math/rand/v2"
+	"net"
+	"net/http"
 	"os"
+	"regexp"
+	"slices"
 	"strings"
-	"text/tabwriter"
+	"sync"
 	"time"
 
+	"github.com/reisei231/go-url-checker/internal/render"
 	"github.com/reisei231/go-url-checker/internal/urlcheck"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
 )
 
 type config struct {
@@ -21,129 +31,1148 @@ type config struct {
 	timeout     time.Duration
 	retries     int
 	asJSON      bool
+	format      string
+	forbid      stringList
+	forbidFor   stringList
+	dedupe      bool
+	misconfig   bool
+	parked      bool
+	wayback     bool
+	emitCurl    bool
+	checkType   string
+	graphQuery  string
+	sshKey      string
+	knownHosts  string
+	allowFile   bool
+	bucketAuth  bool
+	includeDom  stringList
+	excludeDom  stringList
+	method      string
+	bodyFile    string
+	bodySize    int
+	expect      time.Duration
+	match       stringList
+	exclude     stringList
+	sample      string
+	shard       string
+	sampleN     int
+	seed        uint64
+	samplePer   int
+	budget      time.Duration
+	maxDuration time.Duration
+	delay       time.Duration
+	delayJitter time.Duration
+	transport   urlcheck.TransportLimits
+	dial        urlcheck.DialerConfig
+	noKeepAlive bool
+	outputs     stringList
+	scan        stringList
+	fix         bool
+	fixDryRun   bool
+	vhostAudit  bool
+	report      string
+	har         string
+	template    string
+	serve       string
+	color       string
+	verifyNX    string
+	reverify    bool
+	reverifyTO  time.Duration
+	noProgress  bool
+	postProcess stringList
+	quiet       bool
+	onlyFails   bool
+	bundle      string
+	fingerprint bool
+	render      bool
+	renderTO    time.Duration
+	renderWait  time.Duration
+	chrome      string
+	hostStats   bool
+	agents      stringList
+	agentBatch  int
+	agentToken  string
+	canary      string
+	compareHdr  stringList
+	minSimilar  float64
+	groupBy     string
+	sortBy      string
+	progressFmt string
+	outDir      string
+	noSplit     bool
+	sitemaps    stringList
+	ckptEvery   int
+	ckptPeriod  time.Duration
+	ckptFile    string
+	resume      bool
+	cacheDir    string
+	cacheTTL    time.Duration
+	cacheKey    string
+	noCache     bool
+	dryRun      bool
+	saveFails   string
+	acceptEnc   string
+	httpsProbe  bool
+	certWarn    string
+	warnErrors  bool
+	retryBudget int
+	pprof       string
+	debugStats  time.Duration
+	stream      bool
+	follow      bool
+	print       string
+	outFile     string
+	exitZero    bool
+	maxFailures int
+	maxFailRate string
+	maxLatency  time.Duration
+	slowMode    string
+	auditSec    bool
+	auditMode   string
+	mixed       bool
+	mixedMode   string
+	canonical   bool
+	canonMode   string
+	maxRedirect int
+	assertFile  string
+	baseline    string
+	updateBase  bool
+	diff        bool
+	state       string
+	history     string
+	watch       bool
+	bench       bool
+	benchRuns   int
+	interval    time.Duration
+	schedule    string
+	chdir       string
+	otlp        string
+	verbose     bool
+	logLevel    string
+	logFormat   string
+	webhook     string
+	webhookTmpl string
+	slackHook   string
+	slackToken  string
+	slackChan   string
+	reportURL   string
+	smtp        string
+	smtpUser    string
+	emailFrom   string
+	emailTo     stringList
+	emailAttach string
+	configFile  string
+	profile     string
+	args        []string
+	hosts       []urlcheck.HostOverride
+	assertList  []urlcheck.Assertion
+	modules     map[string]*loadedModule
+	modChecks   []urlcheck.Assertion
+	effective   map[string]string
+	schema      bool
+	shutdown    <-chan struct{}
+	live        *liveness
+	priority    string
+	webhooks    []webhookTarget
+}
+
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(v string) error {
+	*s = append(*s, v)
+	return nil
 }
 
 func main() {
-	cfg := parseFlags()
-	urls, err := loadURLs(cfg.file, os.Stdin)
+	if logger, err := newLogger(os.Stderr, "info", "text", false); err == nil {
+		slog.SetDefault(logger)
+	}
+	if len(os.Args) > 1 && os.Args[1] == "history" {
+		if err := runHistory(os.Args[2:], os.Stdout); err != nil {
+			fatal("history error", "error", err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "uptime" {
+		if err := runUptime(os.Args[2:], os.Stdout, time.Now()); err != nil {
+			fatal("uptime error", "error", err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "report" {
+		if err := runReport(os.Args[2:], os.Stdout, time.Now()); err != nil {
+			fatal("report error", "error", err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "service" {
+		if err := runService(os.Args[2:]); err != nil {
+			fatal("service error", "error", err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		if err := runServe(os.Args[2:]); err != nil {
+			fatal("serve error", "error", err)
+		}
+		return
+	}
+	command, args := "", os.Args[1:]
+	if len(args) > 0 {
+		if _, ok := commands[args[0]]; ok {
+			command, args = args[0], args[1:]
+		}
+	}
+	cfg := parseFlags(command, args)
+	if cfg.schema {
+		if err := writeOutputSchema(os.Stdout); err != nil {
+			fatal("schema error", "error", err)
+		}
+		return
+	}
+	logger, err := newLogger(os.Stderr, cfg.logLevel, cfg.logFormat, cfg.verbose)
+	if err != nil {
+		fatal("config error", "error", err)
+	}
+	slog.SetDefault(logger)
+	if cfg.template != "" {
+		write, err := templateFormatter(cfg.template)
+		if err != nil {
+			fatal("config error", "flag", "-template", "error", err)
+		}
+		formats["template"] = write
+	}
+	if _, ok := formats[cfg.format]; !ok {
+		fatal("config error", "error", fmt.Sprintf("unknown format %q (want %s)", cfg.format, formatNames()))
+	}
+	if cfg.priority != "" && (cfg.canary != "" || cfg.bench || cfg.stream) {
+		fatal("config error", "flag", "-priority", "error", "not supported with -canary, -bench or -stream")
+	}
+	if len(cfg.agents) > 0 {
+		if dropped := agentDroppedSettings(cfg); len(dropped) > 0 {
+			fatal("config error", "flag", "-agent", "error", "agents check with their own settings; drop "+strings.Join(dropped, ", ")+" or set them on the agents")
+		}
+	}
+	if cfg.canary != "" {
+		code, err := runCanary(cfg)
+		if err != nil {
+			fatal("canary error", "error", err)
+		}
+		os.Exit(code)
+	}
+	if cfg.bench {
+		if err := runBench(cfg, os.Stdin, os.Stdout); err != nil {
+			fatal("bench error", "error", err)
+		}
+		return
+	}
+	if cfg.watch {
+		color, err := useColor(cfg.color, os.Stdout, os.Getenv)
+		var inService bool
+		if err == nil {
+			inService, err = runWindowsService(cfg, color)
+		}
+		if err == nil && !inService {
+			cfg.shutdown = shutdownOnSignal()
+			err = runWatch(cfg, color)
+		}
+		if err != nil {
+			fatal("watch error", "error", err)
+		}
+		return
+	}
+	if cfg.stream {
+		code, err := runStream(cfg, os.Stdin, os.Stdout)
+		if err != nil {
+			fatal("stream error", "error", err)
+		}
+		os.Exit(code)
+	}
+	urls, prov, err := loadInputs(cfg, os.Stdin)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "input error: %v\n", err)
-		os.Exit(1)
+		fatal("input error", "error", err)
 	}
 	if len(urls) == 0 {
-		fmt.Fprintln(os.Stderr, "no urls provided")
-		os.Exit(1)
+		fatal("no urls provided")
+	}
+	filter, err := newURLFilter(cfg)
+	if err != nil {
+		fatal("config error", "error", err)
+	}
+	urls, skipped := filter.apply(urls)
+	sample, err := newSampling(cfg)
+	if err != nil {
+		fatal("config error", "error", err)
+	}
+	population := len(urls)
+	if sample.enabled() {
+		urls = sample.apply(urls)
+	}
+	all := urls
+	var done map[string]urlcheck.Result
+	if cfg.resume {
+		if cfg.ckptFile == "" {
+			fatal("config error", "error", "-resume requires -checkpoint")
+		}
+		done, err = loadCheckpoint(cfg.ckptFile)
+		if err != nil {
+			fatal("config error", "flag", "-checkpoint", "error", err)
+		}
+		urls = pendingURLs(urls, done)
+		slog.Info("resuming from checkpoint", "done", len(all)-len(urls), "pending", len(urls))
+	}
+	cache := resultCache{dir: cfg.cacheDir, ttl: cfg.cacheTTL, fingerprint: cfg.cacheKey}
+	if cfg.cacheDir != "" && !cfg.noCache {
+		cached := cache.load(urls, time.Now())
+		if done == nil {
+			done = map[string]urlcheck.Result{}
+		}
+		maps.Copy(done, cached)
+		urls = pendingURLs(urls, cached)
+		slog.Info("using cached results", "cached", len(cached), "pending", len(urls))
+	}
+	opts, closeOptions, err := checkerOptions(cfg)
+	if err != nil {
+		fatal("config error", "error", err)
+	}
+	defer closeOptions()
+	if cfg.dryRun {
+		plan := urlcheck.NewChecker(cfg.concurrency, cfg.timeout, cfg.retries, nil, opts...).Plan(urls)
+		if err := writeDryRun(os.Stdout, plan, skipped, cfg.format == "json"); err != nil {
+			fatal("output error", "error", err)
+		}
+		return
+	}
+	opts = append(opts, urlcheck.WithLogger(slog.Default()))
+	notifiers, err := notifiersFor(cfg)
+	if err != nil {
+		fatal("config error", "error", err)
+	}
+	less, err := parseSort(cfg.sortBy)
+	if err != nil {
+		fatal("config error", "error", err)
+	}
+	policy, err := newExitPolicy(cfg)
+	if err != nil {
+		fatal("config error", "error", err)
+	}
+	color, err := useColor(cfg.color, os.Stdout, os.Getenv)
+	if err != nil {
+		fatal("config error", "error", err)
 	}
-	checker := urlcheck.NewChecker(cfg.concurrency, cfg.timeout, cfg.retries, nil)
-	results, err := checker.Check(context.Background(), urls)
+	startedAt := time.Now()
+	formats["json"] = jsonFormatter(runMeta{Started: startedAt, ToolVersion: toolVersion(), Config: cfg.effective}, cfg.hostStats)
+	var stdout io.Writer = os.Stdout
+	if cfg.quiet {
+		stdout = io.Discard
+	}
+	var printer *printSink
+	if cfg.print != "" {
+		printer, err = newPrintSink(cfg.print, stdout)
+		if err != nil {
+			fatal("config error", "error", err)
+		}
+		stdout = io.Discard
+	}
+	sinks, err := newSinkSet(cfg.outputs, cfg.format, stdout, color)
+	if err != nil {
+		fatal("output error", "error", err)
+	}
+	if printer != nil {
+		sinks.sinks = append(sinks.sinks, printer)
+	}
+	if cfg.onlyFails {
+		sinks.keep = failed
+	}
+	switch cfg.groupBy {
+	case "":
+	case "domain":
+		sinks.wrapRender("table", groupByDomain)
+	default:
+		fatal("config error", "error", fmt.Sprintf("unknown -group-by %q (want domain)", cfg.groupBy))
+	}
+	var har *harRecorder
+	if cfg.har != "" {
+		har = &harRecorder{}
+		opts = append(opts, urlcheck.WithTransport(har.wrap))
+	}
+	var failures *failureRecorder
+	if cfg.saveFails != "" {
+		failures = &failureRecorder{}
+		opts = append(opts, urlcheck.WithTransport(failures.wrap))
+	}
+	procs, err := postProcessors(cfg.postProcess)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "check error: %v\n", err)
-		os.Exit(1)
+		fatal("config error", "error", err)
 	}
-	if err := writeOutputs(results, cfg.asJSON); err != nil {
-		fmt.Fprintf(os.Stderr, "output error: %v\n", err)
-		os.Exit(1)
+	if (cfg.fix || cfg.fixDryRun) && len(cfg.scan) == 0 {
+		fatal("config error", "error", "-fix requires -scan or the scan command")
+	}
+	if cfg.updateBase && cfg.baseline == "" {
+		fatal("config error", "error", "-update-baseline requires -baseline")
+	}
+	if cfg.diff {
+		if statePath(cfg) == "" {
+			fatal("config error", "error", "-diff needs -state when -no-split is set")
+		}
+		diff, err := loadRunDiff(statePath(cfg))
+		if err != nil {
+			fatal("config error", "flag", "-state", "error", err)
+		}
+		procs = append([]urlcheck.Processor{diff}, procs...)
+	}
+	if cfg.baseline != "" {
+		base, err := loadBaseline(cfg.baseline, cfg.updateBase)
+		if err != nil {
+			fatal("config error", "flag", "-baseline", "error", err)
+		}
+		procs = append([]urlcheck.Processor{base}, procs...)
+	}
+	stream := !cfg.reverify && len(procs) == 0
+	var prog progressReporter
+	switch {
+	case cfg.noProgress || cfg.quiet:
+	case cfg.progressFmt == "json":
+		prog = newJSONProgress(os.Stderr, len(urls))
+		opts = append(opts, urlcheck.WithOnRetry(prog.retrying))
+	case cfg.progressFmt != "text":
+		fatal("config error", "error", fmt.Sprintf("unknown -progress-format %q (want text|json)", cfg.progressFmt))
+	case isTerminal(os.Stderr):
+		prog = newProgress(os.Stderr, len(urls))
+	}
+	var ckpt *checkpointer
+	if cfg.ckptEvery > 0 || cfg.ckptPeriod > 0 {
+		ckpt = newCheckpointer(os.Stderr, cfg.progressFmt == "json", len(urls), cfg.ckptEvery)
+		if cfg.ckptPeriod > 0 {
+			ckpt.tick(cfg.ckptPeriod)
+		}
 	}
+	var journal *checkpointFile
+	if cfg.ckptFile != "" {
+		journal, err = openCheckpoint(cfg.ckptFile, cfg.resume)
+		if err != nil {
+			fatal("config error", "flag", "-checkpoint", "error", err)
+		}
+	}
+	onResult := func(r urlcheck.Result) {
+		if prog != nil {
+			prog.update(r)
+		}
+		if ckpt != nil {
+			ckpt.update(r)
+		}
+		if journal != nil {
+			journal.record(r)
+		}
+		if failures != nil {
+			failures.forget(r)
+		}
+		if stream {
+			sinks.write(prov.attribute(r))
+		}
+	}
+	opts = append(opts, urlcheck.WithOnResult(onResult))
+	rank, err := parsePriority(cfg.priority, func(u string) map[string]string { return prov[u].Labels }, cfg.history)
+	if err != nil {
+		fatal("config error", "flag", "-priority", "error", err)
+	}
+	if rank != nil {
+		opts = append(opts, urlcheck.WithPriority(rank))
+	}
+	var tracer trace.Tracer
+	shutdownTracing := func() {}
+	if cfg.otlp != "" {
+		tracer, shutdownTracing, err = newTracing(cfg.otlp)
+		if err != nil {
+			fatal("config error", "flag", "-otlp-endpoint", "error", err)
+		}
+		opts = append(opts, urlcheck.WithTracer(tracer))
+	}
+	stop := interruptOnSignal()
+	opts = append(opts, urlcheck.WithGracefulStop(stop, interruptGrace))
+	ctx, endRun := startRunSpan(context.Background(), tracer, len(urls))
+	checker := urlcheck.NewChecker(cfg.concurrency, cfg.timeout, cfg.retries, nil, opts...)
+	defer pauseOnSignals(checker)()
+	if cfg.pprof != "" {
+		lis, err := startPprof(cfg.pprof)
+		if err != nil {
+			fatal("config error", "flag", "-pprof", "error", err)
+		}
+		defer lis.Close()
+		slog.Info("pprof listening", "addr", "http://"+lis.Addr().String()+"/debug/pprof/")
+	}
+	if cfg.debugStats > 0 {
+		defer debugStats(slog.Default(), checker, len(urls), cfg.debugStats)()
+	}
+	if stream {
+		for _, u := range all {
+			if r, ok := done[u]; ok {
+				sinks.write(prov.attribute(r))
+			}
+		}
+	}
+	var results []urlcheck.Result
+	if len(cfg.agents) > 0 {
+		agents, closeAgents, aerr := openAgents(cfg.agents, agentTimeout(cfg.timeout, cfg.agentBatch), cfg.agentToken)
+		if aerr != nil {
+			fatal("config error", "flag", "-agent", "error", aerr)
+		}
+		// Agents check their batches in order, so rank before sharding.
+		order := urlcheck.PriorityOrder(urls, rank)
+		ranked := make([]string, len(urls))
+		for i, j := range order {
+			ranked[i] = urls[j]
+		}
+		got := checkWithAgents(ctx, stop, agents, ranked, cfg.agentBatch, onResult)
+		results = make([]urlcheck.Result, len(urls))
+		for i, j := range order {
+			results[j] = got[i]
+		}
+		closeAgents()
+	} else {
+		results, err = checker.Check(ctx, urls)
+	}
+	if journal != nil {
+		if err := journal.close(); err != nil {
+			slog.Warn("checkpoint error", "error", err)
+		}
+	}
+	results = mergeResumed(all, done, results)
+	if ckpt != nil {
+		ckpt.finish()
+	}
+	if prog != nil {
+		prog.finish(urlcheck.Summarize(results, time.Since(startedAt)))
+	}
+	if err != nil {
+		fatal("check error", "error", err)
+	}
+	if cfg.reverify {
+		timeout := cfg.reverifyTO
+		if timeout <= 0 {
+			timeout = 2 * cfg.timeout
+		}
+		results, err = checker.Reverify(ctx, results, timeout, 1)
+		if err != nil {
+			fatal("check error", "error", err)
+		}
+	}
+	// The rest of main leaves through os.Exit, which skips deferred calls.
+	closeOptions()
+	endRun(results)
+	shutdownTracing()
+	if cfg.cacheDir != "" {
+		if err := cache.store(results, time.Now()); err != nil {
+			slog.Warn("cache error", "error", err)
+		}
+	}
+	for i := range results {
+		results[i] = prov.attribute(results[i])
+	}
+	for _, r := range skipped {
+		r = prov.attribute(r)
+		if stream {
+			sinks.write(r)
+		}
+		results = append(results, r)
+	}
+	if cfg.history != "" {
+		if err := saveHistory(cfg.history, startedAt, results); err != nil {
+			slog.Warn("history error", "error", err)
+		}
+	}
+	if path := statePath(cfg); path != "" {
+		if err := saveState(path, results); err != nil {
+			slog.Warn("state error", "error", err)
+		}
+	}
+	if len(procs) > 0 {
+		results, err = urlcheck.PostProcess(context.Background(), results, procs...)
+		if err != nil {
+			fatal("post-process error", "error", err)
+		}
+	}
+	if less != nil {
+		sortResults(results, less)
+	}
+	if !stream {
+		for _, r := range results {
+			sinks.write(r)
+		}
+	}
+	if err := writeOutputs(results, sinks, cfg.outDir); err != nil {
+		fatal("output error", "error", err)
+	}
+	if har != nil {
+		if err := har.writeFile(cfg.har); err != nil {
+			fatal("har error", "error", err)
+		}
+	}
+	if failures != nil {
+		n, err := failures.save(cfg.saveFails, results)
+		if err != nil {
+			fatal("save failures error", "error", err)
+		}
+		slog.Info("saved failed responses", "count", n, "dir", cfg.saveFails)
+	}
+	if cfg.fix || cfg.fixDryRun {
+		n, err := fixLinks(cfg.scan, results, cfg.fixDryRun, os.Stderr)
+		if err != nil {
+			fatal("fix error", "error", err)
+		}
+		slog.Info("rewrote moved links", "count", n, "dry_run", cfg.fixDryRun)
+	}
+	if cfg.bundle != "" {
+		manifest := bundleManifest{Started: startedAt, Finished: time.Now(), Args: os.Args[1:]}
+		if err := writeBundle(cfg.bundle, manifest, results, bundleSources(cfg)); err != nil {
+			fatal("bundle error", "error", err)
+		}
+	}
+	if err := appendStepSummary(os.Getenv, results, outputArtifacts(cfg.outDir, cfg.outputs)); err != nil {
+		slog.Warn("step summary error", "error", err)
+	}
+	code := exitCode(results, policy)
+	if code != exitOK {
+		n := newNotification(eventFailures, results, time.Since(startedAt), nil)
+		if err := notifyAll(context.Background(), notifiers, n); err != nil {
+			slog.Warn("notify error", "error", err)
+		}
+	}
+	if cfg.quiet {
+		os.Exit(code)
+	}
+	if cfg.budget > 0 {
+		attempted, total := urlcheck.Coverage(results)
+		fmt.Fprintf(os.Stderr, "budget: checked %d of %d urls (%.1f%% coverage)\n", attempted, total, percent(attempted, total))
+	}
+	if sample.enabled() {
+		writeSampleSummary(os.Stderr, sample, population, results)
+	}
+	tableOut := cfg.format == "table" && cfg.print == ""
+	extras := tableExtrasOut(cfg.outputs, cfg.format, os.Stdout, os.Stderr)
+	if tableOut {
+		stats := urlcheck.ComputeStats(results, time.Since(startedAt))
+		if err := writeSummary(extras, stats); err != nil {
+			fatal("output error", "error", err)
+		}
+		if cfg.hostStats {
+			if err := writeHostStats(extras, stats); err != nil {
+				fatal("output error", "error", err)
+			}
+		}
+	}
+	if cfg.dedupe && tableOut {
+		if err := writeRedirectGroups(extras, results); err != nil {
+			fatal("output error", "error", err)
+		}
+	}
+	if cfg.emitCurl && tableOut {
+		if err := writeCurlCommands(extras, results); err != nil {
+			fatal("output error", "error", err)
+		}
+	}
+	if cfg.fingerprint && tableOut {
+		if err := writeProviderRollup(extras, results); err != nil {
+			fatal("output error", "error", err)
+		}
+	}
+	if cfg.serve != "" {
+		store := newResultStore(0)
+		store.add(startedAt, results)
+		slog.Info("serving results", "addr", cfg.serve)
+		if err := http.ListenAndServe(cfg.serve, grafanaHandler(store)); err != nil {
+			fatal("serve error", "error", err)
+		}
+	}
+	os.Exit(code)
 }
 
-func parseFlags() config {
-	cfg := config{}
-	flag.StringVar(&cfg.file, "file", "", "path to file with urls, one per line (defaults to stdin)")
-	flag.IntVar(&cfg.concurrency, "concurrency", 5, "maximum concurrent checks")
-	flag.DurationVar(&cfg.timeout, "timeout", 5*time.Second, "per-request timeout")
-	flag.IntVar(&cfg.retries, "retries", 1, "retries on network errors")
-	flag.BoolVar(&cfg.asJSON, "json", false, "output as json instead of table")
-	flag.Parse()
-	if cfg.concurrency < 1 {
-		cfg.concurrency = 1
+// tableExtrasOut is where the summary footer and the other table extras go:
+// next to the table when it is printed on stdout, otherwise stderr, so they
+// neither go missing nor end up in the middle of another format on stdout.
+func tableExtrasOut(outputs []string, format string, stdout, stderr io.Writer) io.Writer {
+	if len(outputs) == 0 {
+		outputs = []string{format}
 	}
-	if cfg.timeout <= 0 {
-		cfg.timeout = 5 * time.Second
+	for _, spec := range outputs {
+		if name, dest, _ := strings.Cut(spec, "="); name == "table" && (dest == "" || dest == "-") {
+			return stdout
+		}
 	}
-	if cfg.retries < 0 {
-		cfg.retries = 0
+	return stderr
+}
+
+func outputSpecs(specs []string, format string) []string {
+	out := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		_, known := formats[spec]
+		if !strings.Contains(spec, "=") && !known && spec != "template" && spec != "statsd" {
+			spec = format + "=" + spec
+		}
+		out = append(out, spec)
 	}
-	return cfg
+	return out
 }
 
-func loadURLs(path string, stdin io.Reader) ([]string, error) {
-	var reader io.Reader
-	if path != "" {
-		f, err := os.Open(path)
+var checkTypes = []urlcheck.CheckType{urlcheck.CheckHTTP, urlcheck.CheckDNS, urlcheck.CheckTCP, urlcheck.CheckTLS, urlcheck.CheckGRPCHealth, urlcheck.CheckGraphQL}
+
+var acceptEncodings = []string{"gzip", "br", "deflate", "identity"}
+
+func checkTypeNames() string {
+	names := make([]string, len(checkTypes))
+	for i, kind := range checkTypes {
+		names[i] = string(kind)
+	}
+	return strings.Join(names, "|")
+}
+
+func sshAuth(keyFile, knownHostsFile string) (urlcheck.Option, error) {
+	var key ssh.Signer
+	if keyFile != "" {
+		pem, err := os.ReadFile(keyFile)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("reading -ssh-key: %w", err)
+		}
+		if key, err = ssh.ParsePrivateKey(pem); err != nil {
+			return nil, fmt.Errorf("parsing -ssh-key %s: %w", keyFile, err)
 		}
-		defer f.Close()
-		reader = f
-	} else {
-		reader = stdin
 	}
-	scanner := bufio.NewScanner(reader)
-	var urls []string
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
-			continue
+	var hostKeys ssh.HostKeyCallback
+	if knownHostsFile != "" {
+		var err error
+		if hostKeys, err = knownhosts.New(knownHostsFile); err != nil {
+			return nil, fmt.Errorf("reading -ssh-known-hosts: %w", err)
 		}
-		urls = append(urls, line)
 	}
-	if err := scanner.Err(); err != nil {
-		return nil, err
+	return urlcheck.WithSSHAuth(key, hostKeys), nil
+}
+
+// checkerOptions also returns a cleanup func that releases resources the
+// options hold, such as the headless browser behind -render. It is safe to call
+// more than once.
+func checkerOptions(cfg config) ([]urlcheck.Option, func(), error) {
+	var opts []urlcheck.Option
+	if cfg.checkType != "" {
+		kind := urlcheck.CheckType(cfg.checkType)
+		if !slices.Contains(checkTypes, kind) {
+			return nil, nil, fmt.Errorf("unknown -check %q (want %s)", cfg.checkType, checkTypeNames())
+		}
+		opts = append(opts, urlcheck.WithCheckType(kind))
 	}
-	return urls, nil
+	if cfg.graphQuery != "" {
+		opts = append(opts, urlcheck.WithGraphQLQuery(cfg.graphQuery))
+	}
+	if cfg.sshKey != "" || cfg.knownHosts != "" {
+		opt, err := sshAuth(cfg.sshKey, cfg.knownHosts)
+		if err != nil {
+			return nil, nil, err
+		}
+		opts = append(opts, opt)
+	}
+	if cfg.certWarn != "" && cfg.certWarn != "0" {
+		d, err := parseWindow(cfg.certWarn)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid -cert-expiry-warn %q (want e.g. 14d or 72h)", cfg.certWarn)
+		}
+		opts = append(opts, urlcheck.WithCertExpiryWarning(d))
+	}
+	if cfg.retryBudget > 0 {
+		opts = append(opts, urlcheck.WithRetryBudget(cfg.retryBudget))
+	}
+	if cfg.warnErrors {
+		opts = append(opts, urlcheck.WithWarningsAsErrors())
+	}
+	if cfg.httpsProbe {
+		opts = append(opts, urlcheck.WithHTTPSUpgradeCheck())
+	}
+	if cfg.acceptEnc != "" {
+		var encodings []string
+		for _, e := range strings.Split(cfg.acceptEnc, ",") {
+			e = strings.ToLower(strings.TrimSpace(e))
+			if !slices.Contains(acceptEncodings, e) {
+				return nil, nil, fmt.Errorf("unknown -accept-encoding %q (want %s)", e, strings.Join(acceptEncodings, "|"))
+			}
+			encodings = append(encodings, e)
+		}
+		opts = append(opts, urlcheck.WithAcceptEncoding(encodings...))
+	}
+	if cfg.bucketAuth {
+		opts = append(opts, urlcheck.WithBucketAuth())
+	}
+	if cfg.allowFile {
+		opts = append(opts, urlcheck.WithFileURLs())
+	}
+	if len(cfg.forbid) > 0 {
+		opts = append(opts, urlcheck.WithForbiddenContent(cfg.forbid...))
+	}
+	for _, spec := range cfg.forbidFor {
+		pattern, text, ok := strings.Cut(spec, "=")
+		if !ok || text == "" {
+			return nil, nil, fmt.Errorf("invalid -forbid-for %q, want regex=text", spec)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid -forbid-for pattern %q: %w", pattern, err)
+		}
+		opts = append(opts, urlcheck.WithContentRules(urlcheck.ContentRule{Pattern: re, Forbidden: []string{text}}))
+	}
+	if cfg.dedupe {
+		opts = append(opts, urlcheck.WithRedirectDedupe())
+	}
+	if cfg.maxRedirect < 0 {
+		return nil, nil, fmt.Errorf("invalid -max-redirects %d", cfg.maxRedirect)
+	}
+	// Only an explicit -max-redirects overrides the checker's default, so that
+	// -max-redirects 0 means "follow none" rather than "unset".
+	if _, set := cfg.effective["max-redirects"]; set {
+		opts = append(opts, urlcheck.WithMaxRedirects(cfg.maxRedirect))
+	}
+	if cfg.misconfig {
+		opts = append(opts, urlcheck.WithMisconfigDetection())
+	}
+	if cfg.parked {
+		opts = append(opts, urlcheck.WithParkedDetection())
+	}
+	if cfg.wayback {
+		opts = append(opts, urlcheck.WithArchiveLookup(""))
+	}
+	if cfg.emitCurl {
+		opts = append(opts, urlcheck.WithCurlCommands())
+	}
+	body, err := requestBody(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if body != nil || cfg.method != "" {
+		method := strings.ToUpper(cfg.method)
+		if method == "" {
+			method = http.MethodPost
+		}
+		opts = append(opts, urlcheck.WithRequestBody(method, body))
+	}
+	if cfg.budget > 0 {
+		opts = append(opts, urlcheck.WithBudget(cfg.budget))
+	}
+	if cfg.maxDuration > 0 {
+		opts = append(opts, urlcheck.WithMaxDuration(cfg.maxDuration))
+	}
+	if cfg.delay > 0 || cfg.delayJitter > 0 {
+		opts = append(opts, urlcheck.WithHostDelay(cfg.delay, cfg.delayJitter))
+	}
+	if cfg.transport != (urlcheck.TransportLimits{}) {
+		opts = append(opts, urlcheck.WithTransportLimits(cfg.transport))
+	}
+	if cfg.dial != (urlcheck.DialerConfig{}) {
+		opts = append(opts, urlcheck.WithDialer(cfg.dial))
+	}
+	if cfg.noKeepAlive {
+		opts = append(opts, urlcheck.WithoutKeepAlives())
+	}
+	if cfg.vhostAudit {
+		opts = append(opts, urlcheck.WithVhostAudit())
+	}
+	if cfg.verifyNX != "" {
+		if _, _, err := net.SplitHostPort(cfg.verifyNX); err != nil {
+			return nil, nil, fmt.Errorf("invalid -verify-nxdomain %q: %w", cfg.verifyNX, err)
+		}
+		opts = append(opts, urlcheck.WithNXDomainVerifier(cfg.verifyNX))
+	}
+	if cfg.fingerprint {
+		opts = append(opts, urlcheck.WithFingerprinting())
+	}
+	if cfg.assertFile != "" {
+		data, err := os.ReadFile(cfg.assertFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		assertions, err := urlcheck.ParseAssertions(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid -assertions %s: %w", cfg.assertFile, err)
+		}
+		opts = append(opts, urlcheck.WithAssertions(assertions...))
+	}
+	if len(cfg.assertList) > 0 {
+		opts = append(opts, urlcheck.WithAssertions(cfg.assertList...))
+	}
+	if len(cfg.modChecks) > 0 {
+		opts = append(opts, urlcheck.WithAssertions(cfg.modChecks...))
+	}
+	hosts := cfg.hosts
+	if isRowInput(cfg.file) {
+		rows, err := loadInputRows(cfg.file)
+		if err != nil {
+			return nil, nil, err
+		}
+		hosts = append(slices.Clip(hosts), rowOverrides(rows)...)
+	}
+	if len(hosts) > 0 {
+		opts = append(opts, urlcheck.WithHostOverrides(hosts...))
+	}
+	if cfg.maxLatency > 0 {
+		if cfg.slowMode != "fail" && cfg.slowMode != "warn" {
+			return nil, nil, fmt.Errorf("unknown -max-latency-mode %q (want fail|warn)", cfg.slowMode)
+		}
+		opts = append(opts, urlcheck.WithMaxLatency(cfg.maxLatency, cfg.slowMode == "fail"))
+	}
+	if cfg.auditSec {
+		if cfg.auditMode != "fail" && cfg.auditMode != "warn" {
+			return nil, nil, fmt.Errorf("unknown -audit-security-mode %q (want fail|warn)", cfg.auditMode)
+		}
+		opts = append(opts, urlcheck.WithSecurityAudit(cfg.auditMode == "fail"))
+	}
+	if cfg.mixed {
+		if cfg.mixedMode != "fail" && cfg.mixedMode != "warn" {
+			return nil, nil, fmt.Errorf("unknown -mixed-content-mode %q (want fail|warn)", cfg.mixedMode)
+		}
+		opts = append(opts, urlcheck.WithMixedContentDetection(cfg.mixedMode == "fail"))
+	}
+	if cfg.canonical {
+		if cfg.canonMode != "fail" && cfg.canonMode != "warn" {
+			return nil, nil, fmt.Errorf("unknown -canonical-mode %q (want fail|warn)", cfg.canonMode)
+		}
+		opts = append(opts, urlcheck.WithCanonicalChecks(cfg.canonMode == "fail"))
+	}
+	if cfg.expect > 0 {
+		if body == nil {
+			return nil, nil, fmt.Errorf("-expect-continue requires -body-file or -body-size")
+		}
+		opts = append(opts, urlcheck.WithExpectContinue(cfg.expect))
+	}
+	cleanup := func() {}
+	if cfg.render {
+		browser := render.New(render.Options{ExecPath: cfg.chrome, Timeout: cfg.renderTO, Settle: cfg.renderWait})
+		opts = append(opts, urlcheck.WithRenderer(browser.Render))
+		cleanup = sync.OnceFunc(browser.Close)
+	}
+	return opts, cleanup, nil
+}
+
+func failed(r urlcheck.Result) bool {
+	return !r.OK && r.SkipReason == ""
+}
+
+func postProcessors(specs []string) ([]urlcheck.Processor, error) {
+	var procs []urlcheck.Processor
+	for _, spec := range specs {
+		fields, err := splitCommand(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -post-process %q: %w", spec, err)
+		}
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("empty -post-process command")
+		}
+		procs = append(procs, urlcheck.ExecProcessor{Command: fields[0], Args: fields[1:]})
+	}
+	return procs, nil
+}
+
+// splitCommand splits a command line into words the way a POSIX shell
+// would for plain arguments: single quotes are literal, double quotes allow
+// backslash escapes of \ " $ and `, and an unquoted backslash escapes the
+// next character. No expansion is done.
+func splitCommand(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	for i := 0; i < len(line); i++ {
+		switch ch := line[i]; {
+		case ch == ' ' || ch == '\t' || ch == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case ch == '\'':
+			end := strings.IndexByte(line[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote")
+			}
+			word.WriteString(line[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case ch == '"':
+			i++
+			for ; i < len(line) && line[i] != '"'; i++ {
+				if line[i] == '\\' && i+1 < len(line) && strings.IndexByte("\\\"$`", line[i+1]) >= 0 {
+					i++
+				}
+				word.WriteByte(line[i])
+			}
+			if i >= len(line) {
+				return nil, fmt.Errorf("unterminated double quote")
+			}
+			inWord = true
+		case ch == '\\':
+			if i+1 >= len(line) {
+				return nil, fmt.Errorf("trailing backslash")
+			}
+			i++
+			word.WriteByte(line[i])
+			inWord = true
+		default:
+			word.WriteByte(ch)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
 }
 
-func writeOutputs(results []urlcheck.Result, asJSON bool) error {
-	if err := os.MkdirAll(".out", 0o755); err != nil {
-		return err
+func requestBody(cfg config) ([]byte, error) {
+	if cfg.bodyFile != "" && cfg.bodySize > 0 {
+		return nil, fmt.Errorf("-body-file and -body-size are mutually exclusive")
 	}
-	validPath := ".out/valid.txt"
-	invalidPath := ".out/invalid.txt"
-	if err := writeSplit(results, validPath, invalidPath); err != nil {
-		return err
+	if cfg.bodyFile != "" {
+		return os.ReadFile(cfg.bodyFile)
 	}
-	if asJSON {
-		return writeJSON(results)
+	if cfg.bodySize > 0 {
+		return bytes.Repeat([]byte("x"), cfg.bodySize), nil
 	}
-	return writeTable(results)
+	return nil, nil
 }
 
-func writeSplit(results []urlcheck.Result, validPath, invalidPath string) error {
-	valid, err := os.Create(validPath)
+func newSampling(cfg config) (sampling, error) {
+	fraction, err := parseSampleFraction(cfg.sample)
 	if err != nil {
-		return err
+		return sampling{}, err
+	}
+	if cfg.sampleN < 0 {
+		return sampling{}, fmt.Errorf("invalid -sample-n %d", cfg.sampleN)
+	}
+	if cfg.samplePer < 0 {
+		return sampling{}, fmt.Errorf("invalid -sample-per-host %d", cfg.samplePer)
+	}
+	seed := cfg.seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	return sampling{fraction: fraction, n: cfg.sampleN, perHost: cfg.samplePer, seed: seed}, nil
+}
+
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) * 100 / float64(total)
+}
+
+type provenance map[string]urlcheck.Metadata
+
+func (p provenance) attribute(r urlcheck.Result) urlcheck.Result {
+	meta, ok := p[r.URL]
+	if !ok {
+		return r
+	}
+	if r.Source == nil {
+		r.Source = meta.Location
+	}
+	if r.Origin == "" {
+		r.Origin = meta.Label
+	}
+	if r.Labels == nil {
+		r.Labels = meta.Labels
+	}
+	return r
+}
+
+func inputSources(cfg config) ([]urlcheck.Source, error) {
+	var sources []urlcheck.Source
+	if len(cfg.scan) > 0 {
+		scanned, locs, err := scanFiles(cfg.scan)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, scanSource(scanned, locs))
+	}
+	for _, sitemap := range cfg.sitemaps {
+		src, err := urlcheck.SitemapSource(context.Background(), &http.Client{Timeout: cfg.timeout}, sitemap)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, src)
+	}
+	if len(cfg.args) > 0 {
+		sources = append(sources, urlcheck.SliceSource("args", cfg.args))
 	}
-	defer valid.Close()
-	invalid, err := os.Create(invalidPath)
+	return sources, nil
+}
+
+func listLabel(cfg config) string {
+	if cfg.file != "" {
+		return "file:" + cfg.file
+	}
+	return "stdin"
+}
+
+func loadInputs(cfg config, stdin io.Reader) ([]string, provenance, error) {
+	sources, err := inputSources(cfg)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	defer invalid.Close()
-	for _, r := range results {
-		if r.OK {
-			if _, err := fmt.Fprintln(valid, r.URL); err != nil {
-				return err
-			}
-			continue
+	if cfg.file != "" || len(sources) == 0 {
+		src, err := listSource(cfg, stdin)
+		if err != nil {
+			return nil, nil, err
 		}
-		if _, err := fmt.Fprintln(invalid, r.URL); err != nil {
-			return err
+		sources = append(sources, src)
+	}
+	urls, metas, err := urlcheck.Collect(urlcheck.MultiSource(sources...))
+	if err != nil {
+		return nil, nil, err
+	}
+	prov := make(provenance)
+	for i, u := range urls {
+		if _, seen := prov[u]; !seen {
+			prov[u] = metas[i]
 		}
 	}
-	return nil
+	return urls, prov, nil
 }
 
-func writeJSON(results []urlcheck.Result) error {
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-	return enc.Encode(results)
+func listSource(cfg config, stdin io.Reader) (urlcheck.Source, error) {
+	if isRowInput(cfg.file) {
+		rows, err := loadInputRows(cfg.file)
+		if err != nil {
+			return nil, err
+		}
+		return rowSource(listLabel(cfg), rows), nil
+	}
+	listed, err := loadURLs(cfg.file, stdin)
+	if err != nil {
+		return nil, err
+	}
+	return urlcheck.SliceSource(listLabel(cfg), listed), nil
 }
 
-func writeTable(results []urlcheck.Result) error {
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "URL\tSTATUS\tOK\tATTEMPTS\tERROR")
-	for _, r := range results {
-		fmt.Fprintf(w, "%s\t%d\t%t\t%d\t%s\n", r.URL, r.Status, r.OK, r.Attempts, r.Error)
+func loadURLs(path string, stdin io.Reader) ([]string, error) {
+	var reader io.Reader
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		reader = f
+	} else {
+		reader = stdin
 	}
-	return w.Flush()
+	scanner := bufio.NewScanner(reader)
+	var urls []string
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		urls = append(urls, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return urls, nil
 }
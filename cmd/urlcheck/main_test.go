@@ -2,10 +2,15 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/reisei231/go-url-checker/internal/urlcheck"
 )
@@ -51,7 +56,7 @@ func TestWriteOutputsCreatesFiles(t *testing.T) {
 	stdout := os.Stdout
 	r, w, _ := os.Pipe()
 	os.Stdout = w
-	if err := writeOutputs(results, false); err != nil {
+	if err := writeOutputs(results, &sinkSet{sinks: []sink{newFormatSink("table", os.Stdout)}}, ".out"); err != nil {
 		w.Close()
 		os.Stdout = stdout
 		t.Fatalf("writeOutputs: %v", err)
@@ -81,3 +86,254 @@ func TestWriteOutputsCreatesFiles(t *testing.T) {
 		t.Fatalf("table output missing headers: %s", output)
 	}
 }
+
+func TestCheckerOptionsRejectsBadForbidFor(t *testing.T) {
+	cfg := config{forbidFor: stringList{"no-separator"}}
+	if _, _, err := checkerOptions(cfg); err == nil {
+		t.Fatalf("expected error for malformed -forbid-for")
+	}
+	cfg = config{forbidFor: stringList{"/admin/=stack trace"}}
+	opts, _, err := checkerOptions(cfg)
+	if err != nil || len(opts) != 1 {
+		t.Fatalf("expected one option, got %d (%v)", len(opts), err)
+	}
+}
+
+func TestPostProcessorsSplitsCommand(t *testing.T) {
+	procs, err := postProcessors([]string{"jq -c ."})
+	if err != nil || len(procs) != 1 {
+		t.Fatalf("expected one processor, got %d (%v)", len(procs), err)
+	}
+	p := procs[0].(urlcheck.ExecProcessor)
+	if p.Command != "jq" || len(p.Args) != 2 || p.Args[1] != "." {
+		t.Fatalf("unexpected processor: %+v", p)
+	}
+	if _, err := postProcessors([]string{"  "}); err == nil {
+		t.Fatal("expected error for empty command")
+	}
+}
+
+func TestSplitCommandHonorsQuotes(t *testing.T) {
+	cases := map[string][]string{
+		`jq -c .`:                          {"jq", "-c", "."},
+		`jq 'select(.status == 404)'`:      {"jq", "select(.status == 404)"},
+		`sh -c "echo \"hi there\" \$HOME"`: {"sh", "-c", `echo "hi there" $HOME`},
+		`grep a\ b ''`:                     {"grep", "a b", ""},
+	}
+	for line, want := range cases {
+		got, err := splitCommand(line)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("splitCommand(%q) = %q, %v; want %q", line, got, err, want)
+		}
+	}
+	for _, line := range []string{`jq 'unterminated`, `jq "unterminated`, `jq \`} {
+		if _, err := splitCommand(line); err == nil {
+			t.Errorf("splitCommand(%q): expected error", line)
+		}
+	}
+}
+
+func TestWriteOutputsHonorsOutDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "reports")
+	results := []urlcheck.Result{{URL: "https://ok.example", OK: true, Status: 200}}
+	if err := writeOutputs(results, &sinkSet{}, dir); err != nil {
+		t.Fatalf("writeOutputs: %v", err)
+	}
+	for _, path := range splitFiles(dir)[:2] {
+		if _, err := os.Stat(path); err != nil {
+			t.Fatalf("expected %s: %v", path, err)
+		}
+	}
+	if splitFiles("") != nil {
+		t.Fatal("expected no split files when disabled")
+	}
+	if got := outputArtifacts("", []string{"json=r.json", "table"}); len(got) != 1 || got[0] != "r.json" {
+		t.Fatalf("unexpected artifacts: %v", got)
+	}
+}
+
+func TestLoadInputsTracksProvenance(t *testing.T) {
+	dir := t.TempDir()
+	doc := filepath.Join(dir, "README.md")
+	list := filepath.Join(dir, "urls.txt")
+	if err := os.WriteFile(doc, []byte("see https://docs.example/a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(list, []byte("https://list.example/\nhttps://docs.example/a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	urls, prov, err := loadInputs(config{file: list, scan: stringList{doc}}, strings.NewReader(""))
+	if err != nil {
+		t.Fatalf("loadInputs: %v", err)
+	}
+	if len(urls) != 3 {
+		t.Fatalf("expected 3 urls, got %v", urls)
+	}
+	scanned := prov.attribute(urlcheck.Result{URL: "https://docs.example/a"})
+	if scanned.Origin != "scan" || scanned.Source == nil || scanned.Source.Line != 1 {
+		t.Fatalf("expected scan provenance to win, got %+v", scanned)
+	}
+	listed := prov.attribute(urlcheck.Result{URL: "https://list.example/"})
+	if listed.Origin != "file:"+list || listed.Source != nil {
+		t.Fatalf("unexpected file provenance: %+v", listed)
+	}
+}
+
+func TestOutputSpecsAcceptsBarePaths(t *testing.T) {
+	got := outputSpecs([]string{"report.json", "csv", "junit=out.xml", "statsd=127.0.0.1:8125"}, "json")
+	want := []string{"json=report.json", "csv", "junit=out.xml", "statsd=127.0.0.1:8125"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestCheckerOptionsLoadsAssertions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "assertions.json")
+	if err := os.WriteFile(path, []byte(`[{"pattern": "(", "status": 200}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := checkerOptions(config{assertFile: path}); err == nil || !strings.Contains(err.Error(), "invalid -assertions") {
+		t.Fatalf("expected assertions error, got %v", err)
+	}
+	if err := os.WriteFile(path, []byte(`[{"url": "https://a.example", "status": 301}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	opts, _, err := checkerOptions(config{assertFile: path})
+	if err != nil || len(opts) != 1 {
+		t.Fatalf("expected one option, got %d (%v)", len(opts), err)
+	}
+}
+
+func TestCheckerOptionsRenderReportsBrowserFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	opts, closeOptions, err := checkerOptions(config{render: true, chrome: "/nonexistent/chrome", renderTO: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeOptions()
+	checker := urlcheck.NewChecker(1, time.Second, 0, nil, opts...)
+	results, err := checker.Check(context.Background(), []string{server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := results[0]; r.OK || r.ErrorKind != urlcheck.KindRender || !strings.HasPrefix(r.Error, "render: ") {
+		t.Fatalf("expected render failure, got %+v", r)
+	}
+}
+
+func TestCheckerOptionsSecurityAudit(t *testing.T) {
+	if _, _, err := checkerOptions(config{auditSec: true, auditMode: "block"}); err == nil || !strings.Contains(err.Error(), "-audit-security-mode") {
+		t.Fatalf("expected mode error, got %v", err)
+	}
+	opts, _, err := checkerOptions(config{auditSec: true, auditMode: "warn"})
+	if err != nil || len(opts) != 1 {
+		t.Fatalf("expected one option, got %d (%v)", len(opts), err)
+	}
+}
+
+func TestCheckerOptionsCheckType(t *testing.T) {
+	if _, _, err := checkerOptions(config{checkType: "icmp"}); err == nil || !strings.Contains(err.Error(), "unknown -check \"icmp\" (want http|dns|tcp|tls|grpc-health|graphql)") {
+		t.Fatalf("expected check type error, got %v", err)
+	}
+	opts, _, err := checkerOptions(config{checkType: "dns"})
+	if err != nil || len(opts) != 1 {
+		t.Fatalf("expected one option, got %d (%v)", len(opts), err)
+	}
+}
+
+func TestCheckerOptionsMixedContent(t *testing.T) {
+	if _, _, err := checkerOptions(config{mixed: true, mixedMode: "strict"}); err == nil || !strings.Contains(err.Error(), "-mixed-content-mode") {
+		t.Fatalf("expected mode error, got %v", err)
+	}
+	opts, _, err := checkerOptions(config{mixed: true, mixedMode: "fail"})
+	if err != nil || len(opts) != 1 {
+		t.Fatalf("expected one option, got %d (%v)", len(opts), err)
+	}
+}
+
+func TestCheckerOptionsCanonical(t *testing.T) {
+	if _, _, err := checkerOptions(config{canonical: true, canonMode: "loud"}); err == nil || !strings.Contains(err.Error(), "-canonical-mode") {
+		t.Fatalf("expected mode error, got %v", err)
+	}
+	opts, _, err := checkerOptions(config{canonical: true, canonMode: "warn"})
+	if err != nil || len(opts) != 1 {
+		t.Fatalf("expected one option, got %d (%v)", len(opts), err)
+	}
+}
+
+func TestCheckerOptionsSSHAuth(t *testing.T) {
+	key := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(key, []byte("not a key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := checkerOptions(config{sshKey: key}); err == nil || !strings.Contains(err.Error(), "parsing -ssh-key") {
+		t.Fatalf("expected key parse error, got %v", err)
+	}
+	if _, _, err := checkerOptions(config{knownHosts: filepath.Join(t.TempDir(), "missing")}); err == nil || !strings.Contains(err.Error(), "reading -ssh-known-hosts") {
+		t.Fatalf("expected known_hosts error, got %v", err)
+	}
+}
+
+func TestCheckerOptionsAcceptEncoding(t *testing.T) {
+	if _, _, err := checkerOptions(config{acceptEnc: "gzip,zstd"}); err == nil || err.Error() != `unknown -accept-encoding "zstd" (want gzip|br|deflate|identity)` {
+		t.Fatalf("expected encoding error, got %v", err)
+	}
+	opts, _, err := checkerOptions(config{acceptEnc: "GZIP, br"})
+	if err != nil || len(opts) != 1 {
+		t.Fatalf("expected one option, got %d (%v)", len(opts), err)
+	}
+}
+
+func TestCheckerOptionsDialer(t *testing.T) {
+	opts, _, err := checkerOptions(config{dial: urlcheck.DialerConfig{FallbackDelay: -1}})
+	if err != nil || len(opts) != 1 {
+		t.Fatalf("expected one option, got %d (%v)", len(opts), err)
+	}
+}
+
+func TestCheckerOptionsMaxRedirectsZero(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/start" {
+			http.Redirect(w, r, "/end", http.StatusFound)
+		}
+	}))
+	defer server.Close()
+	check := func(cfg config) urlcheck.Result {
+		t.Helper()
+		opts, _, err := checkerOptions(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results, err := urlcheck.NewChecker(1, time.Second, 0, nil, opts...).Check(context.Background(), []string{server.URL + "/start"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return results[0]
+	}
+	if r := check(config{}); !r.OK {
+		t.Fatalf("an unset -max-redirects should keep the default: %+v", r)
+	}
+	if r := check(config{effective: map[string]string{"max-redirects": "0"}}); r.OK || r.ErrorKind != urlcheck.KindTooManyRedirects {
+		t.Fatalf("-max-redirects 0 should fail any redirect: %+v", r)
+	}
+}
+
+func TestTableExtrasFollowTheTable(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	cases := []struct {
+		outputs []string
+		want    *bytes.Buffer
+	}{
+		{nil, &stdout},
+		{[]string{"table"}, &stdout},
+		{[]string{"json", "table=-"}, &stdout},
+		{[]string{"table=report.txt"}, &stderr},
+		{[]string{"json"}, &stderr},
+	}
+	for _, c := range cases {
+		if got := tableExtrasOut(c.outputs, "table", &stdout, &stderr); got != c.want {
+			t.Errorf("tableExtrasOut(%q) picked the wrong writer", c.outputs)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+	"gopkg.in/yaml.v3"
+)
+
+type probeModule struct {
+	Prober  string    `yaml:"prober"`
+	Timeout string    `yaml:"timeout"`
+	HTTP    httpProbe `yaml:"http"`
+}
+
+type httpProbe struct {
+	Method                     string            `yaml:"method"`
+	Headers                    map[string]string `yaml:"headers"`
+	ValidStatusCodes           []int             `yaml:"valid_status_codes"`
+	FailIfSSL                  bool              `yaml:"fail_if_ssl"`
+	FailIfNotSSL               bool              `yaml:"fail_if_not_ssl"`
+	FailIfBodyMatchesRegexp    []string          `yaml:"fail_if_body_matches_regexp"`
+	FailIfBodyNotMatchesRegexp []string          `yaml:"fail_if_body_not_matches_regexp"`
+	TLSConfig                  probeTLS          `yaml:"tls_config"`
+}
+
+type probeTLS struct {
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+	ServerName         string `yaml:"server_name"`
+	CAFile             string `yaml:"ca_file"`
+	CertFile           string `yaml:"cert_file"`
+	KeyFile            string `yaml:"key_file"`
+	MinVersion         string `yaml:"min_version"`
+}
+
+var tlsVersions = map[string]uint16{
+	"TLS10": tls.VersionTLS10,
+	"TLS11": tls.VersionTLS11,
+	"TLS12": tls.VersionTLS12,
+	"TLS13": tls.VersionTLS13,
+}
+
+type loadedModule struct {
+	timeout time.Duration
+	method  string
+	headers map[string]string
+	tls     *tls.Config
+	checks  urlcheck.Assertion
+}
+
+func loadModules(node *yaml.Node) (map[string]*loadedModule, error) {
+	data, err := yaml.Marshal(node)
+	if err != nil {
+		return nil, err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var raw map[string]probeModule
+	if err := dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+	modules := make(map[string]*loadedModule, len(raw))
+	for name, m := range raw {
+		loaded, err := m.load()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		modules[name] = loaded
+	}
+	return modules, nil
+}
+
+func (m probeModule) load() (*loadedModule, error) {
+	if m.Prober != "" && m.Prober != "http" {
+		return nil, fmt.Errorf("prober %q is not supported (want http)", m.Prober)
+	}
+	if m.HTTP.FailIfSSL && m.HTTP.FailIfNotSSL {
+		return nil, fmt.Errorf("fail_if_ssl and fail_if_not_ssl are mutually exclusive")
+	}
+	loaded := &loadedModule{method: strings.ToUpper(m.HTTP.Method), headers: m.HTTP.Headers}
+	if m.Timeout != "" {
+		d, err := time.ParseDuration(m.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		loaded.timeout = d
+	}
+	tlsConfig, err := m.HTTP.TLSConfig.load()
+	if err != nil {
+		return nil, fmt.Errorf("tls_config: %w", err)
+	}
+	loaded.tls = tlsConfig
+	loaded.checks = urlcheck.Assertion{
+		StatusIn:     m.HTTP.ValidStatusCodes,
+		BodyRegex:    m.HTTP.FailIfBodyNotMatchesRegexp,
+		BodyNotRegex: m.HTTP.FailIfBodyMatchesRegexp,
+	}
+	if m.HTTP.FailIfSSL || m.HTTP.FailIfNotSSL {
+		loaded.checks.TLS = &m.HTTP.FailIfNotSSL
+	}
+	return loaded, nil
+}
+
+func (p probeTLS) load() (*tls.Config, error) {
+	if p == (probeTLS{}) {
+		return nil, nil
+	}
+	config := &tls.Config{InsecureSkipVerify: p.InsecureSkipVerify, ServerName: p.ServerName}
+	if p.MinVersion != "" {
+		v, ok := tlsVersions[p.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("unknown min_version %q (want TLS10|TLS11|TLS12|TLS13)", p.MinVersion)
+		}
+		config.MinVersion = v
+	}
+	if p.CAFile != "" {
+		pem, err := os.ReadFile(p.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", p.CAFile)
+		}
+	}
+	if p.CertFile != "" || p.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(p.CertFile, p.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+func (m *loadedModule) apply(h hostConfig, o *urlcheck.HostOverride) (*urlcheck.Assertion, error) {
+	if o.Timeout == 0 {
+		o.Timeout = m.timeout
+	}
+	if o.Method == "" {
+		o.Method = m.method
+	}
+	if len(m.headers) > 0 {
+		headers := maps.Clone(m.headers)
+		maps.Copy(headers, o.Headers)
+		o.Headers = headers
+	}
+	o.TLS = m.tls
+	checks := m.checks
+	if len(checks.StatusIn) == 0 && len(checks.BodyRegex) == 0 && len(checks.BodyNotRegex) == 0 && checks.TLS == nil {
+		return nil, nil
+	}
+	if h.URL != "" {
+		checks.URL = h.URL
+	} else {
+		checks.Pattern = hostPattern(h.Host)
+	}
+	data, err := json.Marshal([]urlcheck.Assertion{checks})
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := urlcheck.ParseAssertions(data)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed[0], nil
+}
+
+func hostPattern(host string) string {
+	name := regexp.QuoteMeta(host)
+	if suffix, ok := strings.CutPrefix(host, "*."); ok {
+		name = `[^/?#@]+\.` + regexp.QuoteMeta(suffix)
+	}
+	return `(?i)^[a-z][a-z0-9+.-]*://` + name + `(:\d+)?([/?#]|$)`
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestLoadConfigFileModules(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Probe") != "blackbox" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path == "/down" {
+			w.Write([]byte("status: maintenance"))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	path := writeConfig(t, `
+modules:
+  http_2xx:
+    prober: http
+    timeout: 2s
+    http:
+      method: get
+      headers:
+        X-Probe: blackbox
+      valid_status_codes: [204]
+      fail_if_not_ssl: true
+      fail_if_body_matches_regexp: [maintenance]
+      tls_config:
+        insecure_skip_verify: true
+hosts:
+  - url: `+server.URL+`/up
+    module: http_2xx
+  - url: `+server.URL+`/down
+    module: http_2xx
+    timeout: 5s
+`)
+	var cfg config
+	if err := loadConfigFile(testFlagSet(&cfg), &cfg, path, ""); err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.hosts) != 2 || len(cfg.modChecks) != 2 {
+		t.Fatalf("unexpected hosts %+v and checks %+v", cfg.hosts, cfg.modChecks)
+	}
+	if h := cfg.hosts[1]; h.Timeout != 5*time.Second || h.Method != "GET" || h.Headers["X-Probe"] != "blackbox" || h.TLS == nil {
+		t.Fatalf("unexpected override %+v", h)
+	}
+	opts, _, err := checkerOptions(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, _ := urlcheck.NewChecker(2, time.Second, 0, nil, opts...).Check(context.Background(), []string{server.URL + "/up", server.URL + "/down"})
+	if !results[0].OK {
+		t.Fatalf("expected the module to pass, got %+v", results[0])
+	}
+	if r := results[1]; r.OK || strings.Join(r.FailedAssertions, "; ") != "status 200, want one of 204; body matches /maintenance/" {
+		t.Fatalf("unexpected result %+v", r)
+	}
+}
+
+func TestLoadConfigFileModuleErrors(t *testing.T) {
+	for body, want := range map[string]string{
+		"modules:\n  icmp:\n    prober: icmp\n":                                             `prober "icmp" is not supported (want http)`,
+		"modules:\n  m:\n    http:\n      preferred_ip_protocol: ip4\n":                     "preferred_ip_protocol not found",
+		"modules:\n  m:\n    http:\n      tls_config:\n        min_version: SSL3\n":         `unknown min_version "SSL3"`,
+		"modules:\n  m:\n    http:\n      fail_if_ssl: true\n      fail_if_not_ssl: true\n": "mutually exclusive",
+		"hosts:\n  - host: example.com\n    module: missing\n":                              `unknown module "missing"`,
+	} {
+		var cfg config
+		err := loadConfigFile(testFlagSet(&cfg), &cfg, writeConfig(t, body), "")
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: expected error containing %q, got %v", body, want, err)
+		}
+	}
+}
+
+func TestHostPattern(t *testing.T) {
+	for host, cases := range map[string]map[string]bool{
+		"example.com": {
+			"https://example.com":        true,
+			"https://Example.com:8443/x": true,
+			"https://example.com.evil/":  false,
+			"https://www.example.com/":   false,
+		},
+		"*.example.com": {
+			"https://www.example.com/a":      true,
+			"http://a.b.example.com?q":       true,
+			"https://example.com/":           false,
+			"https://evil.com/x.example.com": false,
+		},
+	} {
+		re := regexp.MustCompile(hostPattern(host))
+		for u, want := range cases {
+			if got := re.MatchString(u); got != want {
+				t.Errorf("%s matching %s = %v, want %v", host, u, got, want)
+			}
+		}
+	}
+}
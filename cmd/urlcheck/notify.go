@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"text/template"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+const (
+	eventFailures    = "failures"
+	eventStateChange = "state_change"
+)
+
+type notification struct {
+	Event    string            `json:"event"`
+	Time     time.Time         `json:"time"`
+	Summary  urlcheck.Summary  `json:"summary"`
+	Failures []urlcheck.Result `json:"failures"`
+	Changes  []stateChange     `json:"changes,omitempty"`
+	Results  []urlcheck.Result `json:"-"`
+}
+
+type stateChange struct {
+	URL  string `json:"url"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type notifier interface {
+	notify(context.Context, notification) error
+}
+
+func newNotification(event string, results []urlcheck.Result, elapsed time.Duration, changes []stateChange) notification {
+	n := notification{Event: event, Time: time.Now(), Summary: urlcheck.Summarize(results, elapsed), Changes: changes, Results: results}
+	for _, r := range results {
+		if failed(r) {
+			n.Failures = append(n.Failures, r)
+		}
+	}
+	return n
+}
+
+func stateName(ok bool) string {
+	if ok {
+		return "ok"
+	}
+	return "failing"
+}
+
+func trackState(prev map[string]bool, results []urlcheck.Result) (map[string]bool, []stateChange) {
+	next := make(map[string]bool, len(results))
+	var changes []stateChange
+	for _, r := range results {
+		if r.SkipReason != "" {
+			continue
+		}
+		next[r.URL] = r.OK
+		if was, seen := prev[r.URL]; seen && was != r.OK {
+			changes = append(changes, stateChange{URL: r.URL, From: stateName(was), To: stateName(r.OK)})
+		}
+	}
+	return next, changes
+}
+
+func notifyAll(ctx context.Context, notifiers []notifier, n notification) error {
+	var first error
+	for _, nt := range notifiers {
+		if err := nt.notify(ctx, n); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+type webhook struct {
+	url    string
+	tmpl   *template.Template
+	client *http.Client
+}
+
+func newWebhook(url, tmplText string, timeout time.Duration) (*webhook, error) {
+	w := &webhook{url: url, client: &http.Client{Timeout: timeout}}
+	if tmplText != "" {
+		tmpl, err := template.New("webhook").Funcs(template.FuncMap{
+			"ms":    func(d time.Duration) int64 { return d.Milliseconds() },
+			"error": errorText,
+			"json": func(v any) (string, error) {
+				b, err := json.Marshal(v)
+				return string(b), err
+			},
+		}).Parse(tmplText)
+		if err != nil {
+			return nil, err
+		}
+		w.tmpl = tmpl
+	}
+	return w, nil
+}
+
+func (w *webhook) notify(ctx context.Context, n notification) error {
+	var body bytes.Buffer
+	var err error
+	if w.tmpl != nil {
+		err = w.tmpl.Execute(&body, n)
+	} else {
+		err = json.NewEncoder(&body).Encode(n)
+	}
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned %s", w.url, resp.Status)
+	}
+	return nil
+}
+
+func notifiersFor(cfg config) ([]notifier, error) {
+	var notifiers []notifier
+	if cfg.webhook != "" {
+		w, err := newWebhook(cfg.webhook, cfg.webhookTmpl, cfg.timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -webhook-template: %w", err)
+		}
+		notifiers = append(notifiers, w)
+	} else if cfg.webhookTmpl != "" {
+		return nil, fmt.Errorf("-webhook-template requires -webhook")
+	}
+	for _, target := range cfg.webhooks {
+		w, err := newWebhook(target.URL, target.Template, cfg.timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template for webhook %s: %w", target.URL, err)
+		}
+		notifiers = append(notifiers, w)
+	}
+	if cfg.smtp != "" {
+		e, err := newEmailNotifier(cfg, os.Getenv("SMTP_PASSWORD"))
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, e)
+	}
+	if cfg.slackHook != "" || cfg.slackToken != "" {
+		s, err := newSlackNotifier(cfg)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, s)
+	}
+	return notifiers, nil
+}
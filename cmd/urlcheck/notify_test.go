@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestTrackState(t *testing.T) {
+	prev, changes := trackState(nil, []urlcheck.Result{{URL: "a", OK: true}, {URL: "b", OK: true}, {URL: "s", SkipReason: "excluded"}})
+	if len(changes) != 0 || len(prev) != 2 {
+		t.Fatalf("first run should record state without changes: %v %v", prev, changes)
+	}
+	_, changes = trackState(prev, []urlcheck.Result{{URL: "a", OK: false}, {URL: "b", OK: true}, {URL: "c", OK: false}})
+	if len(changes) != 1 || changes[0] != (stateChange{URL: "a", From: "ok", To: "failing"}) {
+		t.Fatalf("unexpected changes: %+v", changes)
+	}
+}
+
+func TestWebhookNotify(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	n := newNotification(eventFailures, []urlcheck.Result{{URL: "https://a.example", OK: true}, {URL: "https://b.example", Status: 404}}, time.Second, nil)
+
+	hook, _ := newWebhook(server.URL, "", time.Second)
+	if err := hook.notify(context.Background(), n); err != nil {
+		t.Fatal(err)
+	}
+	var got notification
+	if err := json.Unmarshal([]byte(bodies[0]), &got); err != nil || got.Event != eventFailures || len(got.Failures) != 1 || got.Summary.Total != 2 {
+		t.Fatalf("unexpected default payload %s (%v)", bodies[0], err)
+	}
+
+	hook, err := newWebhook(server.URL, `{"text":{{json (printf "%d broken: %s" (len .Failures) (index .Failures 0).URL)}}}`, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := hook.notify(context.Background(), n); err != nil {
+		t.Fatal(err)
+	}
+	if bodies[1] != `{"text":"1 broken: https://b.example"}` {
+		t.Fatalf("unexpected templated payload %s", bodies[1])
+	}
+
+	hook, _ = newWebhook(server.URL+"/fail", "", time.Second)
+	if err := hook.notify(context.Background(), n); err == nil {
+		t.Fatal("expected error for non-2xx webhook response")
+	}
+}
+
+func TestNotifiersForValidates(t *testing.T) {
+	if _, err := notifiersFor(config{webhookTmpl: "{{.Event}}"}); err == nil {
+		t.Fatal("expected error for template without webhook")
+	}
+	if _, err := notifiersFor(config{webhook: "http://x", webhookTmpl: "{{"}); err == nil {
+		t.Fatal("expected error for invalid template")
+	}
+}
+
+func TestWatcherNotifiesOnStateChange(t *testing.T) {
+	broken := false
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if broken {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer target.Close()
+	var events []notification
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var n notification
+		json.NewDecoder(r.Body).Decode(&n)
+		events = append(events, n)
+	}))
+	defer hook.Close()
+	list := filepath.Join(t.TempDir(), "urls.txt")
+	if err := os.WriteFile(list, []byte(target.URL+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config{file: list, format: "ndjson", concurrency: 1, timeout: time.Second, interval: time.Minute, webhook: hook.URL}
+	w, err := newWatcher(cfg, nil, io.Discard, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range []bool{false, false, true, true} {
+		broken = b
+		if _, err := w.cycle(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(events) != 1 || events[0].Event != eventStateChange || events[0].Changes[0].To != "failing" {
+		t.Fatalf("expected one state change notification, got %+v", events)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+var sortKeys = map[string]func(a, b urlcheck.Result) bool{
+	"status":   func(a, b urlcheck.Result) bool { return a.Status < b.Status },
+	"url":      func(a, b urlcheck.Result) bool { return a.URL < b.URL },
+	"duration": func(a, b urlcheck.Result) bool { return a.Duration < b.Duration },
+	"attempts": func(a, b urlcheck.Result) bool { return a.Attempts < b.Attempts },
+}
+
+func parseSort(spec string) (func(a, b urlcheck.Result) bool, error) {
+	if spec == "" {
+		return nil, nil
+	}
+	key, dir, _ := strings.Cut(spec, ":")
+	less, ok := sortKeys[key]
+	if !ok {
+		return nil, fmt.Errorf("unknown -sort key %q (want status|url|duration|attempts)", key)
+	}
+	switch dir {
+	case "", "asc":
+		return less, nil
+	case "desc":
+		return func(a, b urlcheck.Result) bool { return less(b, a) }, nil
+	}
+	return nil, fmt.Errorf("invalid -sort direction %q (want asc or desc)", dir)
+}
+
+func sortResults(results []urlcheck.Result, less func(a, b urlcheck.Result) bool) {
+	sort.SliceStable(results, func(i, j int) bool { return less(results[i], results[j]) })
+}
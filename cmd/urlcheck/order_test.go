@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestSortResults(t *testing.T) {
+	results := []urlcheck.Result{
+		{URL: "https://b.example", Status: 200, Duration: time.Second},
+		{URL: "https://a.example", Status: 500, Duration: 3 * time.Second},
+		{URL: "https://c.example", Status: 404, Duration: 2 * time.Second},
+	}
+	less, err := parseSort("duration:desc")
+	if err != nil {
+		t.Fatalf("parseSort: %v", err)
+	}
+	sortResults(results, less)
+	if results[0].URL != "https://a.example" || results[2].URL != "https://b.example" {
+		t.Fatalf("unexpected order: %+v", results)
+	}
+	less, _ = parseSort("url")
+	sortResults(results, less)
+	if results[0].URL != "https://a.example" || results[1].URL != "https://b.example" {
+		t.Fatalf("unexpected order: %+v", results)
+	}
+	for _, bad := range []string{"size", "status:up"} {
+		if _, err := parseSort(bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
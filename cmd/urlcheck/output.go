@@ -0,0 +1,482 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+type formatter func(io.Writer, []urlcheck.Result) error
+
+var formats = map[string]formatter{
+	"table":  writeTable,
+	"json":   writeJSON,
+	"csv":    writeCSV,
+	"ndjson": writeNDJSON,
+	"junit":  writeJUnit,
+	"sarif":  writeSARIF,
+	"tap":    writeTAP,
+	"html":   writeHTML,
+	"github": writeGitHub,
+	"gitlab": writeCodeQuality,
+}
+
+var streamingFormats = map[string]bool{
+	"ndjson": true,
+}
+
+func formatNames() string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, "|")
+}
+
+func writeOutputs(results []urlcheck.Result, sinks *sinkSet, outDir string) error {
+	if outDir != "" {
+		if err := writeFiles(results, outDir); err != nil {
+			sinks.close(results)
+			return err
+		}
+	}
+	return sinks.close(results)
+}
+
+func splitFiles(outDir string) []string {
+	if outDir == "" {
+		return nil
+	}
+	return []string{
+		filepath.Join(outDir, "valid.txt"),
+		filepath.Join(outDir, "invalid.txt"),
+		filepath.Join(outDir, "skipped.txt"),
+	}
+}
+
+func writeFiles(results []urlcheck.Result, outDir string) error {
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return err
+	}
+	paths := splitFiles(outDir)
+	if err := writeSplit(results, paths[0], paths[1]); err != nil {
+		return err
+	}
+	return writeSkipped(results, paths[2])
+}
+
+func writeSplit(results []urlcheck.Result, validPath, invalidPath string) error {
+	valid, err := os.Create(validPath)
+	if err != nil {
+		return err
+	}
+	defer valid.Close()
+	invalid, err := os.Create(invalidPath)
+	if err != nil {
+		return err
+	}
+	defer invalid.Close()
+	for _, r := range results {
+		if r.SkipReason != "" {
+			continue
+		}
+		if r.OK {
+			if _, err := fmt.Fprintln(valid, r.URL); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := fmt.Fprintln(invalid, r.URL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeSkipped(results []urlcheck.Result, path string) error {
+	var lines []string
+	for _, r := range results {
+		if r.SkipReason != "" {
+			lines = append(lines, r.URL+"\t"+r.SkipReason)
+		}
+	}
+	if len(lines) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644)
+}
+
+type jsonReport struct {
+	SchemaVersion string                        `json:"schema_version"`
+	Run           *runMeta                      `json:"run,omitempty"`
+	Summary       urlcheck.Summary              `json:"summary"`
+	Histogram     []urlcheck.Bucket             `json:"histogram,omitempty"`
+	Hosts         map[string]urlcheck.HostStats `json:"hosts,omitempty"`
+	Results       []urlcheck.Result             `json:"results"`
+}
+
+func writeJSON(out io.Writer, results []urlcheck.Result) error {
+	return writeJSONReport(out, results, nil, 0, false)
+}
+
+func jsonFormatter(run runMeta, perHost bool) formatter {
+	return func(out io.Writer, results []urlcheck.Result) error {
+		run.Finished = time.Now()
+		return writeJSONReport(out, results, &run, run.Finished.Sub(run.Started), perHost)
+	}
+}
+
+func writeJSONReport(out io.Writer, results []urlcheck.Result, run *runMeta, elapsed time.Duration, perHost bool) error {
+	if results == nil {
+		results = []urlcheck.Result{}
+	}
+	stats := urlcheck.ComputeStats(results, elapsed)
+	report := jsonReport{SchemaVersion: outputSchemaVersion, Run: run, Summary: stats.Summary, Histogram: stats.Histogram, Results: results}
+	if perHost {
+		report.Hosts = stats.Hosts
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+func writeNDJSON(out io.Writer, results []urlcheck.Result) error {
+	enc := json.NewEncoder(out)
+	for _, r := range results {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeTable(out io.Writer, results []urlcheck.Result) error {
+	labels := hasLabels(results)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(tableHeaderFor(labels), "\t"))
+	for _, r := range results {
+		fmt.Fprintln(w, strings.Join(tableRowFor(r, labels), "\t"))
+	}
+	return w.Flush()
+}
+
+var tableHeader = []string{"URL", "STATUS", "OK", "ATTEMPTS", "DURATION", "ERROR"}
+
+func tableRow(r urlcheck.Result) []string {
+	return []string{
+		r.URL,
+		strconv.Itoa(r.Status),
+		strconv.FormatBool(r.OK),
+		strconv.Itoa(r.Attempts),
+		r.Duration.Round(time.Millisecond).String(),
+		errorText(r),
+	}
+}
+
+func tableHeaderFor(labels bool) []string {
+	if labels {
+		return append(slices.Clip(tableHeader), "LABELS")
+	}
+	return tableHeader
+}
+
+func tableRowFor(r urlcheck.Result, labels bool) []string {
+	if labels {
+		return append(tableRow(r), labelText(r.Labels))
+	}
+	return tableRow(r)
+}
+
+func hasLabels(results []urlcheck.Result) bool {
+	for _, r := range results {
+		if len(r.Labels) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func labelText(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for _, k := range slices.Sorted(maps.Keys(labels)) {
+		pairs = append(pairs, k+"="+labels[k])
+	}
+	return strings.Join(pairs, ",")
+}
+
+func writeCSV(out io.Writer, results []urlcheck.Result) error {
+	labels := hasLabels(results)
+	header := []string{"url", "status", "ok", "attempts", "duration", "error"}
+	if labels {
+		header = append(header, "labels")
+	}
+	w := csv.NewWriter(out)
+	if err := w.Write(header); err != nil {
+		return err
+	}
+	for _, r := range results {
+		record := []string{
+			r.URL,
+			strconv.Itoa(r.Status),
+			strconv.FormatBool(r.OK),
+			strconv.Itoa(r.Attempts),
+			strconv.FormatFloat(r.Duration.Seconds(), 'f', 3, 64),
+			errorText(r),
+		}
+		if labels {
+			record = append(record, labelText(r.Labels))
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func errorText(r urlcheck.Result) string {
+	if r.ArchiveURL != "" {
+		return baseErrorText(r) + " (archived: " + r.ArchiveURL + ")"
+	}
+	return baseErrorText(r)
+}
+
+func baseErrorText(r urlcheck.Result) string {
+	if r.SkipReason != "" {
+		return "skipped: " + r.SkipReason
+	}
+	if r.Slow && r.Error == "" {
+		return "slow"
+	}
+	if r.Error == "" && len(r.Warnings) > 0 {
+		return "warning: " + strings.Join(r.Warnings, "; ")
+	}
+	if findings := findingsText(r); findings != "" && r.Error == "" {
+		return findings
+	}
+	if r.Change != "" && r.Error == "" {
+		return strings.ReplaceAll(r.Change, "_", " ")
+	}
+	if r.BaselineStatus != 0 {
+		note := "baseline " + strconv.Itoa(r.BaselineStatus)
+		if r.Error != "" {
+			return r.Error + " (" + note + ")"
+		}
+		return note
+	}
+	if r.Error == "" && len(r.DNSRecords) > 0 {
+		return strings.Join(r.DNSRecords, ", ")
+	}
+	if r.Error == "" && r.GRPCStatus != "" {
+		return r.GRPCStatus
+	}
+	if r.Error == "" && r.Remote != "" {
+		if r.TLSVersion != "" {
+			return "connected to " + r.Remote + " (" + r.TLSVersion + ")"
+		}
+		return "connected to " + r.Remote
+	}
+	if r.Error == "" && r.ContentEncoding != "" {
+		return fmt.Sprintf("%s, %d bytes (%d decoded)", r.ContentEncoding, r.CompressedSize, r.DecodedSize)
+	}
+	return r.Error
+}
+
+func findingsText(r urlcheck.Result) string {
+	var parts []string
+	if len(r.SecurityFindings) > 0 {
+		parts = append(parts, "security: "+strings.Join(r.SecurityFindings, "; "))
+	}
+	if len(r.MixedContent) > 0 {
+		parts = append(parts, "mixed content: "+strings.Join(r.MixedContent, "; "))
+	}
+	if len(r.LinkFindings) > 0 {
+		parts = append(parts, "link tags: "+strings.Join(r.LinkFindings, "; "))
+	}
+	if r.HTTPSUpgrade != "" {
+		parts = append(parts, "https upgrade "+r.HTTPSUpgrade)
+	}
+	return strings.Join(parts, "; ")
+}
+
+func writeRedirectGroups(out io.Writer, results []urlcheck.Result) error {
+	groups := urlcheck.RedirectGroups(results)
+	if len(groups) == 0 {
+		return nil
+	}
+	targets := make([]string, 0, len(groups))
+	for target := range groups {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\nFINAL TARGET\tSOURCES")
+	for _, target := range targets {
+		fmt.Fprintf(w, "%s\t%s\n", target, strings.Join(groups[target], ", "))
+	}
+	return w.Flush()
+}
+
+func writeProviderRollup(out io.Writer, results []urlcheck.Result) error {
+	rollup := urlcheck.ProviderRollup(results)
+	if len(rollup) == 0 {
+		return nil
+	}
+	providers := make([]string, 0, len(rollup))
+	for provider := range rollup {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\nPROVIDER\tTOTAL\tFAILED")
+	for _, provider := range providers {
+		stats := rollup[provider]
+		fmt.Fprintf(w, "%s\t%d\t%d\n", provider, stats.Total, stats.Failed)
+	}
+	return w.Flush()
+}
+
+func writeCurlCommands(out io.Writer, results []urlcheck.Result) error {
+	header := false
+	for _, r := range results {
+		if r.Curl == "" {
+			continue
+		}
+		if !header {
+			if _, err := fmt.Fprintln(out, "\nREPRODUCE"); err != nil {
+				return err
+			}
+			header = true
+		}
+		if _, err := fmt.Fprintf(out, "# %s\n%s\n", r.URL, r.Curl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeSummary(out io.Writer, s urlcheck.Stats) error {
+	partial := ""
+	if s.Partial {
+		partial = " (partial run)"
+	}
+	warned := ""
+	if s.Warnings > 0 {
+		warned = fmt.Sprintf(" (%d with warnings)", s.Warnings)
+	}
+	if _, err := fmt.Fprintf(out, "\ntotal %d, ok %d%s, broken %d, errored %d, skipped %d; p50 %s, p95 %s; took %s%s\n",
+		s.Total, s.OK, warned, s.Broken, s.Errored, s.Skipped,
+		s.P50.Round(time.Millisecond), s.P95.Round(time.Millisecond), s.TotalDuration.Round(time.Millisecond), partial); err != nil {
+		return err
+	}
+	if len(s.StatusClasses) == 0 {
+		return nil
+	}
+	line := "status " + countText(s.StatusClasses)
+	if len(s.ErrorKinds) > 0 {
+		kinds := make(map[string]int, len(s.ErrorKinds))
+		for k, n := range s.ErrorKinds {
+			kinds[string(k)] = n
+		}
+		line += "; errors " + countText(kinds)
+	}
+	if _, err := fmt.Fprintf(out, "%s; p90 %s, p99 %s, max %s\n", line,
+		s.Latency.P90.Round(time.Millisecond), s.Latency.P99.Round(time.Millisecond), s.Latency.Max.Round(time.Millisecond)); err != nil {
+		return err
+	}
+	return writeHistogram(out, "latency", s.Histogram)
+}
+
+const histogramWidth = 40
+
+func writeHistogram(out io.Writer, title string, buckets []urlcheck.Bucket) error {
+	first, last, peak := -1, -1, 0
+	for i, b := range buckets {
+		if b.Count == 0 {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		last = i
+		peak = max(peak, b.Count)
+	}
+	if first < 0 {
+		return nil
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "\n%s histogram\n", title)
+	for _, b := range buckets[first : last+1] {
+		bar := b.Count * histogramWidth / peak
+		if b.Count > 0 {
+			bar = max(bar, 1)
+		}
+		fmt.Fprintf(w, "  <= %s\t%d\t%s\n", b.Le, b.Count, strings.Repeat("#", bar))
+	}
+	return w.Flush()
+}
+
+func countText(counts map[string]int) string {
+	parts := make([]string, 0, len(counts))
+	for _, k := range slices.Sorted(maps.Keys(counts)) {
+		parts = append(parts, k+" "+strconv.Itoa(counts[k]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func writeHostStats(out io.Writer, s urlcheck.Stats) error {
+	if len(s.Hosts) == 0 {
+		return nil
+	}
+	hosts := slices.Sorted(maps.Keys(s.Hosts))
+	slices.SortStableFunc(hosts, func(a, b string) int {
+		return s.Hosts[b].Failed - s.Hosts[a].Failed
+	})
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\nHOST\tTOTAL\tOK\tFAILED\tP50\tP95\tFAIL%\tMEAN\tTOP ERRORS")
+	for _, host := range hosts {
+		h := s.Hosts[host]
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\t%s\t%.1f\t%s\t%s\n", host, h.Total, h.OK, h.Failed, h.P50.Round(time.Millisecond), h.P95.Round(time.Millisecond),
+			h.ErrorRate*100, h.Mean.Round(time.Millisecond), topErrors(h.Errors, 3))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	for _, host := range hosts {
+		if err := writeHistogram(out, host, s.Hosts[host].Histogram); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func topErrors(counts map[string]int, n int) string {
+	if len(counts) == 0 {
+		return "-"
+	}
+	causes := slices.Sorted(maps.Keys(counts))
+	slices.SortStableFunc(causes, func(a, b string) int {
+		return counts[b] - counts[a]
+	})
+	parts := make([]string, 0, n)
+	for _, c := range causes[:min(n, len(causes))] {
+		parts = append(parts, c+" "+strconv.Itoa(counts[c]))
+	}
+	return strings.Join(parts, ", ")
+}
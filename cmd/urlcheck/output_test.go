@@ -0,0 +1,305 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestWriteCSVQuotes(t *testing.T) {
+	results := []urlcheck.Result{
+		{URL: "https://ok.example", OK: true, Status: 200, Attempts: 1, Duration: 1500 * time.Millisecond},
+		{URL: "https://bad.example/?a=1,2", Attempts: 2, Error: `dial "tcp": refused`},
+	}
+	var buf bytes.Buffer
+	if err := writeCSV(&buf, results); err != nil {
+		t.Fatalf("writeCSV: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected header and two rows, got %d", len(records))
+	}
+	if got := records[0]; got[0] != "url" || got[4] != "duration" || got[5] != "error" {
+		t.Fatalf("unexpected header: %v", got)
+	}
+	if got := records[1]; got[1] != "200" || got[2] != "true" || got[4] != "1.500" {
+		t.Fatalf("unexpected first row: %v", got)
+	}
+	if got := records[2]; got[0] != "https://bad.example/?a=1,2" || got[5] != `dial "tcp": refused` {
+		t.Fatalf("unexpected second row: %v", got)
+	}
+}
+
+func TestWriteProviderRollup(t *testing.T) {
+	results := []urlcheck.Result{
+		{URL: "https://a.example", OK: true, Fingerprint: &urlcheck.Fingerprint{Provider: "Fastly"}},
+		{URL: "https://b.example", Fingerprint: &urlcheck.Fingerprint{Provider: "Fastly"}},
+		{URL: "https://c.example", OK: true, Fingerprint: &urlcheck.Fingerprint{}},
+	}
+	var buf bytes.Buffer
+	if err := writeProviderRollup(&buf, results); err != nil {
+		t.Fatalf("writeProviderRollup: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "Fastly    2      1") || !strings.Contains(out, "unknown   1      0") {
+		t.Fatalf("unexpected rollup: %q", out)
+	}
+}
+
+func TestWriteCurlCommands(t *testing.T) {
+	results := []urlcheck.Result{
+		{URL: "https://ok.example", OK: true},
+		{URL: "https://bad.example", Curl: "curl -sSv -o /dev/null https://bad.example"},
+	}
+	var buf bytes.Buffer
+	if err := writeCurlCommands(&buf, results); err != nil {
+		t.Fatalf("writeCurlCommands: %v", err)
+	}
+	if got := buf.String(); got != "\nREPRODUCE\n# https://bad.example\ncurl -sSv -o /dev/null https://bad.example\n" {
+		t.Fatalf("unexpected output: %q", got)
+	}
+}
+
+func TestWriteJSONIncludesSummary(t *testing.T) {
+	results := []urlcheck.Result{
+		{URL: "https://ok.example", OK: true, Status: 200, Duration: 10 * time.Millisecond},
+		{URL: "https://bad.example", Status: 500, Duration: 30 * time.Millisecond},
+	}
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, results); err != nil {
+		t.Fatalf("writeJSON: %v", err)
+	}
+	var report jsonReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("parse json: %v", err)
+	}
+	if len(report.Results) != 2 || report.Summary.OK != 1 || report.Summary.Broken != 1 || report.Summary.P95 != 30*time.Millisecond {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+}
+
+func TestWriteSummaryFooter(t *testing.T) {
+	var buf bytes.Buffer
+	s := urlcheck.Stats{Summary: urlcheck.Summary{Total: 3, OK: 1, Broken: 1, Skipped: 1, P50: 12 * time.Millisecond, P95: 40 * time.Millisecond, TotalDuration: 2 * time.Second}}
+	if err := writeSummary(&buf, s); err != nil {
+		t.Fatalf("writeSummary: %v", err)
+	}
+	want := "total 3, ok 1, broken 1, errored 0, skipped 1; p50 12ms, p95 40ms; took 2s"
+	if !strings.Contains(buf.String(), want) {
+		t.Fatalf("unexpected footer: %q", buf.String())
+	}
+}
+
+func TestWriteSummaryPartial(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeSummary(&buf, urlcheck.Stats{Summary: urlcheck.Summary{Total: 2, OK: 1, Skipped: 1, Partial: true}}); err != nil {
+		t.Fatalf("writeSummary: %v", err)
+	}
+	if !strings.HasSuffix(buf.String(), "(partial run)\n") {
+		t.Fatalf("partial run not marked: %q", buf.String())
+	}
+}
+
+func TestWriteSummaryBreakdown(t *testing.T) {
+	results := []urlcheck.Result{
+		{URL: "https://a.example/", OK: true, Status: 200, Duration: 10 * time.Millisecond},
+		{URL: "https://a.example/x", Status: 404, Error: "status 404", Duration: 20 * time.Millisecond},
+		{URL: "https://b.example/", Error: "timeout", ErrorKind: urlcheck.KindTimeout, Duration: 50 * time.Millisecond},
+	}
+	var buf bytes.Buffer
+	if err := writeSummary(&buf, urlcheck.ComputeStats(results, time.Second)); err != nil {
+		t.Fatalf("writeSummary: %v", err)
+	}
+	want := "status 2xx 1, 4xx 1, none 1; errors timeout 1; p90 50ms, p99 50ms, max 50ms"
+	if !strings.Contains(buf.String(), want) {
+		t.Fatalf("missing breakdown %q in %q", want, buf.String())
+	}
+}
+
+func TestTopErrors(t *testing.T) {
+	counts := map[string]int{"timeout": 2, "http_404": 5, "connection": 2, "http_500": 1}
+	if got := topErrors(counts, 3); got != "http_404 5, connection 2, timeout 2" {
+		t.Fatalf("unexpected top errors %q", got)
+	}
+	if got := topErrors(nil, 3); got != "-" {
+		t.Fatalf("unexpected top errors for no failures %q", got)
+	}
+}
+
+func TestWriteHistogram(t *testing.T) {
+	buckets := []urlcheck.Bucket{{Le: "10ms"}, {Le: "25ms", Count: 4}, {Le: "50ms"}, {Le: "100ms", Count: 1}, {Le: "+Inf"}}
+	var buf bytes.Buffer
+	if err := writeHistogram(&buf, "latency", buckets); err != nil {
+		t.Fatalf("writeHistogram: %v", err)
+	}
+	want := "\nlatency histogram\n" +
+		"  <= 25ms   4  " + strings.Repeat("#", histogramWidth) + "\n" +
+		"  <= 50ms   0  \n" +
+		"  <= 100ms  1  " + strings.Repeat("#", histogramWidth/4) + "\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected histogram:\n%q\nwant\n%q", buf.String(), want)
+	}
+	buf.Reset()
+	if err := writeHistogram(&buf, "latency", nil); err != nil || buf.Len() != 0 {
+		t.Fatalf("expected no output for an empty histogram, got %q (%v)", buf.String(), err)
+	}
+}
+
+func TestWriteJSONReportHistograms(t *testing.T) {
+	results := []urlcheck.Result{
+		{URL: "https://a.example/", OK: true, Status: 200, Duration: 10 * time.Millisecond},
+		{URL: "https://b.example/", Status: 500, Duration: 300 * time.Millisecond},
+	}
+	for _, perHost := range []bool{false, true} {
+		var buf bytes.Buffer
+		if err := writeJSONReport(&buf, results, nil, time.Second, perHost); err != nil {
+			t.Fatalf("writeJSONReport: %v", err)
+		}
+		var report jsonReport
+		if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+			t.Fatalf("parse json: %v", err)
+		}
+		if report.Histogram[0].Count != 1 || report.Histogram[5] != (urlcheck.Bucket{Le: "500ms", Count: 1}) {
+			t.Fatalf("unexpected histogram: %+v", report.Histogram)
+		}
+		if got := len(report.Hosts); perHost != (got == 2) || (!perHost && got != 0) {
+			t.Fatalf("perHost=%v: unexpected hosts %+v", perHost, report.Hosts)
+		}
+	}
+}
+
+func TestWriteHostStats(t *testing.T) {
+	results := []urlcheck.Result{
+		{URL: "https://a.example/", OK: true, Status: 200, Duration: 10 * time.Millisecond},
+		{URL: "https://b.example/", Status: 500, Duration: 30 * time.Millisecond},
+	}
+	var buf bytes.Buffer
+	if err := writeHostStats(&buf, urlcheck.ComputeStats(results, 0)); err != nil {
+		t.Fatalf("writeHostStats: %v", err)
+	}
+	out := buf.String()
+	if strings.Index(out, "b.example") > strings.Index(out, "a.example") {
+		t.Fatalf("hosts with failures should come first:\n%s", out)
+	}
+	for _, want := range []string{"HOST", "a.example  1      1   0       10ms  10ms  0.0    10ms  -", "b.example  1      0   1       30ms  30ms  100.0  30ms  http_500 1", "b.example histogram\n  <= 50ms  1  "} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestLabelsAppearInOutputs(t *testing.T) {
+	results := []urlcheck.Result{
+		{URL: "https://a.example", OK: true, Status: 200},
+		{URL: "https://b.example", Status: 500, Error: "status 500", Labels: map[string]string{"team": "payments", "env": "prod"}},
+	}
+	for format, want := range map[string]string{
+		"table": "env=prod,team=payments",
+		"csv":   ",labels\n",
+		"junit": `<property name="team" value="payments"></property>`,
+		"sarif": `"team": "payments"`,
+		"tap":   `"team": "payments"`,
+		"json":  `"team": "payments"`,
+	} {
+		var buf bytes.Buffer
+		if err := formats[format](&buf, results); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("%s output is missing %q:\n%s", format, want, buf.String())
+		}
+	}
+	var buf bytes.Buffer
+	if err := writeTable(&buf, results[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "LABELS") {
+		t.Fatalf("unlabelled results should not get a labels column: %s", buf.String())
+	}
+}
+
+func TestTableShowsSecurityFindings(t *testing.T) {
+	var buf bytes.Buffer
+	results := []urlcheck.Result{{URL: "https://a.example", OK: true, Status: 200, SecurityFindings: []string{"missing Content-Security-Policy", "missing Referrer-Policy"}}}
+	if err := writeTable(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+	if want := "security: missing Content-Security-Policy; missing Referrer-Policy"; !strings.Contains(buf.String(), want) {
+		t.Fatalf("missing %q in %q", want, buf.String())
+	}
+}
+
+func TestTableShowsFindings(t *testing.T) {
+	var buf bytes.Buffer
+	results := []urlcheck.Result{{URL: "https://a.example", OK: true, Status: 200, SecurityFindings: []string{"missing Referrer-Policy"}, MixedContent: []string{"line 3: <script src> http://cdn.example/a.js"}, LinkFindings: []string{"hreflang de https://a.example/de has no return link"}}}
+	if err := writeTable(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+	if want := "security: missing Referrer-Policy; mixed content: line 3: <script src> http://cdn.example/a.js; link tags: hreflang de https://a.example/de has no return link"; !strings.Contains(buf.String(), want) {
+		t.Fatalf("missing %q in %q", want, buf.String())
+	}
+}
+
+func TestErrorTextShowsDNSRecords(t *testing.T) {
+	r := urlcheck.Result{URL: "https://a.example", OK: true, DNSRecords: []string{"CNAME edge.example", "A 192.0.2.1"}}
+	if got, want := errorText(r), "CNAME edge.example, A 192.0.2.1"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestErrorTextShowsConnection(t *testing.T) {
+	r := urlcheck.Result{URL: "tls://a.example:465", OK: true, Remote: "192.0.2.1:465", TLSVersion: "TLS 1.3"}
+	if got, want := errorText(r), "connected to 192.0.2.1:465 (TLS 1.3)"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestErrorTextShowsGRPCStatus(t *testing.T) {
+	r := urlcheck.Result{URL: "grpc://api.example:50051", OK: true, GRPCStatus: "SERVING"}
+	if got := errorText(r); got != "SERVING" {
+		t.Fatalf("got %q, want SERVING", got)
+	}
+}
+
+func TestErrorTextIncludesArchive(t *testing.T) {
+	r := urlcheck.Result{URL: "https://gone.example", Status: 404, Error: "status 404", ArchiveURL: "http://web.archive.org/web/2020/https://gone.example"}
+	if got, want := errorText(r), "status 404 (archived: http://web.archive.org/web/2020/https://gone.example)"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestErrorTextShowsContentEncoding(t *testing.T) {
+	r := urlcheck.Result{URL: "https://a.example", OK: true, ContentEncoding: "br", CompressedSize: 812, DecodedSize: 4096}
+	if got := errorText(r); got != "br, 812 bytes (4096 decoded)" {
+		t.Fatalf("unexpected error text %q", got)
+	}
+}
+
+func TestErrorTextShowsHTTPSUpgrade(t *testing.T) {
+	r := urlcheck.Result{URL: "http://a.example", OK: true, Status: 200, HTTPSUpgrade: urlcheck.UpgradeAvailable}
+	if got := errorText(r); got != "https upgrade available" {
+		t.Fatalf("unexpected error text %q", got)
+	}
+}
+
+func TestErrorTextShowsWarnings(t *testing.T) {
+	r := urlcheck.Result{URL: "https://a.example", OK: true, Status: 200, Severity: urlcheck.SeverityWarn, Warnings: []string{"permanent redirect to https://b.example/"}}
+	if got := errorText(r); got != "warning: permanent redirect to https://b.example/" {
+		t.Fatalf("unexpected error text %q", got)
+	}
+	var out bytes.Buffer
+	if err := writeSummary(&out, urlcheck.ComputeStats([]urlcheck.Result{r, {URL: "https://c.example", OK: true, Severity: urlcheck.SeverityOK}}, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "total 2, ok 2 (1 with warnings), broken 0") {
+		t.Fatalf("unexpected summary %q", out.String())
+	}
+}
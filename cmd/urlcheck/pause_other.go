@@ -0,0 +1,9 @@
+//go:build !unix
+
+package main
+
+import "github.com/reisei231/go-url-checker/internal/urlcheck"
+
+func pauseOnSignals(checker *urlcheck.Checker) func() {
+	return func() {}
+}
@@ -0,0 +1,38 @@
+//go:build unix
+
+package main
+
+import (
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func pauseOnSignals(checker *urlcheck.Checker) func() {
+	sig := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(sig, syscall.SIGUSR1, syscall.SIGUSR2)
+	go func() {
+		for {
+			select {
+			case s := <-sig:
+				if s == syscall.SIGUSR1 {
+					checker.Pause()
+					slog.Info("paused: no new checks will start until SIGUSR2")
+					continue
+				}
+				checker.Resume()
+				slog.Info("resumed")
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(sig)
+		close(done)
+	}
+}
@@ -0,0 +1,35 @@
+//go:build unix
+
+package main
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestPauseOnSignals(t *testing.T) {
+	checker := urlcheck.NewChecker(1, time.Second, 0, nil)
+	stop := pauseOnSignals(checker)
+	defer stop()
+	waitPaused := func(want bool) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for checker.Paused() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected paused=%v", want)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+	waitPaused(true)
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR2); err != nil {
+		t.Fatal(err)
+	}
+	waitPaused(false)
+}
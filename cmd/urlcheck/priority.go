@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+const priorityHistoryRuns = 20
+
+// parsePriority builds the -priority ranking. labels looks up a url's input
+// labels when the ranking runs, so watchers see the set from their last reload.
+func parsePriority(spec string, labels func(string) map[string]string, historyPath string) (func(string) int, error) {
+	if spec == "" {
+		return nil, nil
+	}
+	kind, arg, _ := strings.Cut(spec, ":")
+	switch kind {
+	case "failures-first":
+		if historyPath == "" {
+			return nil, fmt.Errorf("-priority failures-first needs -history")
+		}
+		db, err := openHistory(historyPath)
+		if err != nil {
+			return nil, err
+		}
+		defer db.Close()
+		recent, err := recentFailures(db, priorityHistoryRuns)
+		if err != nil {
+			return nil, err
+		}
+		failures := make(map[string]int, len(recent))
+		for u, n := range recent {
+			failures[normalizedKey(u)] += n
+		}
+		return func(u string) int {
+			return -failures[normalizedKey(u)]
+		}, nil
+	case "label":
+		key, list, ok := strings.Cut(arg, "=")
+		if !ok || key == "" || list == "" {
+			return nil, fmt.Errorf("-priority label needs KEY=VALUE[,VALUE...], got %q", arg)
+		}
+		values := strings.Split(list, ",")
+		return func(u string) int {
+			if i := slices.Index(values, labels(u)[key]); i >= 0 {
+				return i
+			}
+			return len(values)
+		}, nil
+	case "domains":
+		domains, err := priorityDomains(arg)
+		if err != nil {
+			return nil, err
+		}
+		return func(u string) int {
+			host := hostOf(u)
+			for i, d := range domains {
+				if host == d || strings.HasSuffix(host, "."+d) {
+					return i
+				}
+			}
+			return len(domains)
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown -priority %q (want failures-first|label:KEY=VALUE,...|domains:LIST-OR-FILE)", spec)
+}
+
+func normalizedKey(u string) string {
+	if normalized, _, err := urlcheck.NormalizeURL(u); err == nil {
+		return normalized
+	}
+	return u
+}
+
+func priorityDomains(arg string) ([]string, error) {
+	items := strings.Split(arg, ",")
+	if data, err := os.ReadFile(arg); err == nil {
+		items = strings.Split(string(data), "\n")
+	}
+	var domains []string
+	for _, d := range items {
+		d = strings.ToLower(strings.TrimSpace(d))
+		if d != "" && !strings.HasPrefix(d, "#") {
+			domains = append(domains, d)
+		}
+	}
+	if len(domains) == 0 {
+		return nil, fmt.Errorf("-priority domains needs a comma-separated list or a file of domains")
+	}
+	return domains, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestParsePriorityLabel(t *testing.T) {
+	prov := provenance{
+		"https://a.example/": {Labels: map[string]string{"tier": "high"}},
+		"https://b.example/": {Labels: map[string]string{"tier": "critical"}},
+	}
+	rank, err := parsePriority("label:tier=critical,high", func(u string) map[string]string { return prov[u].Labels }, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rank("https://b.example/") != 0 || rank("https://a.example/") != 1 || rank("https://c.example/") != 2 {
+		t.Fatal("unexpected label ranks")
+	}
+}
+
+func TestParsePriorityDomains(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "domains.txt")
+	if err := os.WriteFile(file, []byte("# most important first\napi.example.com\n\nexample.org\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, arg := range []string{"api.example.com,example.org", file} {
+		rank, err := parsePriority("domains:"+arg, nil, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rank("https://API.example.com/v1") != 0 || rank("https://www.example.org/") != 1 || rank("https://example.com/") != 2 {
+			t.Fatalf("unexpected domain ranks for %s", arg)
+		}
+	}
+}
+
+func TestParsePriorityFailuresFirst(t *testing.T) {
+	db := filepath.Join(t.TempDir(), "history.db")
+	started := time.Now()
+	for _, ok := range []bool{false, true} {
+		results := []urlcheck.Result{
+			{URL: "https://flaky.example/", OK: ok},
+			{URL: "https://down.example/"},
+			{URL: "https://up.example/", OK: true},
+		}
+		if err := saveHistory(db, started, results); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rank, err := parsePriority("failures-first", nil, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rank("HTTPS://Down.Example/") != -2 || rank("https://flaky.example/") != -1 || rank("https://up.example/") != 0 || rank("https://new.example/") != 0 {
+		t.Fatal("unexpected failure ranks")
+	}
+}
+
+func TestParsePriorityErrors(t *testing.T) {
+	for spec, want := range map[string]string{
+		"random":         "unknown -priority",
+		"failures-first": "needs -history",
+		"label:tier":     "needs KEY=VALUE",
+		"domains:,":      "needs a comma-separated list",
+	} {
+		if _, err := parsePriority(spec, nil, ""); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", spec, want, err)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+const progressInterval = 100 * time.Millisecond
+
+type progressReporter interface {
+	update(urlcheck.Result)
+	retrying(url string, attempt int, err error)
+	finish(urlcheck.Summary)
+}
+
+type progress struct {
+	out      io.Writer
+	total    int
+	done     int
+	failed   int
+	start    time.Time
+	lastDraw time.Time
+	now      func() time.Time
+}
+
+func newProgress(out io.Writer, total int) *progress {
+	return &progress{out: out, total: total, start: time.Now(), now: time.Now}
+}
+
+func (p *progress) update(r urlcheck.Result) {
+	p.done++
+	if failed(r) {
+		p.failed++
+	}
+	now := p.now()
+	if p.done < p.total && now.Sub(p.lastDraw) < progressInterval {
+		return
+	}
+	p.lastDraw = now
+	p.draw(now)
+}
+
+func (p *progress) draw(now time.Time) {
+	rate, eta := throughput(p.done, p.total, now.Sub(p.start))
+	fmt.Fprintf(p.out, "\r\x1b[K%d/%d checked, %d failed, %.1f req/s, ETA %s", p.done, p.total, p.failed, rate, eta)
+}
+
+func throughput(done, total int, elapsed time.Duration) (float64, string) {
+	rate := 0.0
+	if elapsed > 0 {
+		rate = float64(done) / elapsed.Seconds()
+	}
+	eta := "?"
+	if rate > 0 {
+		eta = time.Duration(float64(total-done) / rate * float64(time.Second)).Round(time.Second).String()
+	}
+	return rate, eta
+}
+
+func (p *progress) retrying(string, int, error) {}
+
+func (p *progress) finish(urlcheck.Summary) {
+	fmt.Fprint(p.out, "\r\x1b[K")
+}
+
+type progressEvent struct {
+	Event   string            `json:"event"`
+	Time    time.Time         `json:"time"`
+	Total   int               `json:"total"`
+	Done    int               `json:"done"`
+	Failed  int               `json:"failed"`
+	URL     string            `json:"url,omitempty"`
+	Attempt int               `json:"attempt,omitempty"`
+	Error   string            `json:"error,omitempty"`
+	Result  *urlcheck.Result  `json:"result,omitempty"`
+	Summary *urlcheck.Summary `json:"summary,omitempty"`
+}
+
+type jsonProgress struct {
+	mu     sync.Mutex
+	enc    *json.Encoder
+	total  int
+	done   int
+	failed int
+	now    func() time.Time
+}
+
+func newJSONProgress(out io.Writer, total int) *jsonProgress {
+	p := &jsonProgress{enc: json.NewEncoder(out), total: total, now: time.Now}
+	p.emit(progressEvent{Event: "started"})
+	return p
+}
+
+func (p *jsonProgress) emit(ev progressEvent) {
+	ev.Time = p.now()
+	ev.Total, ev.Done, ev.Failed = p.total, p.done, p.failed
+	p.enc.Encode(ev)
+}
+
+func (p *jsonProgress) update(r urlcheck.Result) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	if failed(r) {
+		p.failed++
+	}
+	p.emit(progressEvent{Event: "completed", URL: r.URL, Result: &r})
+}
+
+func (p *jsonProgress) retrying(url string, attempt int, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.emit(progressEvent{Event: "retrying", URL: url, Attempt: attempt, Error: err.Error()})
+}
+
+func (p *jsonProgress) finish(s urlcheck.Summary) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.emit(progressEvent{Event: "summary", Summary: &s})
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestProgressDrawsThrottledLine(t *testing.T) {
+	var buf bytes.Buffer
+	p := newProgress(&buf, 4)
+	now := p.start
+	p.now = func() time.Time { return now }
+	now = now.Add(time.Second)
+	p.update(urlcheck.Result{OK: true})
+	first := buf.String()
+	if !strings.Contains(first, "1/4 checked, 0 failed, 1.0 req/s, ETA 3s") {
+		t.Fatalf("unexpected progress line: %q", first)
+	}
+	p.update(urlcheck.Result{OK: false})
+	if buf.String() != first {
+		t.Fatalf("expected throttled redraw, got %q", buf.String())
+	}
+	now = now.Add(time.Second)
+	p.update(urlcheck.Result{OK: true})
+	p.update(urlcheck.Result{SkipReason: "excluded"})
+	if !strings.HasSuffix(buf.String(), "4/4 checked, 1 failed, 2.0 req/s, ETA 0s") {
+		t.Fatalf("expected final line, got %q", buf.String())
+	}
+}
+
+func TestJSONProgressEmitsEvents(t *testing.T) {
+	var buf bytes.Buffer
+	p := newJSONProgress(&buf, 2)
+	p.retrying("https://a.example", 1, errors.New("connection reset"))
+	p.update(urlcheck.Result{URL: "https://a.example", OK: true})
+	p.update(urlcheck.Result{URL: "https://b.example", Status: 500})
+	p.finish(urlcheck.Summary{Total: 2, OK: 1, Broken: 1})
+	dec := json.NewDecoder(&buf)
+	var events []progressEvent
+	for dec.More() {
+		var ev progressEvent
+		if err := dec.Decode(&ev); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		events = append(events, ev)
+	}
+	kinds := make([]string, len(events))
+	for i, ev := range events {
+		kinds[i] = ev.Event
+	}
+	if strings.Join(kinds, ",") != "started,retrying,completed,completed,summary" {
+		t.Fatalf("unexpected events: %v", kinds)
+	}
+	if events[1].Attempt != 1 || events[1].Error != "connection reset" {
+		t.Fatalf("unexpected retry event: %+v", events[1])
+	}
+	last := events[4]
+	if last.Done != 2 || last.Failed != 1 || last.Summary == nil || last.Summary.Broken != 1 {
+		t.Fatalf("unexpected summary event: %+v", last)
+	}
+}
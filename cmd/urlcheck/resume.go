@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+type checkpointFile struct {
+	file *os.File
+	enc  *json.Encoder
+	err  error
+}
+
+func openCheckpoint(path string, resume bool) (*checkpointFile, error) {
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if resume {
+		flags = os.O_CREATE | os.O_RDWR
+	}
+	f, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if resume {
+		if err := dropPartialLine(f); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	return &checkpointFile{file: f, enc: json.NewEncoder(f)}, nil
+}
+
+// dropPartialLine cuts a record left half-written by a killed run, so the
+// next record starts on its own line, and leaves f positioned at the end.
+func dropPartialLine(f *os.File) error {
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return err
+	}
+	end := int64(bytes.LastIndexByte(data, '\n') + 1)
+	if end < int64(len(data)) {
+		if err := f.Truncate(end); err != nil {
+			return err
+		}
+	}
+	_, err = f.Seek(end, io.SeekStart)
+	return err
+}
+
+func (c *checkpointFile) record(r urlcheck.Result) {
+	if c.err != nil || r.ErrorKind == urlcheck.KindNotAttempted {
+		return
+	}
+	c.err = c.enc.Encode(r)
+}
+
+func (c *checkpointFile) close() error {
+	if err := c.file.Close(); c.err == nil {
+		c.err = err
+	}
+	return c.err
+}
+
+func loadCheckpoint(path string) (map[string]urlcheck.Result, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]urlcheck.Result{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	done := map[string]urlcheck.Result{}
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	skipped := 0
+	for sc.Scan() {
+		var r urlcheck.Result
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			skipped++
+			continue
+		}
+		if r.URL != "" && r.ErrorKind != urlcheck.KindNotAttempted {
+			done[r.URL] = r
+		}
+	}
+	if skipped > 0 {
+		slog.Warn("skipped unreadable checkpoint lines; those urls will be checked again", "file", path, "lines", skipped)
+	}
+	return done, sc.Err()
+}
+
+func pendingURLs(urls []string, done map[string]urlcheck.Result) []string {
+	if len(done) == 0 {
+		return urls
+	}
+	var pending []string
+	for _, u := range urls {
+		if _, ok := done[u]; !ok {
+			pending = append(pending, u)
+		}
+	}
+	return pending
+}
+
+func mergeResumed(urls []string, done map[string]urlcheck.Result, results []urlcheck.Result) []urlcheck.Result {
+	if len(done) == 0 {
+		return results
+	}
+	merged := make([]urlcheck.Result, 0, len(urls))
+	next := 0
+	for _, u := range urls {
+		if r, ok := done[u]; ok {
+			merged = append(merged, r)
+			continue
+		}
+		if next < len(results) {
+			merged = append(merged, results[next])
+			next++
+		}
+	}
+	return merged
+}
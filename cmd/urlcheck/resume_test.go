@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestCheckpointResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.ckpt")
+	c, err := openCheckpoint(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.record(urlcheck.Result{URL: "https://a.example", OK: true, Status: 200})
+	c.record(urlcheck.Result{URL: "https://b.example", Status: 500})
+	c.record(urlcheck.Result{URL: "https://c.example", SkipReason: "not attempted (interrupted)", ErrorKind: urlcheck.KindNotAttempted})
+	if err := c.close(); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"url":"https://d.exa`)
+	f.Close()
+
+	done, err := loadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("loadCheckpoint: %v", err)
+	}
+	if len(done) != 2 || done["https://b.example"].Status != 500 {
+		t.Fatalf("unexpected checkpoint contents: %+v", done)
+	}
+	urls := []string{"https://a.example", "https://b.example", "https://c.example", "https://d.example"}
+	pending := pendingURLs(urls, done)
+	if want := []string{"https://c.example", "https://d.example"}; !reflect.DeepEqual(pending, want) {
+		t.Fatalf("pending = %v, want %v", pending, want)
+	}
+	fresh := []urlcheck.Result{{URL: "https://c.example", OK: true, Status: 200}, {URL: "https://d.example", OK: true, Status: 204}}
+	merged := mergeResumed(urls, done, fresh)
+	var got []string
+	for _, r := range merged {
+		got = append(got, r.URL)
+	}
+	if !reflect.DeepEqual(got, urls) {
+		t.Fatalf("merged order = %v, want %v", got, urls)
+	}
+}
+
+func TestCheckpointTruncatesWithoutResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.ckpt")
+	if err := os.WriteFile(path, []byte(`{"url":"https://old.example","ok":true,"status":200}`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := openCheckpoint(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.close()
+	done, err := loadCheckpoint(path)
+	if err != nil || len(done) != 0 {
+		t.Fatalf("expected an empty checkpoint, got %v, %v", done, err)
+	}
+	if done, err := loadCheckpoint(filepath.Join(t.TempDir(), "missing")); err != nil || len(done) != 0 {
+		t.Fatalf("missing checkpoint should resume from scratch, got %v, %v", done, err)
+	}
+}
+
+func TestCheckpointResumeDropsPartialLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.ckpt")
+	if err := os.WriteFile(path, []byte(`{"url":"https://a.example","ok":true,"status":200}`+"\n"+`{"url":"https://b.exa`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := openCheckpoint(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.record(urlcheck.Result{URL: "https://b.example", OK: true, Status: 204})
+	if err := c.close(); err != nil {
+		t.Fatal(err)
+	}
+	done, err := loadCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != 2 || done["https://b.example"].Status != 204 {
+		t.Fatalf("the record after a partial line was lost: %+v", done)
+	}
+}
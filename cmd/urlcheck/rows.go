@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+type inputRow struct {
+	url      string
+	override urlcheck.HostOverride
+	labels   map[string]string
+}
+
+func isRowInput(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv", ".json":
+		return true
+	}
+	return false
+}
+
+func loadInputRows(path string) ([]inputRow, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	read := readCSVRows
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		read = readJSONRows
+	}
+	rows, err := read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rows, nil
+}
+
+var rowColumns = map[string]bool{"url": true, "timeout": true, "retries": true, "method": true}
+
+func readCSVRows(r io.Reader) ([]inputRow, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	cols := map[string]int{}
+	for i, name := range header {
+		cols[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := cols["url"]; !ok {
+		return nil, fmt.Errorf("header must have a url column")
+	}
+	field := func(record []string, name string) string {
+		if i, ok := cols[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	var rows []inputRow
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		u := field(record, "url")
+		if u == "" {
+			continue
+		}
+		var retries *int
+		if v := field(record, "retries"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid retries %q", u, v)
+			}
+			retries = &n
+		}
+		row, err := newInputRow(u, field(record, "timeout"), retries, field(record, "method"))
+		if err != nil {
+			return nil, err
+		}
+		for name := range cols {
+			if v := field(record, name); !rowColumns[name] && v != "" {
+				if row.labels == nil {
+					row.labels = map[string]string{}
+				}
+				row.labels[name] = v
+			}
+		}
+		rows = append(rows, row)
+	}
+}
+
+func readJSONRows(r io.Reader) ([]inputRow, error) {
+	var entries []struct {
+		URL     string            `json:"url"`
+		Timeout string            `json:"timeout"`
+		Retries *int              `json:"retries"`
+		Method  string            `json:"method"`
+		Labels  map[string]string `json:"labels"`
+	}
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, err
+	}
+	rows := make([]inputRow, 0, len(entries))
+	for i, e := range entries {
+		if e.URL == "" {
+			return nil, fmt.Errorf("entry %d: url is required", i)
+		}
+		row, err := newInputRow(e.URL, e.Timeout, e.Retries, e.Method)
+		if err != nil {
+			return nil, err
+		}
+		if len(e.Labels) > 0 {
+			row.labels = e.Labels
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func newInputRow(u, timeout string, retries *int, method string) (inputRow, error) {
+	row := inputRow{url: u, override: urlcheck.HostOverride{URL: u, Retries: retries, Method: strings.ToUpper(method)}}
+	if timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil {
+			return row, fmt.Errorf("%s: invalid timeout %q", u, timeout)
+		}
+		row.override.Timeout = d
+	}
+	if retries != nil && *retries < 0 {
+		return row, fmt.Errorf("%s: invalid retries %d", u, *retries)
+	}
+	return row, nil
+}
+
+func (r inputRow) overrides() bool {
+	return r.override.Timeout > 0 || r.override.Retries != nil || r.override.Method != ""
+}
+
+func rowOverrides(rows []inputRow) []urlcheck.HostOverride {
+	var overrides []urlcheck.HostOverride
+	for _, r := range rows {
+		if r.overrides() {
+			overrides = append(overrides, r.override)
+		}
+	}
+	return overrides
+}
+
+func rowSource(label string, rows []inputRow) urlcheck.Source {
+	i := 0
+	return urlcheck.SourceFunc(func() (string, urlcheck.Metadata, error) {
+		if i >= len(rows) {
+			return "", urlcheck.Metadata{}, io.EOF
+		}
+		i++
+		return rows[i-1].url, urlcheck.Metadata{Label: label, Labels: rows[i-1].labels}, nil
+	})
+}
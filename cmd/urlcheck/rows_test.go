@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestReadCSVRows(t *testing.T) {
+	input := `url, timeout, retries, method, owner, ticket
+https://cdn.example/app.js,2s,,,web,
+https://legacy.example/api,45s,0,post,billing,OPS-7
+https://plain.example/,,,,,
+
+`
+	rows, err := readCSVRows(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("readCSVRows: %v", err)
+	}
+	if len(rows) != 3 || rows[2].url != "https://plain.example/" || rows[2].labels != nil {
+		t.Fatalf("unexpected rows %+v", rows)
+	}
+	overrides := rowOverrides(rows)
+	if len(overrides) != 2 {
+		t.Fatalf("expected overrides only for rows that set them, got %+v", overrides)
+	}
+	if o := overrides[0]; o.Timeout != 2*time.Second || o.Retries != nil || o.Method != "" {
+		t.Fatalf("unexpected cdn override %+v", o)
+	}
+	if o := overrides[1]; o.Timeout != 45*time.Second || o.Retries == nil || *o.Retries != 0 || o.Method != "POST" {
+		t.Fatalf("unexpected legacy override %+v", o)
+	}
+	if l := rows[1].labels; len(l) != 2 || l["owner"] != "billing" || l["ticket"] != "OPS-7" {
+		t.Fatalf("unexpected labels %v", l)
+	}
+}
+
+func TestReadCSVRowsErrors(t *testing.T) {
+	for _, input := range []string{
+		"link\nhttps://a.example\n",
+		"url,timeout\nhttps://a.example,soon\n",
+		"url,retries\nhttps://a.example,-1\n",
+	} {
+		if _, err := readCSVRows(strings.NewReader(input)); err == nil {
+			t.Fatalf("expected an error for %q", input)
+		}
+	}
+}
+
+func TestJSONRowsCarryLabelsIntoResults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "urls.json")
+	data := `[{"url": "https://a.example", "labels": {"service": "checkout"}}, {"url": "https://b.example", "method": "head"}]`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	urls, prov, err := loadInputs(config{file: path}, nil)
+	if err != nil {
+		t.Fatalf("loadInputs: %v", err)
+	}
+	if len(urls) != 2 {
+		t.Fatalf("unexpected urls %v", urls)
+	}
+	r := prov.attribute(urlcheck.Result{URL: "https://a.example"})
+	if r.Origin != "file:"+path || r.Labels["service"] != "checkout" {
+		t.Fatalf("labels not attributed: %+v", r)
+	}
+	if _, err := loadInputRows(writeTemp(t, "bad.json", `[{"labels": {}}]`)); err == nil {
+		t.Fatal("expected an error for an entry without url")
+	}
+}
+
+func writeTemp(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+type sampling struct {
+	fraction float64
+	n        int
+	perHost  int
+	seed     uint64
+}
+
+func (s sampling) enabled() bool {
+	return s.fraction > 0 || s.n > 0 || s.perHost > 0
+}
+
+func parseSampleFraction(spec string) (float64, error) {
+	if spec == "" {
+		return 0, nil
+	}
+	percent := strings.HasSuffix(spec, "%")
+	v, err := strconv.ParseFloat(strings.TrimSuffix(spec, "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid -sample %q: %w", spec, err)
+	}
+	if percent {
+		v /= 100
+	}
+	if v <= 0 || v > 1 {
+		return 0, fmt.Errorf("invalid -sample %q: must be in (0%%, 100%%]", spec)
+	}
+	return v, nil
+}
+
+func (s sampling) size(total int) int {
+	k := total
+	if s.fraction > 0 {
+		k = int(float64(total)*s.fraction + 0.5)
+		if k < 1 && total > 0 {
+			k = 1
+		}
+	}
+	if s.n > 0 && s.n < k {
+		k = s.n
+	}
+	return k
+}
+
+func (s sampling) apply(urls []string) []string {
+	k := s.size(len(urls))
+	if s.perHost == 0 && k >= len(urls) {
+		return urls
+	}
+	rng := rand.New(rand.NewPCG(s.seed, s.seed))
+	var picked []int
+	if s.perHost > 0 {
+		picked = s.stratified(urls, k, rng)
+	} else {
+		picked = rng.Perm(len(urls))[:k]
+	}
+	sort.Ints(picked)
+	out := make([]string, 0, k)
+	for _, idx := range picked {
+		out = append(out, urls[idx])
+	}
+	return out
+}
+
+func (s sampling) stratified(urls []string, k int, rng *rand.Rand) []int {
+	byHost := make(map[string][]int)
+	var hosts []string
+	for idx, raw := range urls {
+		host := hostOf(raw)
+		if _, ok := byHost[host]; !ok {
+			hosts = append(hosts, host)
+		}
+		byHost[host] = append(byHost[host], idx)
+	}
+	chosen := make(map[int]bool)
+	var picked []int
+	for _, host := range hosts {
+		idxs := byHost[host]
+		rng.Shuffle(len(idxs), func(i, j int) { idxs[i], idxs[j] = idxs[j], idxs[i] })
+		for _, idx := range idxs[:min(s.perHost, len(idxs))] {
+			chosen[idx] = true
+			picked = append(picked, idx)
+		}
+	}
+	for _, idx := range rng.Perm(len(urls)) {
+		if len(picked) >= k {
+			break
+		}
+		if !chosen[idx] {
+			chosen[idx] = true
+			picked = append(picked, idx)
+		}
+	}
+	return picked
+}
+
+// hostOf is the host the checker will contact for raw, so that a bare
+// "example.com/x" groups with https://example.com/.
+func hostOf(raw string) string {
+	if normalized, _, err := urlcheck.NormalizeURL(raw); err == nil {
+		raw = normalized
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+func writeSampleSummary(out io.Writer, s sampling, population int, results []urlcheck.Result) {
+	checked, failed := 0, 0
+	for _, r := range results {
+		if r.SkipReason != "" {
+			continue
+		}
+		checked++
+		if !r.OK {
+			failed++
+		}
+	}
+	if checked == 0 {
+		fmt.Fprintf(out, "sample: 0 of %d urls checked (seed %d)\n", population, s.seed)
+		return
+	}
+	rate := float64(failed) / float64(checked)
+	fmt.Fprintf(out, "sample: %d of %d urls checked (seed %d), failure rate %.2f%%, estimated %d failures overall\n",
+		checked, population, s.seed, rate*100, int(rate*float64(population)+0.5))
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestParseSampleFraction(t *testing.T) {
+	cases := map[string]float64{"5%": 0.05, "0.25": 0.25, "100%": 1}
+	for spec, want := range cases {
+		got, err := parseSampleFraction(spec)
+		if err != nil || got != want {
+			t.Fatalf("parseSampleFraction(%q) = %v, %v; want %v", spec, got, err, want)
+		}
+	}
+	for _, spec := range []string{"0", "150%", "abc"} {
+		if _, err := parseSampleFraction(spec); err == nil {
+			t.Fatalf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestSamplingIsDeterministic(t *testing.T) {
+	var urls []string
+	for i := 0; i < 100; i++ {
+		urls = append(urls, fmt.Sprintf("https://example.com/%d", i))
+	}
+	s := sampling{fraction: 0.1, seed: 42}
+	first := s.apply(urls)
+	second := s.apply(urls)
+	if len(first) != 10 {
+		t.Fatalf("expected 10 sampled urls, got %d", len(first))
+	}
+	if strings.Join(first, " ") != strings.Join(second, " ") {
+		t.Fatalf("expected same sample for the same seed")
+	}
+	capped := sampling{fraction: 0.5, n: 3, seed: 1}.apply(urls)
+	if len(capped) != 3 {
+		t.Fatalf("expected -sample-n to cap the sample, got %d", len(capped))
+	}
+}
+
+func TestWriteSampleSummaryExtrapolates(t *testing.T) {
+	results := []urlcheck.Result{{OK: true}, {OK: false}, {OK: true}, {OK: true}}
+	var buf bytes.Buffer
+	writeSampleSummary(&buf, sampling{seed: 7}, 1000, results)
+	if !strings.Contains(buf.String(), "failure rate 25.00%") || !strings.Contains(buf.String(), "estimated 250 failures") {
+		t.Fatalf("unexpected summary: %s", buf.String())
+	}
+}
+
+func TestStratifiedSamplingCoversSmallHosts(t *testing.T) {
+	var urls []string
+	for i := 0; i < 200; i++ {
+		urls = append(urls, fmt.Sprintf("https://giant.example/%d", i))
+	}
+	urls = append(urls, "https://tiny.example/only", "https://small.example/a", "https://small.example/b")
+	s := sampling{n: 5, perHost: 1, seed: 3}
+	picked := s.apply(urls)
+	hosts := map[string]int{}
+	for _, u := range picked {
+		hosts[hostOf(u)]++
+	}
+	if hosts["tiny.example"] != 1 || hosts["small.example"] < 1 || hosts["giant.example"] < 1 {
+		t.Fatalf("expected every host represented, got %v", hosts)
+	}
+	if len(picked) != 5 {
+		t.Fatalf("expected sample of 5, got %d", len(picked))
+	}
+}
+
+func TestStratifiedSamplingGroupsBareHosts(t *testing.T) {
+	var urls []string
+	for i := 0; i < 50; i++ {
+		urls = append(urls, fmt.Sprintf("giant.example/%d", i))
+	}
+	urls = append(urls, "tiny.example/only", "Small.example:443/a")
+	s := sampling{n: 3, perHost: 1, seed: 3}
+	hosts := map[string]int{}
+	for _, u := range s.apply(urls) {
+		hosts[hostOf(u)]++
+	}
+	if hosts["tiny.example"] != 1 || hosts["small.example"] != 1 || hosts["giant.example"] != 1 {
+		t.Fatalf("expected one url per bare host, got %v", hosts)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestWriteSARIFOnlyFailures(t *testing.T) {
+	results := []urlcheck.Result{
+		{URL: "https://ok.example", OK: true, Status: 200},
+		{URL: "https://bad.example", Status: 404, Source: &urlcheck.Location{File: "docs/a.md", Line: 12}},
+		{URL: "https://skip.example", SkipReason: "excluded"},
+	}
+	var buf bytes.Buffer
+	if err := writeSARIF(&buf, results); err != nil {
+		t.Fatalf("writeSARIF: %v", err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("parse sarif: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected sarif log: %+v", log)
+	}
+	got := log.Runs[0].Results
+	if len(got) != 1 {
+		t.Fatalf("expected one finding, got %d", len(got))
+	}
+	loc := got[0].Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "docs/a.md" || loc.Region.StartLine != 12 {
+		t.Fatalf("unexpected location: %+v", loc)
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+var linkPattern = regexp.MustCompile(`(?:https?://|s?ftp://|s3://|gs://|file://|mailto:)[^\s<>"'()\[\]{}` + "`" + `]+`)
+
+type locations map[string]*urlcheck.Location
+
+func scanFiles(paths []string) ([]string, locations, error) {
+	var urls []string
+	locs := make(locations)
+	for _, path := range paths {
+		found, err := scanFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, f := range found {
+			if _, seen := locs[f.url]; !seen {
+				locs[f.url] = &urlcheck.Location{File: path, Line: f.line}
+			}
+			urls = append(urls, f.url)
+		}
+	}
+	return urls, locs, nil
+}
+
+type foundLink struct {
+	url  string
+	line int
+}
+
+func scanFile(path string) ([]foundLink, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	extract := extractorFor(path)
+	var found []foundLink
+	line := 0
+	for scanner.Scan() {
+		line++
+		for _, u := range extract(scanner.Text()) {
+			found = append(found, foundLink{url: u, line: line})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+func extractorFor(path string) func(string) []string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".tf", ".tfvars", ".hcl":
+		return extractTerraform
+	case ".yaml", ".yml":
+		return extractYAML
+	}
+	return extractLinks
+}
+
+func extractLinks(line string) []string {
+	var out []string
+	for _, m := range linkPattern.FindAllString(line, -1) {
+		out = append(out, strings.TrimRight(m, ".,;:!?*_"))
+	}
+	return out
+}
+
+func extractYAML(line string) []string {
+	if strings.HasPrefix(strings.TrimSpace(line), "#") {
+		return nil
+	}
+	return extractLinks(line)
+}
+
+var terraformSource = regexp.MustCompile(`^\s*source\s*=\s*"([^"]+)"`)
+
+var terraformRegistryModule = regexp.MustCompile(`^[a-z0-9_-]+/[a-z0-9_-]+/[a-z0-9_-]+$`)
+
+func extractTerraform(line string) []string {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "//") {
+		return nil
+	}
+	m := terraformSource.FindStringSubmatch(line)
+	if m == nil {
+		return extractLinks(line)
+	}
+	if u := terraformModuleURL(m[1]); u != "" {
+		return []string{u}
+	}
+	return nil
+}
+
+func terraformModuleURL(source string) string {
+	if strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../") {
+		return ""
+	}
+	source = strings.TrimPrefix(source, "git::")
+	if terraformRegistryModule.MatchString(source) {
+		return "https://registry.terraform.io/modules/" + source
+	}
+	for _, host := range []string{"github.com/", "bitbucket.org/", "gitlab.com/"} {
+		if strings.HasPrefix(source, host) {
+			source = "https://" + source
+			break
+		}
+	}
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		return ""
+	}
+	source, _, _ = strings.Cut(source, "?")
+	scheme, rest, _ := strings.Cut(source, "://")
+	rest, _, _ = strings.Cut(rest, "//")
+	return scheme + "://" + rest
+}
+
+func scanSource(urls []string, locs locations) urlcheck.Source {
+	i := 0
+	return urlcheck.SourceFunc(func() (string, urlcheck.Metadata, error) {
+		if i >= len(urls) {
+			return "", urlcheck.Metadata{}, io.EOF
+		}
+		u := urls[i]
+		i++
+		return u, urlcheck.Metadata{Label: "scan", Location: locs[u]}, nil
+	})
+}
+
+func (l locations) attribute(r urlcheck.Result) urlcheck.Result {
+	if loc, ok := l[r.URL]; ok && r.Source == nil {
+		r.Source = loc
+	}
+	return r
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestScanFilesAttributesLines(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "README.md")
+	content := "# Docs\n\nSee [site](https://a.example/docs).\n<a href=\"https://b.example/x?y=1\">b</a>, and https://c.example.\nMail [us](mailto:team@example.com) or read file:///srv/docs/faq.md.\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	urls, locs, err := scanFiles([]string{path})
+	if err != nil {
+		t.Fatalf("scanFiles: %v", err)
+	}
+	want := []string{"https://a.example/docs", "https://b.example/x?y=1", "https://c.example", "mailto:team@example.com", "file:///srv/docs/faq.md"}
+	if len(urls) != len(want) {
+		t.Fatalf("unexpected urls: %#v", urls)
+	}
+	for i, u := range want {
+		if urls[i] != u {
+			t.Fatalf("url %d: got %q, want %q", i, urls[i], u)
+		}
+	}
+	r := locs.attribute(urlcheck.Result{URL: "https://b.example/x?y=1"})
+	if r.Source == nil || r.Source.File != path || r.Source.Line != 4 {
+		t.Fatalf("unexpected source: %+v", r.Source)
+	}
+}
+
+func TestScanFindsFTPLinks(t *testing.T) {
+	got := extractLinks("Mirrors: ftp://ftp.example.org/pub/release.tar.gz and <sftp://deploy@files.example.com/drop/>.")
+	want := []string{"ftp://ftp.example.org/pub/release.tar.gz", "sftp://deploy@files.example.com/drop/"}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected links: %#v", got)
+	}
+	for i, u := range want {
+		if got[i] != u {
+			t.Fatalf("link %d: got %q, want %q", i, got[i], u)
+		}
+	}
+}
+
+func TestScanInfraManifests(t *testing.T) {
+	dir := t.TempDir()
+	tf := filepath.Join(dir, "main.tf")
+	tfContent := `module "vpc" {
+  source = "git::https://git.example.com/infra/vpc.git//modules/vpc?ref=v1.2.0"
+}
+module "consul" {
+  source = "hashicorp/consul/aws"
+}
+module "local" {
+  source = "./modules/local"
+}
+# endpoint = "https://commented.example"
+resource "x" "y" {
+  webhook_url = "https://hooks.example.com/abc"
+}
+`
+	compose := filepath.Join(dir, "docker-compose.yml")
+	composeContent := "services:\n  app:\n    environment:\n      # OLD=https://old.example\n      CALLBACK: https://callback.example/notify\n"
+	for path, content := range map[string]string{tf: tfContent, compose: composeContent} {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+	urls, locs, err := scanFiles([]string{tf, compose})
+	if err != nil {
+		t.Fatalf("scanFiles: %v", err)
+	}
+	want := []string{
+		"https://git.example.com/infra/vpc.git",
+		"https://registry.terraform.io/modules/hashicorp/consul/aws",
+		"https://hooks.example.com/abc",
+		"https://callback.example/notify",
+	}
+	if len(urls) != len(want) {
+		t.Fatalf("unexpected urls: %#v", urls)
+	}
+	for i, u := range want {
+		if urls[i] != u {
+			t.Fatalf("url %d: got %q, want %q", i, urls[i], u)
+		}
+	}
+	if loc := locs["https://callback.example/notify"]; loc == nil || loc.File != compose || loc.Line != 5 {
+		t.Fatalf("unexpected compose location: %+v", loc)
+	}
+}
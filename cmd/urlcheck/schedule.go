@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type scheduleGroup struct {
+	Name string   `json:"name"`
+	Cron string   `json:"cron"`
+	File string   `json:"file"`
+	URLs []string `json:"urls"`
+}
+
+type scheduledWatcher struct {
+	name   string
+	cron   cronSchedule
+	w      *watcher
+	nextAt time.Time
+}
+
+func loadSchedule(path string) ([]scheduleGroup, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Groups []scheduleGroup `json:"groups"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(doc.Groups) == 0 {
+		return nil, fmt.Errorf("%s: no groups", path)
+	}
+	seen := make(map[string]bool)
+	for i, g := range doc.Groups {
+		if g.Name == "" {
+			return nil, fmt.Errorf("%s: group %d has no name", path, i)
+		}
+		if seen[g.Name] {
+			return nil, fmt.Errorf("%s: duplicate group %q", path, g.Name)
+		}
+		seen[g.Name] = true
+		if (g.File == "") == (len(g.URLs) == 0) {
+			return nil, fmt.Errorf("%s: group %q needs exactly one of file or urls", path, g.Name)
+		}
+		if _, err := parseCron(g.Cron); err != nil {
+			return nil, fmt.Errorf("%s: group %q: %w", path, g.Name, err)
+		}
+	}
+	return doc.Groups, nil
+}
+
+func newScheduledWatchers(cfg config, tracer trace.Tracer, groups []scheduleGroup, out io.Writer, color bool, now time.Time) ([]*scheduledWatcher, error) {
+	store := newResultStore(watchHistoryRuns)
+	var sched []*scheduledWatcher
+	for _, g := range groups {
+		gcfg := cfg
+		gcfg.file, gcfg.scan, gcfg.sitemaps = g.File, nil, nil
+		w, err := buildWatcher(gcfg, tracer, out, color)
+		if err != nil {
+			closeScheduled(sched)
+			return nil, err
+		}
+		w.store = store
+		if len(g.URLs) > 0 {
+			w.static, w.label = g.URLs, "group:"+g.Name
+		}
+		if err := w.reload(); err != nil {
+			w.close()
+			closeScheduled(sched)
+			return nil, fmt.Errorf("group %q: %w", g.Name, err)
+		}
+		c, _ := parseCron(g.Cron)
+		sched = append(sched, &scheduledWatcher{name: g.Name, cron: c, w: w, nextAt: c.next(now)})
+	}
+	return sched, nil
+}
+
+func closeScheduled(sched []*scheduledWatcher) {
+	for _, s := range sched {
+		s.w.close()
+	}
+}
+
+func nextDue(sched []*scheduledWatcher) *scheduledWatcher {
+	var due *scheduledWatcher
+	for _, s := range sched {
+		if s.nextAt.IsZero() {
+			continue
+		}
+		if due == nil || s.nextAt.Before(due.nextAt) {
+			due = s
+		}
+	}
+	return due
+}
+
+func runSchedule(cfg config, tracer trace.Tracer, color bool) error {
+	groups, err := loadSchedule(cfg.schedule)
+	if err != nil {
+		return err
+	}
+	sched, err := newScheduledWatchers(cfg, tracer, groups, os.Stdout, color, time.Now())
+	if err != nil {
+		return err
+	}
+	defer closeScheduled(sched)
+	stopServing, err := serveLive(cfg.serve, sched[0].w.store)
+	if err != nil {
+		return err
+	}
+	defer stopServing()
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	notifySystemd("READY=1")
+	startWatchdog(cfg.shutdown, cfg.live)
+	cfg.live.setIdle(true)
+	for {
+		due := nextDue(sched)
+		if due == nil {
+			return fmt.Errorf("no group has an upcoming run")
+		}
+		select {
+		case <-time.After(time.Until(due.nextAt)):
+			cfg.live.setIdle(false)
+			if results, err := due.w.cycle(context.Background()); err != nil {
+				slog.Error("check error", "group", due.name, "error", err)
+			} else {
+				notifySystemd(cycleStatus(results))
+			}
+			cfg.live.setIdle(true)
+			due.nextAt = due.cron.next(time.Now())
+		case <-cfg.shutdown:
+			return nil
+		case <-hup:
+			for _, s := range sched {
+				if err := s.w.reload(); err != nil {
+					slog.Error("reload error", "group", s.name, "error", err, "kept_urls", len(s.w.urls))
+					continue
+				}
+				slog.Info("reloaded", "group", s.name, "urls", len(s.w.urls))
+			}
+		}
+	}
+}
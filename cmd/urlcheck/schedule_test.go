@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadSchedule(t *testing.T) {
+	dir := t.TempDir()
+	write := func(body string) string {
+		path := filepath.Join(dir, "schedule.json")
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	groups, err := loadSchedule(write(`{"groups":[{"name":"critical","cron":"* * * * *","urls":["https://example.com"]},{"name":"docs","cron":"@daily","file":"docs.txt"}]}`))
+	if err != nil || len(groups) != 2 || groups[1].File != "docs.txt" {
+		t.Fatalf("unexpected groups: %+v (%v)", groups, err)
+	}
+	for _, body := range []string{
+		`{"groups":[]}`,
+		`{"groups":[{"name":"a","cron":"bad","urls":["x"]}]}`,
+		`{"groups":[{"name":"a","cron":"@daily"}]}`,
+		`{"groups":[{"name":"a","cron":"@daily","urls":["x"]},{"name":"a","cron":"@daily","urls":["y"]}]}`,
+	} {
+		if _, err := loadSchedule(write(body)); err == nil {
+			t.Errorf("expected error for %s", body)
+		}
+	}
+}
+
+func TestScheduledWatchersRunGroups(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	groups := []scheduleGroup{
+		{Name: "critical", Cron: "* * * * *", URLs: []string{server.URL + "/api"}},
+		{Name: "docs", Cron: "@daily", URLs: []string{server.URL + "/docs"}},
+	}
+	now := time.Date(2026, 10, 15, 10, 17, 0, 0, time.UTC)
+	cfg := config{format: "ndjson", concurrency: 2, timeout: time.Second}
+	sched, err := newScheduledWatchers(cfg, nil, groups, &bytes.Buffer{}, false, now)
+	if err != nil {
+		t.Fatalf("newScheduledWatchers: %v", err)
+	}
+	due := nextDue(sched)
+	if due.name != "critical" || !due.nextAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("unexpected next group %s at %v", due.name, due.nextAt)
+	}
+	results, err := due.w.cycle(context.Background())
+	if err != nil || len(results) != 1 || results[0].Origin != "group:critical" {
+		t.Fatalf("unexpected cycle: %+v (%v)", results, err)
+	}
+	if _, err := sched[1].w.cycle(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if runs := sched[0].w.store.snapshot(); len(runs) != 2 {
+		t.Fatalf("expected groups to share one store, got %d runs", len(runs))
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding"
+	"encoding/json"
+	"flag"
+	"io"
+	"reflect"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+const outputSchemaVersion = "1"
+
+type runMeta struct {
+	Started     time.Time         `json:"started"`
+	Finished    time.Time         `json:"finished"`
+	ToolVersion string            `json:"tool_version"`
+	Config      map[string]string `json:"config"`
+}
+
+var redactedFlags = map[string]bool{
+	"agent-token":   true,
+	"slack-token":   true,
+	"slack-webhook": true,
+	"webhook":       true,
+	"smtp":          true,
+}
+
+func toolVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok || info.Main.Version == "" {
+		return "(devel)"
+	}
+	return info.Main.Version
+}
+
+func effectiveConfig(fs *flag.FlagSet) map[string]string {
+	config := map[string]string{}
+	fs.Visit(func(f *flag.Flag) {
+		if redactedFlags[f.Name] {
+			config[f.Name] = "[redacted]"
+			return
+		}
+		config[f.Name] = f.Value.String()
+	})
+	return config
+}
+
+var (
+	timeType       = reflect.TypeFor[time.Time]()
+	rawMessageType = reflect.TypeFor[json.RawMessage]()
+	textType       = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+type schemaBuilder struct {
+	defs map[string]any
+}
+
+func writeOutputSchema(out io.Writer) error {
+	b := schemaBuilder{defs: map[string]any{}}
+	root := b.object(reflect.TypeFor[jsonReport]())
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["title"] = "urlcheck json output"
+	root["properties"].(map[string]any)["schema_version"] = map[string]any{"const": outputSchemaVersion}
+	root["$defs"] = b.defs
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(root)
+}
+
+func (b *schemaBuilder) schema(t reflect.Type) map[string]any {
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == rawMessageType:
+		return map[string]any{}
+	case t.Kind() == reflect.Pointer:
+		return b.schema(t.Elem())
+	case t.Kind() != reflect.String && t.Implements(textType):
+		return map[string]any{"type": "string"}
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		name := strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+		if _, ok := b.defs[name]; !ok {
+			b.defs[name] = nil
+			b.defs[name] = b.object(t)
+		}
+		return map[string]any{"$ref": "#/$defs/" + name}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]any{"type": "array", "items": b.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": b.schema(t.Elem())}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	}
+	return map[string]any{}
+}
+
+func (b *schemaBuilder) object(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	b.fields(t, properties, &required)
+	obj := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		obj["required"] = required
+	}
+	return obj
+}
+
+func (b *schemaBuilder) fields(t reflect.Type, properties map[string]any, required *[]string) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" || (!f.IsExported() && !f.Anonymous) {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			b.fields(f.Type, properties, required)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		prop := b.schema(f.Type)
+		if !strings.Contains(opts, "omitempty") {
+			*required = append(*required, name)
+			switch f.Type.Kind() {
+			case reflect.Pointer, reflect.Slice, reflect.Map:
+				prop = map[string]any{"anyOf": []any{prop, map[string]any{"type": "null"}}}
+			}
+		}
+		properties[name] = prop
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestJSONOutputMatchesSchema(t *testing.T) {
+	var schema bytes.Buffer
+	if err := writeOutputSchema(&schema); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := jsonschema.UnmarshalJSON(&schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("output.json", doc); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := compiler.Compile("output.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := []urlcheck.Result{
+		{URL: "https://example.com", OK: true, Status: 200, Attempts: 1, Duration: 120 * time.Millisecond, Warnings: []string{"slow"}, Severity: urlcheck.SeverityWarn},
+		{URL: "https://broken.example", Error: "timeout", ErrorKind: urlcheck.KindTimeout, Attempts: 3, Fingerprint: &urlcheck.Fingerprint{Provider: "Cloudflare"}},
+	}
+	run := runMeta{Started: time.Now(), ToolVersion: toolVersion(), Config: map[string]string{"timeout": "5s"}}
+	var out bytes.Buffer
+	if err := jsonFormatter(run, true)(&out, results); err != nil {
+		t.Fatal(err)
+	}
+	report, err := jsonschema.UnmarshalJSON(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sch.Validate(report); err != nil {
+		t.Fatalf("output does not match the schema: %v", err)
+	}
+	if err := sch.Validate(map[string]any{"schema_version": "0", "summary": map[string]any{}, "results": []any{}}); err == nil {
+		t.Fatal("expected a wrong schema_version to be rejected")
+	}
+}
+
+func TestJSONOutputRunMetadata(t *testing.T) {
+	started := time.Now().Add(-time.Minute)
+	var out bytes.Buffer
+	if err := jsonFormatter(runMeta{Started: started, ToolVersion: "v1.2.3", Config: map[string]string{"retries": "2"}}, false)(&out, nil); err != nil {
+		t.Fatal(err)
+	}
+	var report jsonReport
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.SchemaVersion != outputSchemaVersion || report.Run == nil || report.Run.ToolVersion != "v1.2.3" || report.Run.Config["retries"] != "2" {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if !report.Run.Started.Equal(started) || report.Run.Finished.Before(started.Add(time.Minute)) {
+		t.Fatalf("unexpected run times %+v", report.Run)
+	}
+	out.Reset()
+	if err := writeJSON(&out, nil); err != nil {
+		t.Fatal(err)
+	}
+	var plain jsonReport
+	if err := json.Unmarshal(out.Bytes(), &plain); err != nil || plain.SchemaVersion != outputSchemaVersion || plain.Run != nil {
+		t.Fatalf("unexpected plain report %s (%v)", out.String(), err)
+	}
+}
+
+func TestEffectiveConfigRedactsSecrets(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Int("retries", 0, "")
+	fs.Int("concurrency", 5, "")
+	fs.String("slack-token", "", "")
+	if err := fs.Parse([]string{"-retries", "3", "-slack-token", "xoxb-secret"}); err != nil {
+		t.Fatal(err)
+	}
+	got := effectiveConfig(fs)
+	if len(got) != 2 || got["retries"] != "3" || got["slack-token"] != "[redacted]" {
+		t.Fatalf("unexpected config %v", got)
+	}
+}
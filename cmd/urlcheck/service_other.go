@@ -0,0 +1,13 @@
+//go:build !windows
+
+package main
+
+import "fmt"
+
+func runService(args []string) error {
+	return fmt.Errorf("windows services are only supported on windows")
+}
+
+func runWindowsService(cfg config, color bool) (bool, error) {
+	return false, nil
+}
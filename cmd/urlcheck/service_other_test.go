@@ -0,0 +1,17 @@
+//go:build !windows
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestServiceUnsupportedOutsideWindows(t *testing.T) {
+	if err := runService([]string{"install"}); err == nil || !strings.Contains(err.Error(), "only supported on windows") {
+		t.Fatalf("expected an unsupported error, got %v", err)
+	}
+	if inService, err := runWindowsService(config{}, false); inService || err != nil {
+		t.Fatalf("expected to run in the foreground, got %v (%v)", inService, err)
+	}
+}
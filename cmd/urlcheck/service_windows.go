@@ -0,0 +1,156 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/eventlog"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+const serviceName = "urlcheck"
+
+func runService(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: urlcheck service install|uninstall|start|stop [watch flags...]")
+	}
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+	switch args[0] {
+	case "install":
+		exe, err := os.Executable()
+		if err != nil {
+			return err
+		}
+		// Services start in the system directory, so relative paths in the
+		// watch flags would stop resolving; pin them to where install ran.
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		s, err := m.CreateService(serviceName, exe, mgr.Config{
+			DisplayName: "urlcheck link monitor",
+			Description: "Re-checks urls on an interval or cron schedule (urlcheck watch).",
+			StartType:   mgr.StartAutomatic,
+		}, append([]string{"watch", "-chdir", wd}, args[1:]...)...)
+		if err != nil {
+			return err
+		}
+		defer s.Close()
+		if err := eventlog.InstallAsEventCreate(serviceName, eventlog.Error|eventlog.Warning|eventlog.Info); err != nil && !strings.Contains(err.Error(), "exists") {
+			s.Delete()
+			return err
+		}
+		return nil
+	case "uninstall":
+		s, err := m.OpenService(serviceName)
+		if err != nil {
+			return err
+		}
+		defer s.Close()
+		if err := s.Delete(); err != nil {
+			return err
+		}
+		return eventlog.Remove(serviceName)
+	case "start":
+		s, err := m.OpenService(serviceName)
+		if err != nil {
+			return err
+		}
+		defer s.Close()
+		return s.Start()
+	case "stop":
+		s, err := m.OpenService(serviceName)
+		if err != nil {
+			return err
+		}
+		defer s.Close()
+		_, err = s.Control(svc.Stop)
+		return err
+	}
+	return fmt.Errorf("unknown service command %q (want install|uninstall|start|stop)", args[0])
+}
+
+func runWindowsService(cfg config, color bool) (bool, error) {
+	inService, err := svc.IsWindowsService()
+	if err != nil || !inService {
+		return false, err
+	}
+	el, err := eventlog.Open(serviceName)
+	if err != nil {
+		return true, err
+	}
+	defer el.Close()
+	slog.SetDefault(slog.New(slog.NewTextHandler(eventLogWriter{el}, nil)))
+	return true, svc.Run(serviceName, &watchService{cfg: cfg, color: color})
+}
+
+type eventLogWriter struct {
+	log *eventlog.Log
+}
+
+func (w eventLogWriter) Write(p []byte) (int, error) {
+	line := strings.TrimSpace(string(p))
+	var err error
+	switch {
+	case strings.Contains(line, " level=ERROR "):
+		err = w.log.Error(1, line)
+	case strings.Contains(line, " level=WARN "):
+		err = w.log.Warning(1, line)
+	default:
+		err = w.log.Info(1, line)
+	}
+	return len(p), err
+}
+
+type watchService struct {
+	cfg   config
+	color bool
+}
+
+func (s *watchService) Execute(args []string, requests <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	changes <- svc.Status{State: svc.StartPending}
+	stop := make(chan struct{})
+	cfg := s.cfg
+	cfg.shutdown = stop
+	done := make(chan error, 1)
+	go func() { done <- runWatch(cfg, s.color) }()
+	changes <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				slog.Error("watch error", "error", err)
+				return false, 1
+			}
+			return false, 0
+		case r := <-requests:
+			switch r.Cmd {
+			case svc.Interrogate:
+				changes <- r.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				reason := "service stop"
+				if r.Cmd == svc.Shutdown {
+					reason = "system shutdown"
+				}
+				slog.Info("shutting down", "reason", reason)
+				changes <- svc.Status{State: svc.StopPending, WaitHint: uint32((interruptGrace + 5*time.Second).Milliseconds())}
+				close(stop)
+				if err := <-done; err != nil {
+					slog.Error("watch error", "error", err)
+					return false, 1
+				}
+				return false, 0
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+type shard struct {
+	index int
+	total int
+}
+
+func parseShard(spec string) (shard, error) {
+	if spec == "" {
+		return shard{}, nil
+	}
+	k, n, ok := strings.Cut(spec, "/")
+	index, kerr := strconv.Atoi(k)
+	total, nerr := strconv.Atoi(n)
+	if !ok || kerr != nil || nerr != nil || total < 1 || index < 1 || index > total {
+		return shard{}, fmt.Errorf("invalid -shard %q (want K/N with 1 <= K <= N, e.g. 3/10)", spec)
+	}
+	return shard{index: index, total: total}, nil
+}
+
+func (s shard) keep(u string) bool {
+	if s.total <= 1 {
+		return true
+	}
+	key := u
+	if normalized, _, err := urlcheck.NormalizeURL(u); err == nil {
+		key = normalized
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32()%uint32(s.total)) == s.index-1
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParseShard(t *testing.T) {
+	s, err := parseShard("3/10")
+	if err != nil || s != (shard{index: 3, total: 10}) {
+		t.Fatalf("unexpected shard %+v, %v", s, err)
+	}
+	if s, err := parseShard(""); err != nil || s.total != 0 {
+		t.Fatalf("empty spec should disable sharding: %+v, %v", s, err)
+	}
+	for _, spec := range []string{"3", "0/10", "11/10", "1/0", "a/b", "-1/3"} {
+		if _, err := parseShard(spec); err == nil {
+			t.Fatalf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestShardsPartitionInput(t *testing.T) {
+	var urls []string
+	for i := range 200 {
+		urls = append(urls, fmt.Sprintf("https://host%d.example/page", i))
+	}
+	owners := make(map[string]int)
+	for k := 1; k <= 4; k++ {
+		s := shard{index: k, total: 4}
+		kept := 0
+		for _, u := range urls {
+			if s.keep(u) {
+				owners[u]++
+				kept++
+			}
+		}
+		if kept == 0 {
+			t.Fatalf("shard %d/4 selected nothing", k)
+		}
+	}
+	for _, u := range urls {
+		if owners[u] != 1 {
+			t.Fatalf("%s selected by %d shards", u, owners[u])
+		}
+	}
+}
+
+func TestShardKeysOnNormalizedURL(t *testing.T) {
+	for k := 1; k <= 7; k++ {
+		s := shard{index: k, total: 7}
+		if s.keep("HTTPS://Example.com:443/a") != s.keep("https://example.com/a") {
+			t.Fatalf("shard %d/7 split equivalent urls", k)
+		}
+	}
+}
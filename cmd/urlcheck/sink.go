@@ -0,0 +1,210 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+type sink interface {
+	write(urlcheck.Result) error
+	close([]urlcheck.Result) error
+}
+
+type formatSink struct {
+	format string
+	render formatter
+	out    io.Writer
+	file   *os.File
+	enc    *json.Encoder
+}
+
+func newFormatSink(format string, out io.Writer) *formatSink {
+	s := &formatSink{format: format, render: formats[format], out: out}
+	if streamingFormats[format] {
+		s.enc = json.NewEncoder(out)
+	}
+	return s
+}
+
+func (s *formatSink) write(r urlcheck.Result) error {
+	if s.enc == nil {
+		return nil
+	}
+	return s.enc.Encode(r)
+}
+
+func (s *formatSink) close(results []urlcheck.Result) error {
+	var err error
+	if s.enc == nil {
+		err = s.render(s.out, results)
+	}
+	if s.file != nil {
+		if cerr := s.file.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// statsdSink is fire-and-forget: metrics are best effort, so a send error
+// (say, ECONNREFUSED while the collector restarts) is logged once and never
+// fails the run.
+type statsdSink struct {
+	conn   net.Conn
+	prefix string
+	warned sync.Once
+}
+
+func newStatsdSink(addr string) (*statsdSink, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &statsdSink{conn: conn, prefix: "urlcheck"}, nil
+}
+
+func (s *statsdSink) write(r urlcheck.Result) error {
+	var err error
+	if r.SkipReason != "" {
+		_, err = fmt.Fprintf(s.conn, "%s.skipped:1|c", s.prefix)
+	} else {
+		outcome := "ok"
+		if !r.OK {
+			outcome = "failed"
+		}
+		_, err = fmt.Fprintf(s.conn, "%s.checked:1|c\n%s.%s:1|c\n%s.duration:%d|ms",
+			s.prefix, s.prefix, outcome, s.prefix, r.Duration.Milliseconds())
+	}
+	if err != nil {
+		s.warned.Do(func() {
+			slog.Warn("statsd send failed, dropping metrics", "addr", s.conn.RemoteAddr().String(), "error", err)
+		})
+	}
+	return nil
+}
+
+func (s *statsdSink) close([]urlcheck.Result) error {
+	return s.conn.Close()
+}
+
+type printSink struct {
+	out  io.Writer
+	want func(urlcheck.Result) bool
+}
+
+func newPrintSink(mode string, out io.Writer) (*printSink, error) {
+	switch mode {
+	case "valid":
+		return &printSink{out: out, want: func(r urlcheck.Result) bool { return r.OK && r.SkipReason == "" }}, nil
+	case "invalid":
+		return &printSink{out: out, want: failed}, nil
+	}
+	return nil, fmt.Errorf("invalid -print %q (want valid|invalid)", mode)
+}
+
+func (s *printSink) write(r urlcheck.Result) error {
+	if !s.want(r) {
+		return nil
+	}
+	_, err := fmt.Fprintln(s.out, r.URL)
+	return err
+}
+
+func (s *printSink) close([]urlcheck.Result) error {
+	return nil
+}
+
+type sinkSet struct {
+	sinks []sink
+	keep  func(urlcheck.Result) bool
+	err   error
+}
+
+func (s *sinkSet) write(r urlcheck.Result) {
+	if s.keep != nil && !s.keep(r) {
+		return
+	}
+	for _, sk := range s.sinks {
+		if err := sk.write(r); err != nil && s.err == nil {
+			s.err = err
+		}
+	}
+}
+
+func (s *sinkSet) close(results []urlcheck.Result) error {
+	err := s.err
+	if s.keep != nil {
+		var kept []urlcheck.Result
+		for _, r := range results {
+			if s.keep(r) {
+				kept = append(kept, r)
+			}
+		}
+		results = kept
+	}
+	for _, sk := range s.sinks {
+		if cerr := sk.close(results); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+func (s *sinkSet) wrapRender(format string, wrap func(formatter) formatter) {
+	for _, sk := range s.sinks {
+		if fs, ok := sk.(*formatSink); ok && fs.format == format {
+			fs.render = wrap(fs.render)
+		}
+	}
+}
+
+func newSinkSet(specs []string, defaultFormat string, stdout io.Writer, color bool) (*sinkSet, error) {
+	if len(specs) == 0 {
+		specs = []string{defaultFormat}
+	}
+	set := &sinkSet{}
+	for _, spec := range specs {
+		sk, err := openSink(spec, stdout, color)
+		if err != nil {
+			set.close(nil)
+			return nil, err
+		}
+		set.sinks = append(set.sinks, sk)
+	}
+	return set, nil
+}
+
+func openSink(spec string, stdout io.Writer, color bool) (sink, error) {
+	name, dest, _ := strings.Cut(spec, "=")
+	if name == "statsd" {
+		if dest == "" {
+			return nil, fmt.Errorf("invalid -output %q, want statsd=host:port", spec)
+		}
+		return newStatsdSink(dest)
+	}
+	if _, ok := formats[name]; !ok {
+		return nil, fmt.Errorf("invalid -output %q: unknown format %q (want %s|statsd)", spec, name, formatNames())
+	}
+	if dest == "" || dest == "-" {
+		s := newFormatSink(name, stdout)
+		if color && name == "table" {
+			s.render = writeColorTable
+		}
+		return s, nil
+	}
+	f, err := os.Create(dest)
+	if err != nil {
+		return nil, err
+	}
+	s := newFormatSink(name, f)
+	s.file = f
+	return s, nil
+}
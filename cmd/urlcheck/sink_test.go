@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestSinkSetWritesEveryOutput(t *testing.T) {
+	dir := t.TempDir()
+	ndjsonPath := filepath.Join(dir, "results.ndjson")
+	var stdout bytes.Buffer
+	sinks, err := newSinkSet([]string{"ndjson=" + ndjsonPath, "table"}, "json", &stdout, false)
+	if err != nil {
+		t.Fatalf("newSinkSet: %v", err)
+	}
+	results := []urlcheck.Result{
+		{URL: "https://a.example", OK: true, Status: 200},
+		{URL: "https://b.example", Status: 404},
+	}
+	for _, r := range results {
+		sinks.write(r)
+	}
+	if err := sinks.close(results); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	data, err := os.ReadFile(ndjsonPath)
+	if err != nil {
+		t.Fatalf("read ndjson: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], `"url":"https://b.example"`) {
+		t.Fatalf("unexpected ndjson output: %q", data)
+	}
+	if !strings.Contains(stdout.String(), "STATUS") || !strings.Contains(stdout.String(), "https://b.example") {
+		t.Fatalf("unexpected table output: %q", stdout.String())
+	}
+}
+
+func TestSinkSetRejectsUnknownFormat(t *testing.T) {
+	if _, err := newSinkSet([]string{"yaml"}, "table", &bytes.Buffer{}, false); err == nil {
+		t.Fatalf("expected unknown format error")
+	}
+	if _, err := newSinkSet([]string{"statsd"}, "table", &bytes.Buffer{}, false); err == nil {
+		t.Fatalf("expected error for statsd without address")
+	}
+}
+
+func TestStatsdSinkSendsCounters(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer conn.Close()
+	s, err := newStatsdSink(conn.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("newStatsdSink: %v", err)
+	}
+	defer s.close(nil)
+	if err := s.write(urlcheck.Result{URL: "https://a.example", Status: 500, Duration: 20 * time.Millisecond}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	buf := make([]byte, 512)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if got := string(buf[:n]); !strings.Contains(got, "urlcheck.failed:1|c") || !strings.Contains(got, "urlcheck.duration:20|ms") {
+		t.Fatalf("unexpected statsd payload: %q", got)
+	}
+}
+
+func TestStatsdSinkIgnoresSendErrors(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := conn.LocalAddr().String()
+	conn.Close()
+	s, err := newStatsdSink(addr)
+	if err != nil {
+		t.Fatalf("newStatsdSink: %v", err)
+	}
+	defer s.close(nil)
+	for range 3 {
+		if err := s.write(urlcheck.Result{URL: "https://a.example", OK: true}); err != nil {
+			t.Fatalf("statsd errors should not fail the run: %v", err)
+		}
+	}
+}
+
+func TestSinkSetKeepFiltersResults(t *testing.T) {
+	var stdout bytes.Buffer
+	sinks, err := newSinkSet([]string{"ndjson", "json"}, "json", &stdout, false)
+	if err != nil {
+		t.Fatalf("newSinkSet: %v", err)
+	}
+	sinks.keep = failed
+	results := []urlcheck.Result{
+		{URL: "https://a.example", OK: true, Status: 200},
+		{URL: "https://b.example", Status: 404},
+		{URL: "https://c.example", SkipReason: "excluded"},
+	}
+	for _, r := range results {
+		sinks.write(r)
+	}
+	if err := sinks.close(results); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	out := stdout.String()
+	if strings.Contains(out, "a.example") || strings.Contains(out, "c.example") {
+		t.Fatalf("expected only failures, got %q", out)
+	}
+	if strings.Count(out, "b.example") != 2 {
+		t.Fatalf("expected failure in both outputs, got %q", out)
+	}
+}
+
+func TestPrintSink(t *testing.T) {
+	results := []urlcheck.Result{
+		{URL: "https://ok.example", OK: true, Status: 200},
+		{URL: "https://bad.example", Status: 404},
+		{URL: "https://skipped.example", SkipReason: "excluded"},
+	}
+	for mode, want := range map[string]string{
+		"valid":   "https://ok.example\n",
+		"invalid": "https://bad.example\n",
+	} {
+		var buf bytes.Buffer
+		s, err := newPrintSink(mode, &buf)
+		if err != nil {
+			t.Fatalf("newPrintSink(%q): %v", mode, err)
+		}
+		for _, r := range results {
+			s.write(r)
+		}
+		if buf.String() != want {
+			t.Fatalf("-print %s wrote %q, want %q", mode, buf.String(), want)
+		}
+	}
+	if _, err := newPrintSink("all", io.Discard); err == nil {
+		t.Fatal("expected an error for an unknown -print mode")
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+const (
+	slackAPI         = "https://slack.com/api/chat.postMessage"
+	slackTopFailures = 10
+)
+
+type slackNotifier struct {
+	webhook   string
+	token     string
+	channel   string
+	api       string
+	reportURL string
+	client    *http.Client
+}
+
+func newSlackNotifier(cfg config) (*slackNotifier, error) {
+	switch {
+	case cfg.slackHook != "" && cfg.slackToken != "":
+		return nil, fmt.Errorf("use either -slack-webhook or -slack-token, not both")
+	case cfg.slackToken != "" && cfg.slackChan == "":
+		return nil, fmt.Errorf("-slack-token requires -slack-channel")
+	}
+	return &slackNotifier{
+		webhook:   cfg.slackHook,
+		token:     cfg.slackToken,
+		channel:   cfg.slackChan,
+		api:       slackAPI,
+		reportURL: cfg.reportURL,
+		client:    &http.Client{Timeout: cfg.timeout},
+	}, nil
+}
+
+func slackText(n notification, reportURL string) string {
+	var b strings.Builder
+	s := n.Summary
+	if n.Event == eventStateChange {
+		fmt.Fprintf(&b, ":arrows_counterclockwise: *urlcheck*: %d url(s) changed state (%d of %d failing)\n", len(n.Changes), s.Broken+s.Errored, s.Total)
+		for _, c := range n.Changes {
+			fmt.Fprintf(&b, "• %s: %s → %s\n", c.URL, c.From, c.To)
+		}
+	} else {
+		fmt.Fprintf(&b, ":red_circle: *urlcheck*: %d of %d urls failing (broken %d, errored %d)\n", s.Broken+s.Errored, s.Total, s.Broken, s.Errored)
+		for i, r := range n.Failures {
+			if i == slackTopFailures {
+				fmt.Fprintf(&b, "…and %d more\n", len(n.Failures)-i)
+				break
+			}
+			fmt.Fprintf(&b, "• %s — %s\n", r.URL, failureReason(r))
+		}
+	}
+	if reportURL != "" {
+		fmt.Fprintf(&b, "<%s|Full report>\n", reportURL)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func failureReason(r urlcheck.Result) string {
+	if text := errorText(r); text != "" {
+		return text
+	}
+	return fmt.Sprintf("status %d", r.Status)
+}
+
+func (s *slackNotifier) notify(ctx context.Context, n notification) error {
+	msg := map[string]string{"text": slackText(n, s.reportURL)}
+	target := s.webhook
+	if s.token != "" {
+		msg["channel"] = s.channel
+		target = s.api
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("slack returned %s", resp.Status)
+	}
+	if s.token == "" {
+		return nil
+	}
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("slack: %w", err)
+	}
+	if !result.OK {
+		return fmt.Errorf("slack: %s", result.Error)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestSlackText(t *testing.T) {
+	var results []urlcheck.Result
+	for i := 0; i < 12; i++ {
+		results = append(results, urlcheck.Result{URL: fmt.Sprintf("https://x.example/%d", i), Status: 404})
+	}
+	results = append(results, urlcheck.Result{URL: "https://ok.example", OK: true, Status: 200})
+	text := slackText(newNotification(eventFailures, results, time.Second, nil), "https://ci.example/report.html")
+	for _, want := range []string{"12 of 13 urls failing", "• https://x.example/0 — status 404", "…and 2 more", "<https://ci.example/report.html|Full report>"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("missing %q in:\n%s", want, text)
+		}
+	}
+	text = slackText(newNotification(eventStateChange, results, time.Second, []stateChange{{URL: "https://a", From: "ok", To: "failing"}}), "")
+	if !strings.Contains(text, "1 url(s) changed state") || !strings.Contains(text, "• https://a: ok → failing") {
+		t.Errorf("unexpected state change text:\n%s", text)
+	}
+}
+
+func TestSlackNotifierBotToken(t *testing.T) {
+	var got map[string]string
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&got)
+		if got["channel"] == "#missing" {
+			w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+	n := newNotification(eventFailures, []urlcheck.Result{{URL: "https://a", Status: 500}}, 0, nil)
+	s, err := newSlackNotifier(config{slackToken: "xoxb-1", slackChan: "#ops", timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.api = server.URL
+	if err := s.notify(context.Background(), n); err != nil {
+		t.Fatal(err)
+	}
+	if auth != "Bearer xoxb-1" || got["channel"] != "#ops" || !strings.Contains(got["text"], "https://a") {
+		t.Fatalf("unexpected request: auth %q body %v", auth, got)
+	}
+	s.channel = "#missing"
+	if err := s.notify(context.Background(), n); err == nil || !strings.Contains(err.Error(), "channel_not_found") {
+		t.Fatalf("expected slack api error, got %v", err)
+	}
+}
+
+func TestSlackNotifierWebhook(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	s, err := newSlackNotifier(config{slackHook: server.URL, timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.notify(context.Background(), newNotification(eventFailures, nil, 0, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if _, hasChannel := got["channel"]; hasChannel || got["text"] == "" {
+		t.Fatalf("unexpected webhook body %v", got)
+	}
+}
+
+func TestNewSlackNotifierValidates(t *testing.T) {
+	if _, err := newSlackNotifier(config{slackHook: "http://x", slackToken: "t"}); err == nil {
+		t.Fatal("expected error for webhook and token together")
+	}
+	if _, err := newSlackNotifier(config{slackToken: "t"}); err == nil {
+		t.Fatal("expected error for token without channel")
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+const maxSummaryFailures = 10
+
+func appendStepSummary(getenv func(string) string, results []urlcheck.Result, artifacts []string) error {
+	path := getenv("GITHUB_STEP_SUMMARY")
+	if path == "" {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	runURL := ""
+	if server, repo, id := getenv("GITHUB_SERVER_URL"), getenv("GITHUB_REPOSITORY"), getenv("GITHUB_RUN_ID"); server != "" && repo != "" && id != "" {
+		runURL = fmt.Sprintf("%s/%s/actions/runs/%s", server, repo, id)
+	}
+	if err := writeStepSummary(f, results, artifacts, runURL); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func writeStepSummary(out io.Writer, results []urlcheck.Result, artifacts []string, runURL string) error {
+	var ok, failed, skipped int
+	var failures []urlcheck.Result
+	for _, r := range results {
+		switch {
+		case r.SkipReason != "":
+			skipped++
+		case r.OK:
+			ok++
+		default:
+			failed++
+			failures = append(failures, r)
+		}
+	}
+	var b strings.Builder
+	b.WriteString("## urlcheck results\n\n")
+	b.WriteString("| Total | OK | Failed | Skipped |\n|---:|---:|---:|---:|\n")
+	fmt.Fprintf(&b, "| %d | %d | %d | %d |\n\n", len(results), ok, failed, skipped)
+	if len(failures) > 0 {
+		fmt.Fprintf(&b, "### Top failures\n\n| URL | Status | Error |\n|---|---:|---|\n")
+		for _, r := range failures[:min(len(failures), maxSummaryFailures)] {
+			fmt.Fprintf(&b, "| %s | %d | %s |\n", markdownCell(r.URL), r.Status, markdownCell(failureMessage(r)))
+		}
+		if len(failures) > maxSummaryFailures {
+			fmt.Fprintf(&b, "\n…and %d more.\n", len(failures)-maxSummaryFailures)
+		}
+		b.WriteString("\n")
+	}
+	if len(artifacts) > 0 {
+		b.WriteString("### Artifacts\n\n")
+		for _, a := range artifacts {
+			if runURL != "" {
+				fmt.Fprintf(&b, "- `%s` ([run](%s))\n", a, runURL)
+			} else {
+				fmt.Fprintf(&b, "- `%s`\n", a)
+			}
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+func outputArtifacts(outDir string, specs []string) []string {
+	var artifacts []string
+	if split := splitFiles(outDir); split != nil {
+		artifacts = append(artifacts, split[0], split[1])
+	}
+	return append(artifacts, reportFiles(specs)...)
+}
+
+func reportFiles(specs []string) []string {
+	var artifacts []string
+	for _, spec := range specs {
+		name, dest, _ := strings.Cut(spec, "=")
+		if name != "statsd" && dest != "" && dest != "-" {
+			artifacts = append(artifacts, dest)
+		}
+	}
+	return artifacts
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestAppendStepSummary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "summary.md")
+	if err := os.WriteFile(path, []byte("existing\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	env := map[string]string{
+		"GITHUB_STEP_SUMMARY": path,
+		"GITHUB_SERVER_URL":   "https://github.com",
+		"GITHUB_REPOSITORY":   "o/r",
+		"GITHUB_RUN_ID":       "42",
+	}
+	results := []urlcheck.Result{
+		{URL: "https://ok.example", OK: true, Status: 200},
+		{URL: "https://bad.example/a|b", Status: 500},
+	}
+	if err := appendStepSummary(func(k string) string { return env[k] }, results, outputArtifacts(".out", []string{"json=report.json"})); err != nil {
+		t.Fatalf("appendStepSummary: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	out := string(data)
+	for _, want := range []string{
+		"existing\n## urlcheck results",
+		"| 2 | 1 | 1 | 0 |",
+		`https://bad.example/a\|b`,
+		"- `report.json` ([run](https://github.com/o/r/actions/runs/42))",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestAppendStepSummaryNoopOutsideActions(t *testing.T) {
+	if err := appendStepSummary(func(string) string { return "" }, nil, nil); err != nil {
+		t.Fatalf("expected noop, got %v", err)
+	}
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func streamConflicts(cfg config) []string {
+	set := []struct {
+		name string
+		on   bool
+	}{
+		{"sample", cfg.sample != ""},
+		{"sort", cfg.sortBy != ""},
+		{"group-by", cfg.groupBy != ""},
+		{"verify-failures", cfg.reverify},
+		{"post-process", len(cfg.postProcess) > 0},
+		{"baseline", cfg.baseline != ""},
+		{"diff", cfg.diff},
+		{"resume", cfg.resume},
+		{"agent", len(cfg.agents) > 0},
+		{"report", cfg.report != ""},
+		{"har", cfg.har != ""},
+		{"bundle", cfg.bundle != ""},
+		{"history", cfg.history != ""},
+		{"serve", cfg.serve != ""},
+		{"webhook", cfg.webhook != "" || len(cfg.webhooks) > 0},
+		{"slack-webhook", cfg.slackHook != "" || cfg.slackChan != ""},
+		{"smtp", cfg.smtp != ""},
+	}
+	var names []string
+	for _, s := range set {
+		if s.on {
+			names = append(names, "-"+s.name)
+		}
+	}
+	return names
+}
+
+func streamSource(cfg config, stdin io.Reader, stop <-chan struct{}) (urlcheck.Source, func() error, error) {
+	sources, err := inputSources(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	closeInput := func() error { return nil }
+	if cfg.file != "" || len(sources) == 0 {
+		reader := stdin
+		if cfg.file != "" {
+			f, err := os.Open(cfg.file)
+			if err != nil {
+				return nil, nil, err
+			}
+			reader, closeInput = f, f.Close
+		}
+		if cfg.follow {
+			reader = follow(reader, stop)
+		}
+		sources = append(sources, urlcheck.LineSource(listLabel(cfg), reader))
+	}
+	return urlcheck.MultiSource(sources...), closeInput, nil
+}
+
+type filteredSource struct {
+	src    urlcheck.Source
+	filter urlFilter
+	skip   func(urlcheck.Result)
+}
+
+func (f filteredSource) Next() (string, urlcheck.Metadata, error) {
+	for {
+		u, meta, err := f.src.Next()
+		if err != nil {
+			return u, meta, err
+		}
+		if !f.filter.shard.keep(u) {
+			continue
+		}
+		if reason := f.filter.skipReason(u); reason != "" {
+			f.skip(urlcheck.Result{URL: u, SkipReason: reason, Origin: meta.Label, Source: meta.Location})
+			continue
+		}
+		return u, meta, nil
+	}
+}
+
+type splitWriter struct {
+	valid, invalid, skipped *os.File
+	skippedPath             string
+	wroteSkipped            bool
+	err                     error
+}
+
+func openSplitWriter(outDir string) (*splitWriter, error) {
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return nil, err
+	}
+	paths := splitFiles(outDir)
+	w := &splitWriter{skippedPath: paths[2]}
+	files := []**os.File{&w.valid, &w.invalid, &w.skipped}
+	for i, path := range paths {
+		f, err := os.Create(path)
+		if err != nil {
+			w.close()
+			return nil, err
+		}
+		*files[i] = f
+	}
+	return w, nil
+}
+
+func (w *splitWriter) write(r urlcheck.Result) {
+	if w.err != nil {
+		return
+	}
+	switch {
+	case r.SkipReason != "":
+		w.wroteSkipped = true
+		_, w.err = fmt.Fprintf(w.skipped, "%s\t%s\n", r.URL, r.SkipReason)
+	case r.OK:
+		_, w.err = fmt.Fprintln(w.valid, r.URL)
+	default:
+		_, w.err = fmt.Fprintln(w.invalid, r.URL)
+	}
+}
+
+func (w *splitWriter) close() error {
+	err := w.err
+	for _, f := range []*os.File{w.valid, w.invalid, w.skipped} {
+		if f == nil {
+			continue
+		}
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if w.skipped != nil && !w.wroteSkipped {
+		if rerr := os.Remove(w.skippedPath); err == nil {
+			err = rerr
+		}
+	}
+	return err
+}
+
+func runStream(cfg config, stdin io.Reader, stdout io.Writer) (int, error) {
+	if conflicts := streamConflicts(cfg); len(conflicts) > 0 {
+		return exitToolError, fmt.Errorf("-stream cannot be combined with %v, they need the full result set", conflicts)
+	}
+	if isRowInput(cfg.file) {
+		return exitToolError, fmt.Errorf("-stream reads one url per line; csv and json input are not supported")
+	}
+	if cfg.format != "table" && !streamingFormats[cfg.format] {
+		return exitToolError, fmt.Errorf("-stream writes ndjson; -format %s needs the full result set", cfg.format)
+	}
+	specs := cfg.outputs
+	if len(specs) == 0 {
+		specs = []string{"ndjson"}
+	}
+	if cfg.quiet {
+		stdout = io.Discard
+	}
+	var printer *printSink
+	if cfg.print != "" {
+		p, err := newPrintSink(cfg.print, stdout)
+		if err != nil {
+			return exitToolError, err
+		}
+		printer, stdout = p, io.Discard
+	}
+	sinks, err := newSinkSet(specs, "ndjson", stdout, false)
+	if err != nil {
+		return exitToolError, err
+	}
+	if printer != nil {
+		sinks.sinks = append(sinks.sinks, printer)
+	}
+	for _, sk := range sinks.sinks {
+		if fs, ok := sk.(*formatSink); ok && fs.enc == nil {
+			sinks.close(nil)
+			return exitToolError, fmt.Errorf("-stream cannot write -output %s, it needs the full result set", fs.format)
+		}
+	}
+	if cfg.onlyFails {
+		sinks.keep = failed
+	}
+	filter, err := newURLFilter(cfg)
+	if err != nil {
+		return exitToolError, err
+	}
+	policy, err := newExitPolicy(cfg)
+	if err != nil {
+		return exitToolError, err
+	}
+	opts, closeOptions, err := checkerOptions(cfg)
+	if err != nil {
+		return exitToolError, err
+	}
+	defer closeOptions()
+	var split *splitWriter
+	if cfg.outDir != "" && !cfg.noSplit {
+		if split, err = openSplitWriter(cfg.outDir); err != nil {
+			return exitToolError, err
+		}
+	}
+	var journal *checkpointFile
+	if cfg.ckptFile != "" {
+		if journal, err = openCheckpoint(cfg.ckptFile, false); err != nil {
+			return exitToolError, err
+		}
+	}
+	stop := interruptOnSignal()
+	src, closeInput, err := streamSource(cfg, stdin, stop)
+	if err != nil {
+		return exitToolError, err
+	}
+	defer closeInput()
+	var mu sync.Mutex
+	var t tally
+	emit := func(r urlcheck.Result) {
+		mu.Lock()
+		defer mu.Unlock()
+		t.add(r)
+		sinks.write(r)
+		if split != nil {
+			split.write(r)
+		}
+		if journal != nil {
+			journal.record(r)
+		}
+	}
+	opts = append(opts, urlcheck.WithLogger(slog.Default()), urlcheck.WithGracefulStop(stop, interruptGrace))
+	checker := urlcheck.NewChecker(cfg.concurrency, cfg.timeout, cfg.retries, nil, opts...)
+	defer pauseOnSignals(checker)()
+	checkErr := checker.CheckStream(context.Background(), filteredSource{src: src, filter: filter, skip: emit}, emit)
+	err = sinks.close(nil)
+	if split != nil {
+		if serr := split.close(); err == nil {
+			err = serr
+		}
+	}
+	if journal != nil {
+		if jerr := journal.close(); err == nil {
+			err = jerr
+		}
+	}
+	if checkErr != nil {
+		return exitToolError, checkErr
+	}
+	if err != nil {
+		return exitToolError, err
+	}
+	slog.Info("stream finished", "checked", t.checked, "failed", t.failures, "skipped", t.skipped, "partial", t.partial)
+	return t.code(policy), nil
+}
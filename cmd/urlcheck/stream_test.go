@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestRunStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+	cfg := parseFlags("check", []string{"-stream", "-out-dir", dir, "-exclude", "skip-me"})
+	stdin := strings.NewReader(server.URL + "/ok\n" + server.URL + "/missing\n" + server.URL + "/skip-me\n")
+	var out bytes.Buffer
+	code, err := runStream(cfg, stdin, &out)
+	if err != nil {
+		t.Fatalf("runStream: %v", err)
+	}
+	if code != exitFailures {
+		t.Fatalf("exit code = %d, want %d", code, exitFailures)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 ndjson lines, got %q", out.String())
+	}
+	for _, line := range lines {
+		var r urlcheck.Result
+		if err := json.Unmarshal([]byte(line), &r); err != nil || r.Origin != "stdin" {
+			t.Fatalf("unexpected line %q: %v", line, err)
+		}
+	}
+	for name, want := range map[string]string{
+		"valid.txt":   server.URL + "/ok\n",
+		"invalid.txt": server.URL + "/missing\n",
+		"skipped.txt": server.URL + "/skip-me\texcluded by pattern skip-me\n",
+	} {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil || string(got) != want {
+			t.Fatalf("%s = %q, %v; want %q", name, got, err, want)
+		}
+	}
+}
+
+func TestRunStreamRejectsFullResultOptions(t *testing.T) {
+	for _, args := range [][]string{
+		{"-stream", "-sort", "url"},
+		{"-stream", "-format", "json"},
+		{"-stream", "-output", "table"},
+	} {
+		cfg := parseFlags("check", append(args, "-no-split"))
+		if _, err := runStream(cfg, strings.NewReader("https://a.example\n"), &bytes.Buffer{}); err == nil {
+			t.Fatalf("%v: expected an error", args)
+		}
+	}
+}
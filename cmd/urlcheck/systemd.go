@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func sdNotify(state string) error {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return nil
+	}
+	if name, ok := strings.CutPrefix(socket, "@"); ok {
+		socket = "\x00" + name
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Write([]byte(state))
+	return err
+}
+
+func notifySystemd(state string) {
+	if err := sdNotify(state); err != nil {
+		slog.Warn("sd_notify error", "error", err)
+	}
+}
+
+func cycleStatus(results []urlcheck.Result) string {
+	failures := 0
+	for _, r := range results {
+		if failed(r) {
+			failures++
+		}
+	}
+	return fmt.Sprintf("STATUS=checked %d urls at %s, %d failed", len(results), time.Now().Format(time.TimeOnly), failures)
+}
+
+func watchdogInterval() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond / 2
+}
+
+// liveness tracks whether the watch loop is making progress: a url finished,
+// a cycle ended, or the loop is idle between cycles. The watchdog only pings
+// while it is, so a hung cycle lets systemd restart the service.
+type liveness struct {
+	last atomic.Int64
+	idle atomic.Bool
+}
+
+func newLiveness() *liveness {
+	l := &liveness{}
+	l.beat()
+	return l
+}
+
+func (l *liveness) beat() {
+	l.last.Store(time.Now().UnixNano())
+}
+
+func (l *liveness) setIdle(idle bool) {
+	l.beat()
+	l.idle.Store(idle)
+}
+
+func (l *liveness) alive(within time.Duration) bool {
+	return l.idle.Load() || time.Since(time.Unix(0, l.last.Load())) < within
+}
+
+// startWatchdog pings systemd every half WATCHDOG_USEC. With a non-nil live it
+// skips pings once live has seen no progress for a full WATCHDOG_USEC.
+func startWatchdog(stop <-chan struct{}, live *liveness) {
+	interval := watchdogInterval()
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if live == nil || live.alive(2*interval) {
+					notifySystemd("WATCHDOG=1")
+				}
+			}
+		}
+	}()
+}
+
+func shutdownOnSignal() <-chan struct{} {
+	stop := make(chan struct{})
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		s := <-sig
+		signal.Stop(sig)
+		slog.Info("shutting down", "signal", s.String())
+		notifySystemd("STOPPING=1")
+		close(stop)
+	}()
+	return stop
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func notifySocket(t *testing.T) *net.UnixConn {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram sockets unavailable: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	t.Setenv("NOTIFY_SOCKET", path)
+	return conn
+}
+
+func readNotify(t *testing.T, conn *net.UnixConn) string {
+	t.Helper()
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("reading notification: %v", err)
+	}
+	return string(buf[:n])
+}
+
+func TestSDNotify(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	if err := sdNotify("READY=1"); err != nil {
+		t.Fatalf("expected no-op without NOTIFY_SOCKET, got %v", err)
+	}
+	conn := notifySocket(t)
+	if err := sdNotify("READY=1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readNotify(t, conn); got != "READY=1" {
+		t.Fatalf("unexpected notification %q", got)
+	}
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	for _, tc := range []struct {
+		usec, pid string
+		want      time.Duration
+	}{
+		{"", "", 0},
+		{"nope", "", 0},
+		{"10000000", "", 5 * time.Second},
+		{"10000000", strconv.Itoa(os.Getpid()), 5 * time.Second},
+		{"10000000", "1", 0},
+	} {
+		t.Setenv("WATCHDOG_USEC", tc.usec)
+		t.Setenv("WATCHDOG_PID", tc.pid)
+		if got := watchdogInterval(); got != tc.want {
+			t.Errorf("WATCHDOG_USEC=%q WATCHDOG_PID=%q: got %v, want %v", tc.usec, tc.pid, got, tc.want)
+		}
+	}
+}
+
+func TestRunWatchNotifiesAndStopsCleanly(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+	list := filepath.Join(dir, "urls.txt")
+	if err := os.WriteFile(list, []byte(server.URL+"/ok\n"+server.URL+"/broken\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	conn := notifySocket(t)
+	shutdown := make(chan struct{})
+	cfg := config{file: list, format: "ndjson", outputs: []string{"ndjson=" + filepath.Join(dir, "out.ndjson")}, concurrency: 2, timeout: time.Second, interval: time.Hour, shutdown: shutdown}
+	done := make(chan error, 1)
+	go func() { done <- runWatch(cfg, false) }()
+	if got := readNotify(t, conn); got != "READY=1" {
+		t.Fatalf("expected readiness first, got %q", got)
+	}
+	if got := readNotify(t, conn); !strings.HasPrefix(got, "STATUS=checked 2 urls at ") || !strings.HasSuffix(got, ", 1 failed") {
+		t.Fatalf("unexpected status %q", got)
+	}
+	close(shutdown)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected a clean stop, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch did not stop")
+	}
+}
+
+func TestLivenessStopsWatchdogOnHungCycle(t *testing.T) {
+	live := newLiveness()
+	live.setIdle(true)
+	live.last.Store(time.Now().Add(-time.Hour).UnixNano())
+	if !live.alive(time.Second) {
+		t.Fatal("an idle loop waiting for its next cycle is alive")
+	}
+	live.setIdle(false)
+	if !live.alive(time.Second) {
+		t.Fatal("a cycle that just started is alive")
+	}
+	live.last.Store(time.Now().Add(-2 * time.Second).UnixNano())
+	if live.alive(time.Second) {
+		t.Fatal("a cycle without progress past the watchdog timeout must stop the pings")
+	}
+	live.beat()
+	if !live.alive(time.Second) {
+		t.Fatal("a finished url counts as progress")
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func writeTAP(out io.Writer, results []urlcheck.Result) error {
+	w := bufio.NewWriter(out)
+	fmt.Fprintln(w, "TAP version 13")
+	fmt.Fprintf(w, "1..%d\n", len(results))
+	for i, r := range results {
+		n := i + 1
+		switch {
+		case r.SkipReason != "":
+			fmt.Fprintf(w, "ok %d - %s # SKIP %s\n", n, r.URL, r.SkipReason)
+		case r.OK:
+			fmt.Fprintf(w, "ok %d - %s\n", n, r.URL)
+		default:
+			fmt.Fprintf(w, "not ok %d - %s\n", n, r.URL)
+			fmt.Fprintln(w, "  ---")
+			fmt.Fprintf(w, "  message: %s\n", strconv.Quote(failureMessage(r)))
+			fmt.Fprintf(w, "  status: %d\n", r.Status)
+			fmt.Fprintf(w, "  attempts: %d\n", r.Attempts)
+			if r.ErrorKind != "" {
+				fmt.Fprintf(w, "  kind: %s\n", r.ErrorKind)
+			}
+			if len(r.Labels) > 0 {
+				fmt.Fprintln(w, "  labels:")
+				for _, k := range slices.Sorted(maps.Keys(r.Labels)) {
+					fmt.Fprintf(w, "    %s: %s\n", strconv.Quote(k), strconv.Quote(r.Labels[k]))
+				}
+			}
+			fmt.Fprintln(w, "  ...")
+		}
+	}
+	return w.Flush()
+}
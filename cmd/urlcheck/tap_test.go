@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestWriteTAP(t *testing.T) {
+	results := []urlcheck.Result{
+		{URL: "https://ok.example", OK: true, Status: 200},
+		{URL: "https://bad.example", Status: 0, Error: "dial tcp: refused", ErrorKind: urlcheck.KindConnection, Attempts: 2},
+		{URL: "https://skip.example", SkipReason: "excluded"},
+	}
+	var buf bytes.Buffer
+	if err := writeTAP(&buf, results); err != nil {
+		t.Fatalf("writeTAP: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"TAP version 13\n1..3\n",
+		"ok 1 - https://ok.example\n",
+		"not ok 2 - https://bad.example\n  ---\n  message: \"dial tcp: refused\"\n",
+		"  kind: connection\n  ...\n",
+		"ok 3 - https://skip.example # SKIP excluded\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func templateFormatter(text string) (formatter, error) {
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	tmpl, err := template.New("result").Funcs(template.FuncMap{
+		"ms":    func(d time.Duration) int64 { return d.Milliseconds() },
+		"error": errorText,
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+	}).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return func(out io.Writer, results []urlcheck.Result) error {
+		w := bufio.NewWriter(out)
+		for _, r := range results {
+			if err := tmpl.Execute(w, r); err != nil {
+				return err
+			}
+		}
+		return w.Flush()
+	}, nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestTemplateFormatter(t *testing.T) {
+	write, err := templateFormatter(`{{.URL}} {{.Status}} {{ms .Duration}}ms{{if not .OK}} {{error .}}{{end}}`)
+	if err != nil {
+		t.Fatalf("templateFormatter: %v", err)
+	}
+	results := []urlcheck.Result{
+		{URL: "https://a.example", OK: true, Status: 200, Duration: 12 * time.Millisecond},
+		{URL: "https://b.example", Status: 503, Error: "unavailable"},
+	}
+	var buf bytes.Buffer
+	if err := write(&buf, results); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	want := "https://a.example 200 12ms\nhttps://b.example 503 0ms unavailable\n"
+	if buf.String() != want {
+		t.Fatalf("got %q, want %q", buf.String(), want)
+	}
+	if _, err := templateFormatter("{{.URL"); err == nil {
+		t.Fatalf("expected parse error")
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/reisei231/go-url-checker"
+
+func newTracing(endpoint string) (trace.Tracer, func(), error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, nil, fmt.Errorf("want an http(s) collector url, got %q", endpoint)
+	}
+	exp, err := otlptracehttp.New(context.Background(), otlptracehttp.WithEndpointURL(endpoint))
+	if err != nil {
+		return nil, nil, err
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", "urlcheck"))),
+	)
+	shutdown := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		tp.Shutdown(ctx)
+	}
+	return tp.Tracer(tracerName), shutdown, nil
+}
+
+func startRunSpan(ctx context.Context, tracer trace.Tracer, urls int) (context.Context, func([]urlcheck.Result)) {
+	if tracer == nil {
+		return ctx, func([]urlcheck.Result) {}
+	}
+	started := time.Now()
+	ctx, span := tracer.Start(ctx, "urlcheck.run", trace.WithAttributes(attribute.Int("urlcheck.urls", urls)))
+	return ctx, func(results []urlcheck.Result) {
+		s := urlcheck.Summarize(results, time.Since(started))
+		span.SetAttributes(
+			attribute.Int("urlcheck.ok", s.OK),
+			attribute.Int("urlcheck.broken", s.Broken),
+			attribute.Int("urlcheck.errored", s.Errored),
+			attribute.Int("urlcheck.skipped", s.Skipped),
+			attribute.Int64("urlcheck.p95_ms", s.P95.Milliseconds()),
+		)
+		if s.Broken+s.Errored > 0 {
+			span.SetStatus(codes.Error, "some urls failed")
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestStartRunSpan(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)).Tracer("test")
+	ctx, end := startRunSpan(context.Background(), tracer, 2)
+	if !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+		t.Fatal("expected run span in context")
+	}
+	end([]urlcheck.Result{{URL: "a", OK: true}, {URL: "b", Status: 404}})
+	spans := rec.Ended()
+	if len(spans) != 1 || spans[0].Name() != "urlcheck.run" || spans[0].Status().Code != codes.Error {
+		t.Fatalf("unexpected spans: %+v", spans)
+	}
+}
+
+func TestStartRunSpanWithoutTracer(t *testing.T) {
+	ctx, end := startRunSpan(context.Background(), nil, 1)
+	if trace.SpanFromContext(ctx).SpanContext().IsValid() {
+		t.Fatal("expected no span without a tracer")
+	}
+	end(nil)
+}
+
+func TestNewTracingRejectsBadEndpoint(t *testing.T) {
+	if _, _, err := newTracing("localhost:4318"); err == nil {
+		t.Fatal("expected error for invalid endpoint")
+	}
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	trendBuckets = 120
+	chartWidth   = 640
+	chartHeight  = 140
+	chartMargin  = 48
+)
+
+type trendPoint struct {
+	at     time.Time
+	checks int
+	ok     int
+	ms     float64
+}
+
+func (p trendPoint) availability() float64 {
+	return percent(p.ok, p.checks)
+}
+
+func (p trendPoint) mean() float64 {
+	return p.ms / float64(p.checks)
+}
+
+type trendSeries struct {
+	group  string
+	points []trendPoint
+}
+
+func trendGroup(raw, groupBy string) string {
+	if groupBy == "url" {
+		return raw
+	}
+	if host := hostOf(raw); host != "" {
+		return host
+	}
+	return "(no host)"
+}
+
+func loadTrend(db *sql.DB, since time.Time, groupBy string) ([]trendSeries, error) {
+	rows, err := db.Query(`SELECT runs.started_at, r.url, r.ok, r.duration_ms
+		FROM results r JOIN runs ON runs.id = r.run_id
+		WHERE runs.started_at >= ? ORDER BY runs.started_at`, since.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	type sample struct {
+		at    time.Time
+		group string
+		ok    bool
+		ms    float64
+	}
+	var samples []sample
+	for rows.Next() {
+		var s sample
+		var started, url string
+		if err := rows.Scan(&started, &url, &s.ok, &s.ms); err != nil {
+			return nil, err
+		}
+		s.at, _ = time.Parse(time.RFC3339Nano, started)
+		s.group = trendGroup(url, groupBy)
+		samples = append(samples, s)
+	}
+	if err := rows.Err(); err != nil || len(samples) == 0 {
+		return nil, err
+	}
+	first := samples[0].at
+	width := samples[len(samples)-1].at.Sub(first)/trendBuckets + 1
+	buckets := make(map[string]map[int]*trendPoint)
+	for _, s := range samples {
+		idx := int(s.at.Sub(first) / width)
+		if buckets[s.group] == nil {
+			buckets[s.group] = make(map[int]*trendPoint)
+		}
+		p := buckets[s.group][idx]
+		if p == nil {
+			p = &trendPoint{at: s.at}
+			buckets[s.group][idx] = p
+		}
+		p.checks++
+		p.ms += s.ms
+		if s.ok {
+			p.ok++
+		}
+	}
+	var out []trendSeries
+	for group, points := range buckets {
+		series := trendSeries{group: group}
+		for _, idx := range slices.Sorted(maps.Keys(points)) {
+			series.points = append(series.points, *points[idx])
+		}
+		out = append(out, series)
+	}
+	slices.SortFunc(out, func(a, b trendSeries) int { return strings.Compare(a.group, b.group) })
+	return out, nil
+}
+
+func chartSVG(title string, points []trendPoint, value func(trendPoint) float64, maxY float64, unit string) string {
+	var b strings.Builder
+	plotW, plotH := float64(chartWidth-2*chartMargin), float64(chartHeight-chartMargin)
+	fmt.Fprintf(&b, `<text x="%d" y="14" font-size="13">%s</text>`, chartMargin, html.EscapeString(title))
+	fmt.Fprintf(&b, `<line x1="%d" y1="20" x2="%d" y2="%.0f" stroke="#999"/>`, chartMargin, chartMargin, 20+plotH)
+	fmt.Fprintf(&b, `<line x1="%d" y1="%.0f" x2="%.0f" y2="%.0f" stroke="#999"/>`, chartMargin, 20+plotH, chartMargin+plotW, 20+plotH)
+	fmt.Fprintf(&b, `<text x="%d" y="26" font-size="10" text-anchor="end">%.0f%s</text>`, chartMargin-4, maxY, unit)
+	fmt.Fprintf(&b, `<text x="%d" y="%.0f" font-size="10" text-anchor="end">0%s</text>`, chartMargin-4, 20+plotH, unit)
+	if len(points) == 0 || maxY <= 0 {
+		return b.String()
+	}
+	start, end := points[0].at, points[len(points)-1].at
+	span := float64(end.Sub(start))
+	coords := make([]string, len(points))
+	for i, p := range points {
+		x := float64(chartMargin)
+		if span > 0 {
+			x += float64(p.at.Sub(start)) / span * plotW
+		}
+		y := 20 + plotH - min(value(p), maxY)/maxY*plotH
+		coords[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+		fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="2" fill="#2a6fdb"><title>%s: %.1f%s</title></circle>`, x, y, p.at.UTC().Format(time.RFC3339), value(p), unit)
+	}
+	fmt.Fprintf(&b, `<polyline fill="none" stroke="#2a6fdb" stroke-width="1.5" points="%s"/>`, strings.Join(coords, " "))
+	fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="10">%s</text>`, chartMargin, chartHeight+8, start.UTC().Format("2006-01-02 15:04"))
+	fmt.Fprintf(&b, `<text x="%.0f" y="%d" font-size="10" text-anchor="end">%s</text>`, chartMargin+plotW, chartHeight+8, end.UTC().Format("2006-01-02 15:04"))
+	return b.String()
+}
+
+func trendCharts(series trendSeries) [2]string {
+	peak := 0.0
+	for _, p := range series.points {
+		peak = max(peak, p.mean())
+	}
+	return [2]string{
+		chartSVG(series.group+" availability", series.points, trendPoint.availability, 100, "%"),
+		chartSVG(series.group+" mean latency", series.points, trendPoint.mean, peak, "ms"),
+	}
+}
+
+func writeTrendSVG(out io.Writer, all []trendSeries) error {
+	rowHeight := chartHeight + chartMargin/2
+	fmt.Fprintf(out, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="sans-serif">`+"\n", 2*chartWidth, max(len(all), 1)*rowHeight)
+	for i, series := range all {
+		charts := trendCharts(series)
+		for j, chart := range charts {
+			fmt.Fprintf(out, `<g transform="translate(%d,%d)">%s</g>`+"\n", j*chartWidth, i*rowHeight, chart)
+		}
+	}
+	_, err := fmt.Fprintln(out, "</svg>")
+	return err
+}
+
+type trendPage struct {
+	Generated string
+	Window    string
+	Groups    []trendGroupCharts
+}
+
+type trendGroupCharts struct {
+	Name   string
+	Charts []template.HTML
+}
+
+func writeTrendHTML(out io.Writer, all []trendSeries, window string) error {
+	page := trendPage{Generated: time.Now().UTC().Format(time.RFC3339), Window: window}
+	for _, series := range all {
+		g := trendGroupCharts{Name: series.group}
+		for _, chart := range trendCharts(series) {
+			g.Charts = append(g.Charts, template.HTML(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="sans-serif">%s</svg>`, chartWidth, chartHeight+chartMargin/2, chart)))
+		}
+		page.Groups = append(page.Groups, g)
+	}
+	return trendTemplate.Execute(out, page)
+}
+
+var trendTemplate = template.Must(template.New("trend").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>urlcheck trends</title>
+<style>body{font-family:system-ui,sans-serif;margin:2em;color:#222}h2{font-size:16px;margin:1.5em 0 .5em}</style>
+</head>
+<body>
+<h1>urlcheck trends</h1>
+<p>Last {{.Window}}, generated {{.Generated}}</p>
+{{range .Groups}}<h2>{{.Name}}</h2>
+{{range .Charts}}{{.}}{{end}}
+{{else}}<p>No history recorded in this window.</p>
+{{end}}</body>
+</html>
+`))
+
+func runReport(args []string, out io.Writer, now time.Time) error {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	dbPath := fs.String("db", "", "history database written by -history")
+	trend := fs.Bool("trend", false, "chart availability and mean latency over time per group")
+	window := fs.String("window", "30d", "how far back to chart (e.g. 24h, 7d, 30d)")
+	groupBy := fs.String("group", "host", "group urls by host or chart each url on its own: host|url")
+	format := fs.String("format", "html", "chart output: html|svg")
+	outFile := fs.String("o", "", "write the charts to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *dbPath == "" || !*trend {
+		return fmt.Errorf("usage: urlcheck report -db results.db -trend [-window 30d] [-group host|url] [-format html|svg] [-o file]")
+	}
+	if *groupBy != "host" && *groupBy != "url" {
+		return fmt.Errorf("unknown -group %q (want host|url)", *groupBy)
+	}
+	if *format != "html" && *format != "svg" {
+		return fmt.Errorf("unknown -format %q (want html|svg)", *format)
+	}
+	d, err := parseWindow(*window)
+	if err != nil {
+		return err
+	}
+	db, err := openHistory(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	all, err := loadTrend(db, now.Add(-d), *groupBy)
+	if err != nil {
+		return err
+	}
+	write := func(w io.Writer) error {
+		if *format == "svg" {
+			return writeTrendSVG(w, all)
+		}
+		return writeTrendHTML(w, all, *window)
+	}
+	if *outFile == "" {
+		return write(out)
+	}
+	f, err := os.Create(*outFile)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func trendHistory(t *testing.T, start time.Time) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "results.db")
+	for i := range 4 {
+		results := []urlcheck.Result{
+			{URL: "https://a.example/1", OK: true, Status: 200, Duration: time.Duration(10*(i+1)) * time.Millisecond},
+			{URL: "https://a.example/2", OK: i%2 == 0, Status: 200, Duration: 30 * time.Millisecond},
+			{URL: "https://b.example/", OK: true, Status: 200, Duration: 5 * time.Millisecond},
+		}
+		if err := saveHistory(path, start.Add(time.Duration(i)*time.Hour), results); err != nil {
+			t.Fatalf("saveHistory: %v", err)
+		}
+	}
+	return path
+}
+
+func TestLoadTrendGroupsByHost(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	db, err := openHistory(trendHistory(t, start))
+	if err != nil {
+		t.Fatalf("openHistory: %v", err)
+	}
+	defer db.Close()
+	series, err := loadTrend(db, start.Add(time.Hour), "host")
+	if err != nil {
+		t.Fatalf("loadTrend: %v", err)
+	}
+	if len(series) != 2 || series[0].group != "a.example" || series[1].group != "b.example" {
+		t.Fatalf("unexpected series: %+v", series)
+	}
+	a := series[0].points
+	if len(a) != 3 || !a[0].at.Equal(start.Add(time.Hour)) || a[0].availability() != 50 || a[1].availability() != 100 || a[0].mean() != 25 {
+		t.Fatalf("unexpected points: %+v", a)
+	}
+	series, err = loadTrend(db, start, "url")
+	if err != nil || len(series) != 3 || series[0].group != "https://a.example/1" {
+		t.Fatalf("unexpected url series: %+v (%v)", series, err)
+	}
+}
+
+func TestRunReportTrend(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	path := trendHistory(t, start)
+	var buf bytes.Buffer
+	if err := runReport([]string{"-db", path, "-trend", "-window", "7d"}, &buf, start.Add(24*time.Hour)); err != nil {
+		t.Fatalf("runReport: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"<h2>a.example</h2>", "<h2>b.example</h2>", "a.example availability", "b.example mean latency", "<polyline"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in report:\n%s", want, out)
+		}
+	}
+	buf.Reset()
+	if err := runReport([]string{"-db", path, "-trend", "-format", "svg"}, &buf, start.Add(24*time.Hour)); err != nil {
+		t.Fatalf("runReport svg: %v", err)
+	}
+	if err := xml.Unmarshal(buf.Bytes(), new(struct{})); err != nil {
+		t.Fatalf("svg output is not well-formed: %v\n%s", err, buf.String())
+	}
+	buf.Reset()
+	if err := runReport([]string{"-db", path, "-trend", "-window", "1h"}, &buf, start.Add(48*time.Hour)); err != nil {
+		t.Fatalf("runReport: %v", err)
+	}
+	if !strings.Contains(buf.String(), "No history recorded") {
+		t.Fatalf("expected an empty report, got:\n%s", buf.String())
+	}
+}
+
+func TestRunReportUsage(t *testing.T) {
+	for _, args := range [][]string{{"-trend"}, {"-db", "x.db"}, {"-db", "x.db", "-trend", "-group", "label"}, {"-db", "x.db", "-trend", "-format", "png"}} {
+		if err := runReport(args, &bytes.Buffer{}, time.Now()); err == nil {
+			t.Fatalf("expected an error for %v", args)
+		}
+	}
+}
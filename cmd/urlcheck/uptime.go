@@ -0,0 +1,124 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+type uptimeStats struct {
+	checks int
+	ok     int
+	mean   time.Duration
+}
+
+func (s uptimeStats) availability() float64 {
+	return percent(s.ok, s.checks)
+}
+
+func parseWindow(spec string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(spec, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid window %q", spec)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(spec)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid window %q", spec)
+	}
+	return d, nil
+}
+
+func uptimeSince(db *sql.DB, since time.Time) (map[string]uptimeStats, error) {
+	rows, err := db.Query(`SELECT r.url, COUNT(*), SUM(CASE WHEN r.ok THEN 1 ELSE 0 END), AVG(r.duration_ms)
+		FROM results r JOIN runs ON runs.id = r.run_id
+		WHERE runs.started_at >= ?
+		GROUP BY r.url`, since.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	stats := make(map[string]uptimeStats)
+	for rows.Next() {
+		var url string
+		var s uptimeStats
+		var ms float64
+		if err := rows.Scan(&url, &s.checks, &s.ok, &ms); err != nil {
+			return nil, err
+		}
+		s.mean = time.Duration(ms * float64(time.Millisecond))
+		stats[url] = s
+	}
+	return stats, rows.Err()
+}
+
+func runUptime(args []string, out io.Writer, now time.Time) error {
+	fs := flag.NewFlagSet("uptime", flag.ContinueOnError)
+	dbPath := fs.String("db", "", "history database written by -history")
+	windows := fs.String("windows", "7d,30d", "comma-separated windows to report (e.g. 24h,7d,30d)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *dbPath == "" {
+		return fmt.Errorf("usage: urlcheck uptime -db results.db [-windows 7d,30d]")
+	}
+	specs := strings.Split(*windows, ",")
+	durations := make([]time.Duration, len(specs))
+	for i, spec := range specs {
+		d, err := parseWindow(strings.TrimSpace(spec))
+		if err != nil {
+			return err
+		}
+		durations[i] = d
+	}
+	db, err := openHistory(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	perWindow := make([]map[string]uptimeStats, len(durations))
+	urls := make(map[string]bool)
+	for i, d := range durations {
+		stats, err := uptimeSince(db, now.Add(-d))
+		if err != nil {
+			return err
+		}
+		perWindow[i] = stats
+		for url := range stats {
+			urls[url] = true
+		}
+	}
+	sorted := make([]string, 0, len(urls))
+	for url := range urls {
+		sorted = append(sorted, url)
+	}
+	sort.Strings(sorted)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	header := []string{"URL"}
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		header = append(header, "UPTIME "+spec, "MEAN "+spec)
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, url := range sorted {
+		row := []string{url}
+		for _, stats := range perWindow {
+			s, ok := stats[url]
+			if !ok {
+				row = append(row, "-", "-")
+				continue
+			}
+			row = append(row, fmt.Sprintf("%.2f%% (%d)", s.availability(), s.checks), s.mean.Round(time.Millisecond).String())
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
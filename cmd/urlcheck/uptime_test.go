@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestUptimeReportsWindows(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.db")
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	runs := []struct {
+		at     time.Time
+		result urlcheck.Result
+	}{
+		{now.Add(-20 * 24 * time.Hour), urlcheck.Result{URL: "https://a.example", Status: 500, Duration: 300 * time.Millisecond}},
+		{now.Add(-2 * 24 * time.Hour), urlcheck.Result{URL: "https://a.example", OK: true, Status: 200, Duration: 100 * time.Millisecond}},
+		{now.Add(-1 * 24 * time.Hour), urlcheck.Result{URL: "https://a.example", OK: true, Status: 200, Duration: 200 * time.Millisecond}},
+	}
+	for _, run := range runs {
+		if err := saveHistory(path, run.at, []urlcheck.Result{run.result}); err != nil {
+			t.Fatalf("saveHistory: %v", err)
+		}
+	}
+	var out bytes.Buffer
+	if err := runUptime([]string{"-db", path}, &out, now); err != nil {
+		t.Fatalf("runUptime: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+	fields := strings.Fields(lines[1])
+	want := []string{"https://a.example", "100.00%", "(2)", "150ms", "66.67%", "(3)", "200ms"}
+	if strings.Join(fields, " ") != strings.Join(want, " ") {
+		t.Fatalf("got %v, want %v", fields, want)
+	}
+}
+
+func TestParseWindow(t *testing.T) {
+	if d, err := parseWindow("7d"); err != nil || d != 7*24*time.Hour {
+		t.Fatalf("unexpected window %v (%v)", d, err)
+	}
+	if d, err := parseWindow("12h"); err != nil || d != 12*time.Hour {
+		t.Fatalf("unexpected window %v (%v)", d, err)
+	}
+	for _, bad := range []string{"0d", "xd", "soon"} {
+		if _, err := parseWindow(bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
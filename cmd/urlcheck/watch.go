@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const watchHistoryRuns = 1000
+
+type watcher struct {
+	cfg     config
+	tracer  trace.Tracer
+	filter  urlFilter
+	checker *urlcheck.Checker
+	store   *resultStore
+	out     io.Writer
+	color   bool
+	urls    []string
+	static  []string
+	label   string
+	skipped []urlcheck.Result
+	prov    provenance
+	notify  []notifier
+	lastOK  map[string]bool
+	close   func()
+}
+
+func newWatcher(cfg config, tracer trace.Tracer, out io.Writer, color bool) (*watcher, error) {
+	if cfg.interval <= 0 {
+		return nil, fmt.Errorf("-watch requires a positive -interval")
+	}
+	if cfg.file == "" && len(cfg.scan) == 0 && len(cfg.sitemaps) == 0 {
+		return nil, fmt.Errorf("-watch needs -file, -scan or -sitemap so the url set can be reloaded")
+	}
+	w, err := buildWatcher(cfg, tracer, out, color)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.reload(); err != nil {
+		w.close()
+		return nil, err
+	}
+	return w, nil
+}
+
+func buildWatcher(cfg config, tracer trace.Tracer, out io.Writer, color bool) (*watcher, error) {
+	filter, err := newURLFilter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	opts, closeOptions, err := checkerOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, urlcheck.WithLogger(slog.Default()))
+	notifiers, err := notifiersFor(cfg)
+	if err != nil {
+		closeOptions()
+		return nil, err
+	}
+	if tracer != nil {
+		opts = append(opts, urlcheck.WithTracer(tracer))
+	}
+	if cfg.shutdown != nil {
+		opts = append(opts, urlcheck.WithGracefulStop(cfg.shutdown, interruptGrace))
+	}
+	if cfg.live != nil {
+		opts = append(opts, urlcheck.WithOnResult(func(urlcheck.Result) { cfg.live.beat() }))
+	}
+	w := &watcher{
+		cfg:    cfg,
+		tracer: tracer,
+		notify: notifiers,
+		filter: filter,
+		store:  newResultStore(watchHistoryRuns),
+		out:    out,
+		color:  color,
+		close:  closeOptions,
+	}
+	rank, err := parsePriority(cfg.priority, func(u string) map[string]string { return w.prov[u].Labels }, cfg.history)
+	if err != nil {
+		closeOptions()
+		return nil, err
+	}
+	if rank != nil {
+		opts = append(opts, urlcheck.WithPriority(rank))
+	}
+	w.checker = urlcheck.NewChecker(cfg.concurrency, cfg.timeout, cfg.retries, nil, opts...)
+	return w, nil
+}
+
+func (w *watcher) reload() error {
+	if w.static != nil {
+		w.urls, w.skipped = w.filter.apply(w.static)
+		w.prov = make(provenance)
+		for _, u := range w.static {
+			w.prov[u] = urlcheck.Metadata{Label: w.label}
+		}
+		return nil
+	}
+	urls, prov, err := loadInputs(w.cfg, nil)
+	if err != nil {
+		return err
+	}
+	w.urls, w.skipped = w.filter.apply(urls)
+	w.prov = prov
+	return nil
+}
+
+func (w *watcher) cycle(ctx context.Context) ([]urlcheck.Result, error) {
+	started := time.Now()
+	ctx, endRun := startRunSpan(ctx, w.tracer, len(w.urls))
+	results, err := w.checker.Check(ctx, w.urls)
+	endRun(results)
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i] = w.prov.attribute(results[i])
+	}
+	for _, r := range w.skipped {
+		results = append(results, w.prov.attribute(r))
+	}
+	sinks, err := newSinkSet(w.cfg.outputs, w.cfg.format, w.out, w.color)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range results {
+		sinks.write(r)
+	}
+	if err := sinks.close(results); err != nil {
+		return nil, err
+	}
+	if w.cfg.history != "" {
+		if err := saveHistory(w.cfg.history, started, results); err != nil {
+			slog.Warn("history error", "error", err)
+		}
+	}
+	w.store.add(started, results)
+	select {
+	case <-w.cfg.shutdown:
+		slog.Info("skipping notifications for the interrupted run")
+	default:
+		w.sendNotifications(ctx, results, time.Since(started))
+	}
+	return results, nil
+}
+
+func (w *watcher) sendNotifications(ctx context.Context, results []urlcheck.Result, elapsed time.Duration) {
+	first := w.lastOK == nil
+	var changes []stateChange
+	w.lastOK, changes = trackState(w.lastOK, results)
+	if len(w.notify) == 0 {
+		return
+	}
+	var n notification
+	switch {
+	case len(changes) > 0:
+		n = newNotification(eventStateChange, results, elapsed, changes)
+	case first && slices.ContainsFunc(results, failed):
+		n = newNotification(eventFailures, results, elapsed, nil)
+	default:
+		return
+	}
+	if err := notifyAll(ctx, w.notify, n); err != nil {
+		slog.Warn("notify error", "error", err)
+	}
+}
+
+func runWatch(cfg config, color bool) error {
+	var tracer trace.Tracer
+	if cfg.otlp != "" {
+		t, shutdown, err := newTracing(cfg.otlp)
+		if err != nil {
+			return err
+		}
+		defer shutdown()
+		tracer = t
+	}
+	cfg.live = newLiveness()
+	if cfg.schedule != "" {
+		return runSchedule(cfg, tracer, color)
+	}
+	w, err := newWatcher(cfg, tracer, os.Stdout, color)
+	if err != nil {
+		return err
+	}
+	defer w.close()
+	stopServing, err := serveLive(cfg.serve, w.store)
+	if err != nil {
+		return err
+	}
+	defer stopServing()
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	notifySystemd("READY=1")
+	startWatchdog(cfg.shutdown, cfg.live)
+	for {
+		started := time.Now()
+		cfg.live.setIdle(false)
+		results, err := w.cycle(context.Background())
+		if err != nil {
+			slog.Error("check error", "error", err)
+		} else {
+			notifySystemd(cycleStatus(results))
+		}
+		cfg.live.setIdle(true)
+		select {
+		case <-time.After(cfg.interval - time.Since(started)):
+		case <-cfg.shutdown:
+			return nil
+		case <-hup:
+			if err := w.reload(); err != nil {
+				slog.Error("reload error", "error", err, "kept_urls", len(w.urls))
+				continue
+			}
+			slog.Info("reloaded", "urls", len(w.urls))
+		}
+	}
+}
+
+func serveLive(addr string, store *resultStore) (func(), error) {
+	if addr == "" {
+		return func() {}, nil
+	}
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("serving results", "addr", addr)
+	srv := &http.Server{Handler: grafanaHandler(store)}
+	go func() {
+		if err := srv.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fatal("serve error", "error", err)
+		}
+	}()
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), interruptGrace)
+		defer cancel()
+		srv.Shutdown(ctx)
+	}, nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+func TestWatcherCycleAndReload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	list := filepath.Join(t.TempDir(), "urls.txt")
+	if err := os.WriteFile(list, []byte(server.URL+"/ok\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	cfg := config{file: list, format: "ndjson", concurrency: 2, timeout: time.Second, interval: time.Minute}
+	w, err := newWatcher(cfg, nil, &out, false)
+	if err != nil {
+		t.Fatalf("newWatcher: %v", err)
+	}
+	results, err := w.cycle(context.Background())
+	if err != nil || len(results) != 1 || !results[0].OK {
+		t.Fatalf("unexpected first cycle: %+v (%v)", results, err)
+	}
+	if err := os.WriteFile(list, []byte(server.URL+"/ok\n"+server.URL+"/broken\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.reload(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	results, err = w.cycle(context.Background())
+	if err != nil || len(results) != 2 || results[1].Status != http.StatusNotFound {
+		t.Fatalf("unexpected second cycle: %+v (%v)", results, err)
+	}
+	if got := strings.Count(out.String(), "\n"); got != 3 {
+		t.Fatalf("expected 3 ndjson lines across cycles, got %d", got)
+	}
+	if runs := w.store.snapshot(); len(runs) != 2 {
+		t.Fatalf("expected 2 stored runs, got %d", len(runs))
+	}
+}
+
+func TestNewWatcherRequiresReloadableSource(t *testing.T) {
+	if _, err := newWatcher(config{interval: time.Minute}, nil, &bytes.Buffer{}, false); err == nil {
+		t.Fatal("expected error without a file source")
+	}
+	if _, err := newWatcher(config{file: "urls.txt"}, nil, &bytes.Buffer{}, false); err == nil {
+		t.Fatal("expected error without an interval")
+	}
+}
+
+func TestWatcherAppliesPriority(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		order = append(order, r.URL.Path)
+		mu.Unlock()
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+	list := filepath.Join(dir, "urls.txt")
+	if err := os.WriteFile(list, []byte(server.URL+"/low\n"+server.URL+"/high\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config{file: list, format: "ndjson", concurrency: 1, timeout: time.Second, interval: time.Minute, priority: "label:tier=critical"}
+	w, err := newWatcher(cfg, nil, &bytes.Buffer{}, false)
+	if err != nil {
+		t.Fatalf("newWatcher: %v", err)
+	}
+	w.prov[server.URL+"/high"] = urlcheck.Metadata{Labels: map[string]string{"tier": "critical"}}
+	results, err := w.cycle(context.Background())
+	if err != nil || len(results) != 2 {
+		t.Fatalf("unexpected cycle: %+v (%v)", results, err)
+	}
+	if len(order) != 2 || order[0] != "/high" {
+		t.Fatalf("-priority was not applied in watch mode: %v", order)
+	}
+	if results[0].URL != server.URL+"/low" {
+		t.Fatalf("results should stay in input order: %+v", results)
+	}
+}
@@ -0,0 +1,136 @@
+package render
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"github.com/reisei231/go-url-checker/internal/urlcheck"
+)
+
+type Options struct {
+	ExecPath string
+	Timeout  time.Duration
+	Settle   time.Duration
+}
+
+type Browser struct {
+	opts   Options
+	once   sync.Once
+	ctx    context.Context
+	cancel func()
+	err    error
+}
+
+func New(opts Options) *Browser {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 15 * time.Second
+	}
+	return &Browser{opts: opts}
+}
+
+func (b *Browser) start() error {
+	b.once.Do(func() {
+		allocOpts := chromedp.DefaultExecAllocatorOptions[:]
+		if b.opts.ExecPath != "" {
+			allocOpts = append(allocOpts, chromedp.ExecPath(b.opts.ExecPath))
+		}
+		allocCtx, cancelAlloc := chromedp.NewExecAllocator(context.Background(), allocOpts...)
+		ctx, cancelBrowser := chromedp.NewContext(allocCtx)
+		b.ctx = ctx
+		b.cancel = func() {
+			cancelBrowser()
+			cancelAlloc()
+		}
+		b.err = chromedp.Run(ctx)
+	})
+	return b.err
+}
+
+func (b *Browser) Close() {
+	if b.cancel != nil {
+		b.cancel()
+	}
+}
+
+func (b *Browser) Render(ctx context.Context, url string) (*urlcheck.RenderReport, error) {
+	if err := b.start(); err != nil {
+		return nil, err
+	}
+	tab, cancelTab := chromedp.NewContext(b.ctx)
+	defer cancelTab()
+	stop := context.AfterFunc(ctx, cancelTab)
+	defer stop()
+	tab, cancelTimeout := context.WithTimeout(tab, b.opts.Timeout)
+	defer cancelTimeout()
+	events := newCollector()
+	chromedp.ListenTarget(tab, events.handle)
+	var state struct {
+		ReadyState string `json:"readyState"`
+		Title      string `json:"title"`
+		Text       int    `json:"text"`
+	}
+	err := chromedp.Run(tab,
+		chromedp.Navigate(url),
+		chromedp.Sleep(b.opts.Settle),
+		chromedp.Evaluate(`({readyState: document.readyState, title: document.title, text: document.body ? document.body.innerText.trim().length : 0})`, &state),
+	)
+	if err != nil {
+		return nil, err
+	}
+	report := events.report()
+	report.ReadyState = state.ReadyState
+	report.Title = state.Title
+	report.TextLength = state.Text
+	return report, nil
+}
+
+type collector struct {
+	mu       sync.Mutex
+	requests map[network.RequestID]string
+	jsErrors []string
+	failed   []string
+}
+
+func newCollector() *collector {
+	return &collector{requests: make(map[network.RequestID]string)}
+}
+
+func (c *collector) handle(ev any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch ev := ev.(type) {
+	case *network.EventRequestWillBeSent:
+		c.requests[ev.RequestID] = ev.Request.URL
+	case *network.EventResponseReceived:
+		if ev.Response != nil && ev.Response.Status >= 400 {
+			c.failed = append(c.failed, ev.Response.URL+": "+strconv.FormatInt(ev.Response.Status, 10))
+		}
+	case *network.EventLoadingFailed:
+		if !ev.Canceled {
+			c.failed = append(c.failed, c.requests[ev.RequestID]+": "+ev.ErrorText)
+		}
+	case *runtime.EventExceptionThrown:
+		c.jsErrors = append(c.jsErrors, exceptionText(ev.ExceptionDetails))
+	}
+}
+
+func (c *collector) report() *urlcheck.RenderReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return &urlcheck.RenderReport{JSErrors: c.jsErrors, FailedRequests: c.failed}
+}
+
+func exceptionText(d *runtime.ExceptionDetails) string {
+	if d == nil {
+		return "unknown exception"
+	}
+	if d.Exception != nil && d.Exception.Description != "" {
+		return d.Exception.Description
+	}
+	return d.Text
+}
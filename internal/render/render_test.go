@@ -0,0 +1,38 @@
+package render
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/runtime"
+)
+
+func TestCollectorReportsErrorsAndFailedRequests(t *testing.T) {
+	c := newCollector()
+	c.handle(&network.EventRequestWillBeSent{RequestID: "1", Request: &network.Request{URL: "https://cdn.example/app.js"}})
+	c.handle(&network.EventRequestWillBeSent{RequestID: "2", Request: &network.Request{URL: "https://cdn.example/ad.js"}})
+	c.handle(&network.EventLoadingFailed{RequestID: "1", ErrorText: "net::ERR_NAME_NOT_RESOLVED"})
+	c.handle(&network.EventLoadingFailed{RequestID: "2", ErrorText: "net::ERR_ABORTED", Canceled: true})
+	c.handle(&network.EventResponseReceived{RequestID: "3", Response: &network.Response{URL: "https://example.com/api", Status: 500}})
+	c.handle(&network.EventResponseReceived{RequestID: "4", Response: &network.Response{URL: "https://example.com/ok", Status: 200}})
+	c.handle(&runtime.EventExceptionThrown{ExceptionDetails: &runtime.ExceptionDetails{Text: "Uncaught", Exception: &runtime.RemoteObject{Description: "TypeError: x is undefined"}}})
+	c.handle(&runtime.EventExceptionThrown{ExceptionDetails: &runtime.ExceptionDetails{Text: "Uncaught SyntaxError"}})
+	r := c.report()
+	if len(r.JSErrors) != 2 || r.JSErrors[0] != "TypeError: x is undefined" || r.JSErrors[1] != "Uncaught SyntaxError" {
+		t.Fatalf("unexpected js errors: %q", r.JSErrors)
+	}
+	want := []string{"https://cdn.example/app.js: net::ERR_NAME_NOT_RESOLVED", "https://example.com/api: 500"}
+	if len(r.FailedRequests) != len(want) || r.FailedRequests[0] != want[0] || r.FailedRequests[1] != want[1] {
+		t.Fatalf("unexpected failed requests: %q", r.FailedRequests)
+	}
+}
+
+func TestRenderFailsWithoutBrowser(t *testing.T) {
+	b := New(Options{ExecPath: "/nonexistent/chrome", Timeout: time.Second})
+	defer b.Close()
+	if _, err := b.Render(context.Background(), "https://example.com"); err == nil {
+		t.Fatal("expected an error when the browser cannot start")
+	}
+}
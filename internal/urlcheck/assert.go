@@ -0,0 +1,236 @@
+package urlcheck
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+type Assertion struct {
+	URL          string            `json:"url,omitempty"`
+	Pattern      string            `json:"pattern,omitempty"`
+	Status       int               `json:"status,omitempty"`
+	StatusIn     []int             `json:"status_in,omitempty"`
+	Headers      map[string]string `json:"headers,omitempty"`
+	BodyContains []string          `json:"body_contains,omitempty"`
+	BodyRegex    []string          `json:"body_regex,omitempty"`
+	BodyNotRegex []string          `json:"body_not_regex,omitempty"`
+	TLS          *bool             `json:"tls,omitempty"`
+	MaxLatency   string            `json:"max_latency,omitempty"`
+	FinalURL     string            `json:"final_url,omitempty"`
+	Schema       json.RawMessage   `json:"schema,omitempty"`
+
+	pattern    *regexp.Regexp
+	bodyRegex  []*regexp.Regexp
+	bodyNot    []*regexp.Regexp
+	maxLatency time.Duration
+	schema     *jsonschema.Schema
+}
+
+func ParseAssertions(data []byte) ([]Assertion, error) {
+	var assertions []Assertion
+	if err := json.Unmarshal(data, &assertions); err != nil {
+		return nil, err
+	}
+	for i := range assertions {
+		a := &assertions[i]
+		if a.URL == "" && a.Pattern == "" {
+			return nil, fmt.Errorf("assertion %d: url or pattern is required", i)
+		}
+		if a.Pattern != "" {
+			re, err := regexp.Compile(a.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("assertion %d: invalid pattern: %w", i, err)
+			}
+			a.pattern = re
+		}
+		for _, expr := range a.BodyRegex {
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return nil, fmt.Errorf("assertion %d: invalid body_regex: %w", i, err)
+			}
+			a.bodyRegex = append(a.bodyRegex, re)
+		}
+		for _, expr := range a.BodyNotRegex {
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return nil, fmt.Errorf("assertion %d: invalid body_not_regex: %w", i, err)
+			}
+			a.bodyNot = append(a.bodyNot, re)
+		}
+		if a.MaxLatency != "" {
+			d, err := time.ParseDuration(a.MaxLatency)
+			if err != nil {
+				return nil, fmt.Errorf("assertion %d: invalid max_latency: %w", i, err)
+			}
+			a.maxLatency = d
+		}
+		if len(a.Schema) > 0 {
+			sch, err := compileSchema(a.Schema, i)
+			if err != nil {
+				return nil, fmt.Errorf("assertion %d: invalid schema: %w", i, err)
+			}
+			a.schema = sch
+		}
+	}
+	return assertions, nil
+}
+
+func compileSchema(raw json.RawMessage, i int) (*jsonschema.Schema, error) {
+	compiler := jsonschema.NewCompiler()
+	var path string
+	if json.Unmarshal(raw, &path) == nil {
+		return compiler.Compile(path)
+	}
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("assertion-%d.json", i)
+	if err := compiler.AddResource(name, doc); err != nil {
+		return nil, err
+	}
+	return compiler.Compile(name)
+}
+
+func schemaViolations(sch *jsonschema.Schema, body []byte) []string {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+	if err != nil {
+		return []string{"body is not json: " + err.Error()}
+	}
+	err = sch.Validate(doc)
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		if err != nil {
+			return []string{"schema: " + err.Error()}
+		}
+		return nil
+	}
+	var out []string
+	for _, unit := range verr.BasicOutput().Errors {
+		if unit.Error == nil {
+			continue
+		}
+		loc := unit.InstanceLocation
+		if loc == "" {
+			loc = "/"
+		}
+		out = append(out, fmt.Sprintf("schema %s: %s", loc, unit.Error))
+	}
+	return out
+}
+
+func WithAssertions(assertions ...Assertion) Option {
+	return func(c *Checker) {
+		for _, a := range assertions {
+			if a.URL != "" {
+				if normalized, _, err := NormalizeURL(a.URL); err == nil {
+					a.URL = normalized
+				}
+			}
+			c.assertions = append(c.assertions, a)
+		}
+	}
+}
+
+func (a Assertion) matches(target string) bool {
+	if a.URL != "" && a.URL == target {
+		return true
+	}
+	return a.pattern != nil && a.pattern.MatchString(target)
+}
+
+func (a Assertion) needsBody() bool {
+	return len(a.BodyContains) > 0 || len(a.bodyRegex) > 0 || len(a.bodyNot) > 0 || a.schema != nil
+}
+
+func (c *Checker) assertResponse(target string, resp *http.Response, body []byte) (statusOK *bool, failed []string) {
+	for _, a := range c.assertions {
+		if !a.matches(target) {
+			continue
+		}
+		if a.Status != 0 {
+			ok := resp.StatusCode == a.Status
+			statusOK = &ok
+			if !ok {
+				failed = append(failed, fmt.Sprintf("status %d, want %d", resp.StatusCode, a.Status))
+			}
+		}
+		if len(a.StatusIn) > 0 {
+			ok := slices.Contains(a.StatusIn, resp.StatusCode)
+			statusOK = &ok
+			if !ok {
+				failed = append(failed, fmt.Sprintf("status %d, want one of %s", resp.StatusCode, joinInts(a.StatusIn)))
+			}
+		}
+		if a.TLS != nil && *a.TLS != (resp.TLS != nil) {
+			if *a.TLS {
+				failed = append(failed, "response was not served over tls")
+			} else {
+				failed = append(failed, "response was served over tls")
+			}
+		}
+		for name, want := range a.Headers {
+			got, present := resp.Header[http.CanonicalHeaderKey(name)]
+			switch {
+			case !present:
+				failed = append(failed, "missing header "+http.CanonicalHeaderKey(name))
+			case want != "" && !strings.Contains(strings.Join(got, ", "), want):
+				failed = append(failed, fmt.Sprintf("header %s %q does not contain %q", http.CanonicalHeaderKey(name), strings.Join(got, ", "), want))
+			}
+		}
+		for _, needle := range a.BodyContains {
+			if !strings.Contains(string(body), needle) {
+				failed = append(failed, fmt.Sprintf("body does not contain %q", needle))
+			}
+		}
+		for _, re := range a.bodyRegex {
+			if !re.Match(body) {
+				failed = append(failed, fmt.Sprintf("body does not match /%s/", re))
+			}
+		}
+		for _, re := range a.bodyNot {
+			if re.Match(body) {
+				failed = append(failed, fmt.Sprintf("body matches /%s/", re))
+			}
+		}
+		if a.schema != nil {
+			failed = append(failed, schemaViolations(a.schema, body)...)
+		}
+		if a.FinalURL != "" && resp.Request != nil && resp.Request.URL.String() != a.FinalURL {
+			failed = append(failed, fmt.Sprintf("final url %s, want %s", resp.Request.URL, a.FinalURL))
+		}
+	}
+	return statusOK, failed
+}
+
+func (c *Checker) assertLatency(target string, res Result) Result {
+	for _, a := range c.assertions {
+		if a.maxLatency > 0 && a.matches(target) && res.Duration > a.maxLatency && res.Status != 0 {
+			res.FailedAssertions = append(res.FailedAssertions, fmt.Sprintf("took %s, max %s", res.Duration.Round(time.Millisecond), a.maxLatency))
+			if res.OK {
+				res.OK = false
+				res.Error = "assertion failed: " + res.FailedAssertions[0]
+				res.ErrorKind = KindAssertion
+			}
+		}
+	}
+	return res
+}
+
+func joinInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,171 @@
+package urlcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAssertionsEvaluateContract(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+		case "/new":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte("<title>Welcome v2</title>"))
+		case "/gone":
+			w.WriteHeader(http.StatusGone)
+		case "/api":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer server.Close()
+	assertions, err := ParseAssertions([]byte(`[
+		{"url": "` + server.URL + `/old", "status": 200, "final_url": "` + server.URL + `/new",
+		 "headers": {"content-type": "text/html"}, "body_contains": ["Welcome"], "body_regex": ["v\\d+"]},
+		{"url": "` + server.URL + `/gone", "status": 410},
+		{"pattern": "/api$", "headers": {"Content-Type": "json", "X-Request-Id": ""}, "max_latency": "1h"}
+	]`))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	checker := NewChecker(3, time.Second, 0, server.Client(), WithAssertions(assertions...))
+	results, err := checker.Check(context.Background(), []string{server.URL + "/old", server.URL + "/gone", server.URL + "/api"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !results[0].OK || len(results[0].FailedAssertions) != 0 {
+		t.Fatalf("expected contract to hold, got %+v", results[0])
+	}
+	if !results[1].OK {
+		t.Fatalf("expected asserted 410 to pass, got %+v", results[1])
+	}
+	api := results[2]
+	if api.OK || api.ErrorKind != KindAssertion || len(api.FailedAssertions) != 2 {
+		t.Fatalf("expected two failed assertions, got %+v", api)
+	}
+	joined := strings.Join(api.FailedAssertions, "; ")
+	if !strings.Contains(joined, `header Content-Type "text/plain" does not contain "json"`) || !strings.Contains(joined, "missing header X-Request-Id") {
+		t.Fatalf("unexpected failures: %v", api.FailedAssertions)
+	}
+}
+
+func TestAssertLatency(t *testing.T) {
+	assertions, err := ParseAssertions([]byte(`[{"pattern": ".", "max_latency": "10ms"}]`))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	c := NewChecker(1, time.Second, 0, nil, WithAssertions(assertions...))
+	res := c.assertLatency("https://a.example", Result{OK: true, Status: 200, Duration: 20 * time.Millisecond})
+	if res.OK || res.Error != "assertion failed: took 20ms, max 10ms" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestParseAssertionsRejectsInvalid(t *testing.T) {
+	for _, data := range []string{`[{}]`, `[{"pattern": "("}]`, `[{"url": "x", "max_latency": "soon"}]`, `[{"url": "x", "schema": {"type": 7}}]`, `[{"url": "x", "schema": "missing.json"}]`, `{`} {
+		if _, err := ParseAssertions([]byte(data)); err == nil {
+			t.Fatalf("expected error for %s", data)
+		}
+	}
+}
+
+func TestAssertionsValidateJSONSchema(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/good":
+			w.Write([]byte(`{"id": 1, "tags": ["a"]}`))
+		case "/bad":
+			w.Write([]byte(`{"id": "one", "tags": [2]}`))
+		case "/html":
+			w.Write([]byte("<html>maintenance</html>"))
+		}
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "item.json")
+	schema := `{"type": "object", "required": ["id"], "properties": {"id": {"type": "integer"}, "tags": {"type": "array", "items": {"type": "string"}}}}`
+	if err := os.WriteFile(schemaFile, []byte(schema), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	assertions, err := ParseAssertions([]byte(`[
+		{"pattern": "/(good|html)$", "schema": ` + schema + `},
+		{"url": "` + server.URL + `/bad", "schema": "` + schemaFile + `"}
+	]`))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	checker := NewChecker(3, time.Second, 0, server.Client(), WithAssertions(assertions...))
+	results, err := checker.Check(context.Background(), []string{server.URL + "/good", server.URL + "/bad", server.URL + "/html"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !results[0].OK {
+		t.Fatalf("expected valid payload to pass, got %+v", results[0])
+	}
+	bad := results[1]
+	if bad.OK || bad.ErrorKind != KindAssertion || len(bad.FailedAssertions) != 2 {
+		t.Fatalf("expected two schema violations, got %+v", bad)
+	}
+	joined := strings.Join(bad.FailedAssertions, "; ")
+	if !strings.Contains(joined, "schema /id: ") || !strings.Contains(joined, "schema /tags/0: ") {
+		t.Fatalf("unexpected failures: %v", bad.FailedAssertions)
+	}
+	if r := results[2]; r.OK || !strings.HasPrefix(r.FailedAssertions[0], "body is not json") {
+		t.Fatalf("expected non-json body to fail, got %+v", r)
+	}
+}
+
+func TestAssertionsStatusSetTLSAndForbiddenBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/accepted":
+			w.WriteHeader(http.StatusAccepted)
+		case "/teapot":
+			w.WriteHeader(http.StatusTeapot)
+			w.Write([]byte("maintenance mode"))
+		}
+	}))
+	defer server.Close()
+	assertions, err := ParseAssertions([]byte(`[
+		{"pattern": ".", "status_in": [200, 202], "body_not_regex": ["(?i)maintenance"], "tls": true}
+	]`))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	results, _ := NewChecker(2, time.Second, 0, nil, WithAssertions(assertions...)).Check(context.Background(), []string{server.URL + "/accepted", server.URL + "/teapot"})
+	if r := results[0]; r.OK || len(r.FailedAssertions) != 1 || r.FailedAssertions[0] != "response was not served over tls" {
+		t.Fatalf("unexpected result %+v", r)
+	}
+	want := []string{"status 418, want one of 200, 202", "response was not served over tls", "body matches /(?i)maintenance/"}
+	if r := results[1]; r.OK || strings.Join(r.FailedAssertions, "; ") != strings.Join(want, "; ") {
+		t.Fatalf("unexpected result %+v", r)
+	}
+}
+
+func TestAssertionURLIsNormalized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer server.Close()
+	written := strings.Replace(server.URL, "http://", "HTTP://", 1) + "/gone#top"
+	assertions, err := ParseAssertions([]byte(`[{"url": "` + written + `", "status": 410}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := NewChecker(1, time.Second, 0, server.Client(), WithAssertions(assertions...)).Check(context.Background(), []string{server.URL + "/gone"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results[0].OK {
+		t.Fatalf("assertion for %q was not applied: %+v", written, results[0])
+	}
+}
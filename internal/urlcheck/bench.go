@@ -0,0 +1,46 @@
+package urlcheck
+
+import (
+	"context"
+	"time"
+)
+
+type BenchResult struct {
+	URL       string  `json:"url"`
+	Requests  int     `json:"requests"`
+	Errors    int     `json:"errors"`
+	ErrorRate float64 `json:"error_rate"`
+	Latency   Latency `json:"latency"`
+}
+
+func (c *Checker) Bench(ctx context.Context, urls []string, runs int) ([]BenchResult, error) {
+	if runs < 1 {
+		runs = 1
+	}
+	targets := make([]string, 0, len(urls)*runs)
+	for range runs {
+		targets = append(targets, urls...)
+	}
+	results, err := c.Check(ctx, targets)
+	out := make([]BenchResult, len(urls))
+	durations := make([][]time.Duration, len(urls))
+	for i, r := range results {
+		idx := i % len(urls)
+		if r.SkipReason != "" {
+			continue
+		}
+		out[idx].Requests++
+		if !r.OK {
+			out[idx].Errors++
+		}
+		durations[idx] = append(durations[idx], r.Duration)
+	}
+	for i := range out {
+		out[i].URL = urls[i]
+		if out[i].Requests > 0 {
+			out[i].ErrorRate = float64(out[i].Errors) / float64(out[i].Requests)
+		}
+		out[i].Latency = latencyOf(durations[i])
+	}
+	return out, err
+}
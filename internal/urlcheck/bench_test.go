@@ -0,0 +1,50 @@
+package urlcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBenchAggregatesPerURL(t *testing.T) {
+	var flaky int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow":
+			time.Sleep(20 * time.Millisecond)
+		case "/flaky":
+			if atomic.AddInt32(&flaky, 1)%2 == 0 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}
+	}))
+	defer server.Close()
+	checker := NewChecker(4, time.Second, 0, server.Client())
+	results, err := checker.Bench(context.Background(), []string{server.URL + "/slow", server.URL + "/flaky"}, 6)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	slow, flakyRes := results[0], results[1]
+	if slow.URL != server.URL+"/slow" || slow.Requests != 6 || slow.Errors != 0 || slow.Latency.Min < 20*time.Millisecond {
+		t.Fatalf("unexpected slow result %+v", slow)
+	}
+	if slow.Latency.Min > slow.Latency.P50 || slow.Latency.P50 > slow.Latency.P99 || slow.Latency.P99 > slow.Latency.Max {
+		t.Fatalf("percentiles out of order: %+v", slow.Latency)
+	}
+	if flakyRes.Requests != 6 || flakyRes.Errors != 3 || flakyRes.ErrorRate != 0.5 {
+		t.Fatalf("unexpected flaky result %+v", flakyRes)
+	}
+}
+
+func TestBenchEmpty(t *testing.T) {
+	results, err := NewChecker(1, time.Second, 0, nil).Bench(context.Background(), nil, 3)
+	if err != nil || len(results) != 0 {
+		t.Fatalf("expected no results, got %v (%v)", results, err)
+	}
+}
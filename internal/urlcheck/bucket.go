@@ -0,0 +1,259 @@
+package urlcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const gcsReadScope = "https://www.googleapis.com/auth/devstorage.read_only"
+
+func WithBucketAuth() Option {
+	return func(c *Checker) {
+		c.bucketAuth = true
+	}
+}
+
+type bucketObject struct {
+	provider string
+	bucket   string
+	key      string
+	region   string
+}
+
+type bucketStore struct {
+	once      sync.Once
+	awsCreds  aws.CredentialsProvider
+	awsRegion string
+	gcsToken  oauth2.TokenSource
+	awsErr    error
+	gcsErr    error
+	s3URL     string
+	gcsURL    string
+}
+
+var s3Host = regexp.MustCompile(`^(?:(.+)\.)?s3(?:[.-]([a-z0-9-]+))?\.amazonaws\.com$`)
+
+func (c *Checker) bucketTarget(target string) (bucketObject, bool) {
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" {
+		return bucketObject{}, false
+	}
+	key := strings.TrimPrefix(u.Path, "/")
+	switch strings.ToLower(u.Scheme) {
+	case "s3":
+		return bucketObject{provider: "s3", bucket: u.Host, key: key}, true
+	case "gs":
+		return bucketObject{provider: "gs", bucket: u.Host, key: key}, true
+	case "https":
+		if !c.bucketAuth {
+			return bucketObject{}, false
+		}
+	default:
+		return bucketObject{}, false
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "storage.googleapis.com" {
+		bucket, key, _ := strings.Cut(key, "/")
+		return bucketObject{provider: "gs", bucket: bucket, key: key}, bucket != ""
+	}
+	if bucket, ok := strings.CutSuffix(host, ".storage.googleapis.com"); ok {
+		return bucketObject{provider: "gs", bucket: bucket, key: key}, true
+	}
+	m := s3Host.FindStringSubmatch(host)
+	if m == nil {
+		return bucketObject{}, false
+	}
+	obj := bucketObject{provider: "s3", bucket: m[1], key: key}
+	if m[2] != "" && m[2] != "dualstack" {
+		obj.region = m[2]
+	}
+	if obj.bucket == "" {
+		obj.bucket, obj.key, _ = strings.Cut(key, "/")
+	}
+	return obj, obj.bucket != ""
+}
+
+// load resolves credentials once per checker. It uses a background context
+// because the providers keep it for later refreshes, which must outlive the
+// job (or watch cycle) that happened to trigger loading.
+func (s *bucketStore) load() {
+	s.once.Do(func() {
+		ctx := context.Background()
+		if cfg, err := config.LoadDefaultConfig(ctx); err == nil {
+			s.awsCreds, s.awsRegion = cfg.Credentials, cfg.Region
+		} else {
+			s.awsErr = err
+		}
+		creds, err := google.FindDefaultCredentials(ctx, gcsReadScope)
+		switch {
+		case err == nil:
+			s.gcsToken = creds.TokenSource
+		case os.Getenv("GOOGLE_APPLICATION_CREDENTIALS") != "":
+			s.gcsErr = err
+		}
+	})
+}
+
+func (c *Checker) checkBucket(ctx context.Context, target string, obj bucketObject) Result {
+	res := Result{URL: target}
+	if obj.key == "" {
+		res.Error = "bucket url needs an object key"
+		res.ErrorKind = KindInvalidRequest
+		return res
+	}
+	c.buckets.load()
+	override := c.hostOverride(target)
+	retries := c.retriesFor(override)
+	for {
+		res.Attempts++
+		reqCtx, cancel := context.WithTimeout(ctx, c.timeoutFor(override))
+		head, err := c.headObject(reqCtx, obj)
+		cancel()
+		if err != nil {
+			res.Error = err.Error()
+			res.ErrorKind = classifyError(err)
+			if res.Attempts > retries || !c.shouldRetry(err) || ctx.Err() != nil || !c.spendRetry() {
+				return res
+			}
+			continue
+		}
+		status := head.status
+		res.Status = status
+		res.Error, res.ErrorKind = "", ""
+		res.OK = status >= 200 && status < 300
+		switch {
+		case res.OK:
+		case status == http.StatusNotFound:
+			res.Error = fmt.Sprintf("%s://%s/%s: no such object", obj.provider, obj.bucket, obj.key)
+			res.ErrorKind = KindNotFound
+		case status == http.StatusUnauthorized || status == http.StatusForbidden:
+			res.Error = fmt.Sprintf("%s://%s/%s: access denied (status %d)", obj.provider, obj.bucket, obj.key, status)
+			if head.unsigned != nil {
+				res.Error += "; request was sent unsigned: " + head.unsigned.Error()
+			}
+			res.ErrorKind = KindAuth
+		default:
+			res.Error = "status " + strconv.Itoa(status)
+		}
+		return res
+	}
+}
+
+// objectHead is the outcome of a HEAD request for a bucket object.
+type objectHead struct {
+	status int
+	region string
+	// unsigned is why the request went out anonymously although credentials
+	// were configured; public objects still pass, denied ones report it.
+	unsigned error
+}
+
+func (c *Checker) headObject(ctx context.Context, obj bucketObject) (objectHead, error) {
+	if obj.provider == "gs" {
+		return c.headGCS(ctx, obj)
+	}
+	region := obj.region
+	if region == "" {
+		region = c.buckets.awsRegion
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+	head, err := c.headS3(ctx, obj, region)
+	if err == nil && head.region != "" && head.region != region {
+		head, err = c.headS3(ctx, obj, head.region)
+	}
+	return head, err
+}
+
+func (c *Checker) headS3(ctx context.Context, obj bucketObject, region string) (objectHead, error) {
+	endpoint := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", obj.bucket, region, escapeKey(obj.key))
+	if c.buckets.s3URL != "" {
+		endpoint = c.buckets.s3URL + "/" + obj.bucket + "/" + escapeKey(obj.key)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, endpoint, nil)
+	if err != nil {
+		return objectHead{}, &requestError{err: err}
+	}
+	credErr := c.buckets.awsErr
+	if c.buckets.awsCreds != nil {
+		creds, err := c.buckets.awsCreds.Retrieve(ctx)
+		if err == nil {
+			req.Header.Set("X-Amz-Content-Sha256", "UNSIGNED-PAYLOAD")
+			signer := v4.NewSigner(func(o *v4.SignerOptions) { o.DisableURIPathEscaping = true })
+			if err := signer.SignHTTP(ctx, creds, req, "UNSIGNED-PAYLOAD", "s3", region, time.Now()); err != nil {
+				return objectHead{}, &requestError{err: err}
+			}
+		} else {
+			credErr = err
+		}
+	}
+	head := objectHead{}
+	if credErr != nil {
+		head.unsigned = fmt.Errorf("aws credentials: %w", credErr)
+	}
+	resp, err := c.do(c.client, req)
+	if err != nil {
+		return head, err
+	}
+	resp.Body.Close()
+	head.status, head.region = resp.StatusCode, resp.Header.Get("X-Amz-Bucket-Region")
+	return head, nil
+}
+
+func (c *Checker) headGCS(ctx context.Context, obj bucketObject) (objectHead, error) {
+	base := "https://storage.googleapis.com"
+	if c.buckets.gcsURL != "" {
+		base = c.buckets.gcsURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, base+"/"+obj.bucket+"/"+escapeKey(obj.key), nil)
+	if err != nil {
+		return objectHead{}, &requestError{err: err}
+	}
+	credErr := c.buckets.gcsErr
+	if c.buckets.gcsToken != nil {
+		if token, err := c.buckets.gcsToken.Token(); err == nil {
+			token.SetAuthHeader(req)
+		} else {
+			credErr = err
+		}
+	}
+	head := objectHead{}
+	if credErr != nil {
+		head.unsigned = fmt.Errorf("gcs credentials: %w", credErr)
+	}
+	resp, err := c.do(c.client, req)
+	if err != nil {
+		return head, err
+	}
+	resp.Body.Close()
+	head.status = resp.StatusCode
+	return head, nil
+}
+
+func escapeKey(key string) string {
+	var b strings.Builder
+	for _, ch := range []byte(key) {
+		switch {
+		case 'a' <= ch && ch <= 'z', 'A' <= ch && ch <= 'Z', '0' <= ch && ch <= '9', strings.IndexByte("-_.~/", ch) >= 0:
+			b.WriteByte(ch)
+		default:
+			fmt.Fprintf(&b, "%%%02X", ch)
+		}
+	}
+	return b.String()
+}
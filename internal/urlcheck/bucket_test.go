@@ -0,0 +1,137 @@
+package urlcheck
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"golang.org/x/oauth2"
+)
+
+func TestBucketTarget(t *testing.T) {
+	checker := NewChecker(1, time.Second, 0, nil, WithBucketAuth())
+	cases := []struct {
+		in   string
+		want bucketObject
+	}{
+		{"s3://releases/v1/app.tar.gz", bucketObject{provider: "s3", bucket: "releases", key: "v1/app.tar.gz"}},
+		{"gs://assets/logo.png", bucketObject{provider: "gs", bucket: "assets", key: "logo.png"}},
+		{"https://releases.s3.eu-west-1.amazonaws.com/app.zip", bucketObject{provider: "s3", bucket: "releases", key: "app.zip", region: "eu-west-1"}},
+		{"https://releases.s3.amazonaws.com/app.zip", bucketObject{provider: "s3", bucket: "releases", key: "app.zip"}},
+		{"https://s3.us-west-2.amazonaws.com/releases/app.zip", bucketObject{provider: "s3", bucket: "releases", key: "app.zip", region: "us-west-2"}},
+		{"https://storage.googleapis.com/assets/img/logo.png", bucketObject{provider: "gs", bucket: "assets", key: "img/logo.png"}},
+		{"https://assets.storage.googleapis.com/logo.png", bucketObject{provider: "gs", bucket: "assets", key: "logo.png"}},
+	}
+	for _, tc := range cases {
+		got, ok := checker.bucketTarget(tc.in)
+		if !ok || got != tc.want {
+			t.Fatalf("bucketTarget(%q) = %+v, %v; want %+v", tc.in, got, ok, tc.want)
+		}
+	}
+	if _, ok := checker.bucketTarget("https://example.com/a"); ok {
+		t.Fatal("plain https url detected as a bucket")
+	}
+	if _, ok := NewChecker(1, time.Second, 0, nil).bucketTarget("https://releases.s3.amazonaws.com/app.zip"); ok {
+		t.Fatal("https bucket urls should need WithBucketAuth")
+	}
+}
+
+func TestCheckBucket(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("unexpected method %s", r.Method)
+		}
+		auth := r.Header.Get("Authorization")
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/gcs/"):
+			if auth != "Bearer gcs-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		case !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/"):
+			w.WriteHeader(http.StatusForbidden)
+			return
+		case !strings.Contains(auth, "/eu-west-1/s3/"):
+			w.Header().Set("X-Amz-Bucket-Region", "eu-west-1")
+			w.WriteHeader(http.StatusMovedPermanently)
+			return
+		}
+		switch r.URL.EscapedPath() {
+		case "/releases/v1/app%20final%2B1.tar.gz", "/gcs/assets/logo.png":
+			w.WriteHeader(http.StatusOK)
+		case "/releases/private.zip":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	checker := NewChecker(2, time.Second, 0, nil)
+	checker.buckets.once.Do(func() {})
+	checker.buckets.awsCreds = credentials.NewStaticCredentialsProvider("AKID", "secret", "")
+	checker.buckets.gcsToken = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "gcs-token"})
+	checker.buckets.s3URL = server.URL
+	checker.buckets.gcsURL = server.URL + "/gcs"
+	results, err := checker.Check(context.Background(), []string{
+		"s3://releases/v1/app final+1.tar.gz",
+		"gs://assets/logo.png",
+		"s3://releases/missing.zip",
+		"s3://releases/private.zip",
+		"s3://releases",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if r := results[i]; !r.OK || r.Status != http.StatusOK {
+			t.Fatalf("unexpected result %+v", r)
+		}
+	}
+	if r := results[2]; r.OK || r.ErrorKind != KindNotFound || r.Error != "s3://releases/missing.zip: no such object" {
+		t.Fatalf("unexpected missing object result %+v", r)
+	}
+	if r := results[3]; r.OK || r.ErrorKind != KindAuth {
+		t.Fatalf("unexpected denied object result %+v", r)
+	}
+	if r := results[4]; r.OK || r.ErrorKind != KindInvalidRequest {
+		t.Fatalf("unexpected bucket-only result %+v", r)
+	}
+}
+
+func TestCheckBucketReportsCredentialFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("expected an unsigned request, got %q", r.Header.Get("Authorization"))
+		}
+		if strings.HasSuffix(r.URL.Path, "/public.txt") {
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	checker := NewChecker(1, time.Second, 0, nil)
+	checker.buckets.once.Do(func() {})
+	checker.buckets.gcsToken = oauth2.ReuseTokenSource(nil, failingTokenSource{})
+	checker.buckets.gcsURL = server.URL
+	results, err := checker.Check(context.Background(), []string{"gs://assets/public.txt", "gs://assets/private.txt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results[0].OK {
+		t.Fatalf("public objects should pass without credentials: %+v", results[0])
+	}
+	if r := results[1]; r.OK || r.ErrorKind != KindAuth || !strings.Contains(r.Error, "unsigned: gcs credentials: token expired and refresh failed") {
+		t.Fatalf("expected the credential error in the result, got %+v", r)
+	}
+}
+
+type failingTokenSource struct{}
+
+func (failingTokenSource) Token() (*oauth2.Token, error) {
+	return nil, errors.New("token expired and refresh failed")
+}
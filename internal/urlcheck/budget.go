@@ -0,0 +1,49 @@
+package urlcheck
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+var errDeadline = errors.New("deadline")
+
+func WithBudget(d time.Duration) Option {
+	return func(c *Checker) {
+		c.budget = d
+	}
+}
+
+func WithMaxDuration(d time.Duration) Option {
+	return func(c *Checker) {
+		c.maxDuration = d
+	}
+}
+
+func WithRetryBudget(n int) Option {
+	return func(c *Checker) {
+		if n >= 0 {
+			c.retryBudget = &atomic.Int64{}
+			c.retryBudget.Store(int64(n))
+		}
+	}
+}
+
+func (c *Checker) spendRetry() bool {
+	return c.retryBudget == nil || c.retryBudget.Add(-1) >= 0
+}
+
+func Coverage(results []Result) (attempted, total int) {
+	for _, r := range results {
+		if r.ErrorKind == KindNotAttempted {
+			total++
+			continue
+		}
+		if r.SkipReason != "" {
+			continue
+		}
+		attempted++
+		total++
+	}
+	return attempted, total
+}
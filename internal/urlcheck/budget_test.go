@@ -0,0 +1,94 @@
+package urlcheck
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBudgetMarksRemainderNotAttempted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(40 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	var urls []string
+	for i := 0; i < 20; i++ {
+		urls = append(urls, fmt.Sprintf("%s/%d", server.URL, i))
+	}
+	checker := NewChecker(1, time.Second, 0, server.Client(), WithBudget(100*time.Millisecond))
+	results, err := checker.Check(context.Background(), urls)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !results[0].OK {
+		t.Fatalf("expected first url to be checked, got %+v", results[0])
+	}
+	last := results[len(results)-1]
+	if last.ErrorKind != KindNotAttempted || last.URL != urls[len(urls)-1] {
+		t.Fatalf("expected last url not attempted, got %+v", last)
+	}
+	attempted, total := Coverage(results)
+	if total != len(urls) || attempted == 0 || attempted >= total {
+		t.Fatalf("unexpected coverage %d/%d", attempted, total)
+	}
+}
+
+func TestMaxDurationReportsRemainderAsDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(time.Second)
+		}
+	}))
+	defer server.Close()
+	urls := []string{server.URL + "/fast", server.URL + "/slow", server.URL + "/never"}
+	checker := NewChecker(1, 5*time.Second, 0, server.Client(), WithMaxDuration(200*time.Millisecond))
+	start := time.Now()
+	results, err := checker.Check(context.Background(), urls)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 800*time.Millisecond {
+		t.Fatalf("run was not cut at the deadline, took %v", elapsed)
+	}
+	if !results[0].OK {
+		t.Fatalf("expected the first url to be checked, got %+v", results[0])
+	}
+	if r := results[1]; r.ErrorKind != KindNotAttempted || r.SkipReason != "not completed (deadline)" {
+		t.Fatalf("expected the in-flight url to be cut, got %+v", r)
+	}
+	if r := results[2]; r.URL != urls[2] || r.SkipReason != "not checked (deadline)" {
+		t.Fatalf("expected the remaining url to be reported, got %+v", r)
+	}
+}
+
+func TestRetryBudgetCapsRetriesAcrossTheRun(t *testing.T) {
+	urls := make([]string, 5)
+	for i := range urls {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		urls[i] = "http://" + lis.Addr().String() + "/"
+		lis.Close()
+	}
+	checker := NewChecker(1, time.Second, 3, nil, WithRetryBudget(4))
+	results, err := checker.Check(context.Background(), urls)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	attempts := 0
+	for _, r := range results {
+		if r.OK || r.ErrorKind != KindConnection {
+			t.Fatalf("unexpected result %+v", r)
+		}
+		attempts += r.Attempts
+	}
+	if attempts != len(urls)+4 || results[0].Attempts != 4 || results[len(results)-1].Attempts != 1 {
+		t.Fatalf("expected 4 retries in total, got %d attempts: %+v", attempts, results)
+	}
+}
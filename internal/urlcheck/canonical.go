@@ -0,0 +1,184 @@
+package urlcheck
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+type pageLinks struct {
+	canonicals []string
+	alternates map[string]string
+}
+
+type linkedPage struct {
+	done   chan struct{}
+	status int
+	final  string
+	links  pageLinks
+	err    error
+}
+
+func WithCanonicalChecks(fail bool) Option {
+	return func(c *Checker) {
+		c.canonical = true
+		c.canonFails = fail
+	}
+}
+
+func (c *Checker) checkCanonical(ctx context.Context, resp *http.Response, body []byte, res Result) Result {
+	if !c.canonical || !res.OK || resp.Request == nil || !isHTML(resp.Header.Get("Content-Type")) {
+		return res
+	}
+	self := resp.Request.URL.String()
+	links := parseLinkTags(self, body)
+	var findings []string
+	if len(links.canonicals) > 1 {
+		findings = append(findings, "multiple canonical links: "+strings.Join(links.canonicals, ", "))
+	}
+	if len(links.canonicals) > 0 && !sameURL(links.canonicals[0], self) {
+		target := links.canonicals[0]
+		page := c.linkedPage(ctx, target)
+		switch {
+		case page.err != nil:
+			findings = append(findings, "canonical "+target+" unreachable: "+page.err.Error())
+		case page.status < 200 || page.status > 299:
+			findings = append(findings, "canonical "+target+" returned "+strconv.Itoa(page.status))
+		case !sameURL(page.final, target):
+			findings = append(findings, "canonical "+target+" redirects to "+page.final)
+		case len(page.links.canonicals) > 0 && !sameURL(page.links.canonicals[0], target):
+			findings = append(findings, "canonical "+target+" declares canonical "+page.links.canonicals[0])
+		}
+	}
+	for _, lang := range slices.Sorted(maps.Keys(links.alternates)) {
+		target := links.alternates[lang]
+		if sameURL(target, self) {
+			continue
+		}
+		page := c.linkedPage(ctx, target)
+		switch {
+		case page.err != nil:
+			findings = append(findings, "hreflang "+lang+" "+target+" unreachable: "+page.err.Error())
+		case page.status < 200 || page.status > 299:
+			findings = append(findings, "hreflang "+lang+" "+target+" returned "+strconv.Itoa(page.status))
+		case !linksBack(page.links, self, links.canonicals):
+			findings = append(findings, "hreflang "+lang+" "+target+" has no return link")
+		}
+	}
+	res.LinkFindings = findings
+	if c.canonFails && len(findings) > 0 && res.Error == "" {
+		res.OK = false
+		res.Error = "link tags: " + findings[0]
+		res.ErrorKind = KindCanonical
+	}
+	return res
+}
+
+// linkedPage fetches a canonical or hreflang target once per Check call, so
+// pages that share them don't refetch while watch cycles still see changes.
+func (c *Checker) linkedPage(ctx context.Context, target string) *linkedPage {
+	page := &linkedPage{done: make(chan struct{})}
+	loaded := false
+	if run, ok := ctx.Value(runKey{}).(*runState); ok {
+		var entry any
+		entry, loaded = run.pages.LoadOrStore(target, page)
+		page = entry.(*linkedPage)
+	}
+	if !loaded {
+		page.status, page.final, page.links, page.err = c.fetchPage(ctx, target)
+		close(page.done)
+	}
+	<-page.done
+	return page
+}
+
+func (c *Checker) fetchPage(ctx context.Context, target string) (int, string, pageLinks, error) {
+	if err := c.pace(ctx, target); err != nil {
+		return 0, "", pageLinks{}, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return 0, "", pageLinks{}, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, "", pageLinks{}, err
+	}
+	defer resp.Body.Close()
+	final := resp.Request.URL.String()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxInspectBytes))
+	if err != nil {
+		return resp.StatusCode, final, pageLinks{}, err
+	}
+	return resp.StatusCode, final, parseLinkTags(final, body), nil
+}
+
+func parseLinkTags(base string, body []byte) pageLinks {
+	links := pageLinks{alternates: make(map[string]string)}
+	z := html.NewTokenizer(bytes.NewReader(body))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return links
+		}
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+		name, hasAttr := z.TagName()
+		if string(name) != "link" || !hasAttr {
+			continue
+		}
+		attrs := make(map[string]string)
+		for hasAttr {
+			var k, v []byte
+			k, v, hasAttr = z.TagAttr()
+			attrs[string(k)] = strings.TrimSpace(string(v))
+		}
+		href, err := resolveLocation(base, attrs["href"])
+		if attrs["href"] == "" || err != nil {
+			continue
+		}
+		rels := strings.Fields(strings.ToLower(attrs["rel"]))
+		for _, rel := range rels {
+			switch {
+			case rel == "canonical":
+				links.canonicals = append(links.canonicals, href)
+			case rel == "alternate" && attrs["hreflang"] != "":
+				links.alternates[strings.ToLower(attrs["hreflang"])] = href
+			}
+		}
+	}
+}
+
+func linksBack(links pageLinks, self string, canonicals []string) bool {
+	for _, href := range links.alternates {
+		if sameURL(href, self) {
+			return true
+		}
+		for _, canonical := range canonicals {
+			if sameURL(href, canonical) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func sameURL(a, b string) bool {
+	if na, _, err := NormalizeURL(a); err == nil {
+		a = na
+	}
+	if nb, _, err := NormalizeURL(b); err == nil {
+		b = nb
+	}
+	return a == b
+}
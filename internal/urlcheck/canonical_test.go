@@ -0,0 +1,91 @@
+package urlcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCanonicalAndHreflangChecks(t *testing.T) {
+	var deFetches atomic.Int32
+	pages := map[string]string{
+		"/en":     `<link rel="canonical" href="/en"><link rel="alternate" hreflang="en" href="/en"><link rel="alternate" hreflang="de" href="/de"><link rel="alternate" hreflang="fr" href="/fr">`,
+		"/en-gb":  `<link rel="alternate" hreflang="en-GB" href="/en-gb"><link rel="alternate" hreflang="de" href="/de">`,
+		"/de":     `<link rel="alternate" hreflang="en" href="/en"><link rel="alternate" hreflang="en-gb" href="/en-gb"><link rel="alternate" hreflang="de" href="/de">`,
+		"/fr":     `<title>no alternates</title>`,
+		"/dup":    `<link rel="canonical" href="/moved"><link rel="canonical" href="/dup">`,
+		"/gone":   `<link rel="canonical" href="/missing">`,
+		"/chain":  `<link rel="canonical" href="/other">`,
+		"/other":  `<link rel="canonical" href="/else">`,
+		"/target": `<link rel="canonical" href="/target">`,
+		"/alias":  `<link rel="canonical" href="/target">`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/moved" {
+			http.Redirect(w, r, "/dup", http.StatusMovedPermanently)
+			return
+		}
+		if r.URL.Path == "/de" {
+			deFetches.Add(1)
+		}
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, "<html><head>%s</head><body>hi</body></html>", page)
+	}))
+	defer server.Close()
+	u := func(path string) string { return server.URL + path }
+	checker := NewChecker(1, time.Second, 0, server.Client(), WithCanonicalChecks(false))
+	paths := []string{"/en", "/en-gb", "/dup", "/gone", "/chain", "/alias"}
+	var urls []string
+	for _, p := range paths {
+		urls = append(urls, u(p))
+	}
+	results, err := checker.Check(context.Background(), urls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{
+		"/en":    {"hreflang fr " + u("/fr") + " has no return link"},
+		"/en-gb": nil,
+		"/dup":   {"multiple canonical links: " + u("/moved") + ", " + u("/dup"), "canonical " + u("/moved") + " redirects to " + u("/dup")},
+		"/gone":  {"canonical " + u("/missing") + " returned 404"},
+		"/chain": {"canonical " + u("/other") + " declares canonical " + u("/else")},
+		"/alias": nil,
+	}
+	for i, r := range results {
+		if !r.OK {
+			t.Fatalf("warn mode should not fail %s: %+v", paths[i], r)
+		}
+		if !slices.Equal(r.LinkFindings, want[paths[i]]) {
+			t.Fatalf("%s: got %q\nwant %q", paths[i], r.LinkFindings, want[paths[i]])
+		}
+	}
+	if n := deFetches.Load(); n != 1 {
+		t.Fatalf("expected the shared hreflang target to be fetched once, got %d", n)
+	}
+	if _, err := checker.Check(context.Background(), urls); err != nil {
+		t.Fatal(err)
+	}
+	if n := deFetches.Load(); n != 2 {
+		t.Fatalf("linked pages must be refetched on the next run (watch mode), got %d fetches", n)
+	}
+	results, err = NewChecker(1, time.Second, 0, server.Client(), WithCanonicalChecks(true)).Check(context.Background(), []string{u("/gone"), u("/alias")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := results[0]; r.OK || r.ErrorKind != KindCanonical || r.Error != "link tags: canonical "+u("/missing")+" returned 404" {
+		t.Fatalf("fail mode should fail the page: %+v", r)
+	}
+	if !results[1].OK {
+		t.Fatalf("consistent page should pass: %+v", results[1])
+	}
+}
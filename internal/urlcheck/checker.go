@@ -3,29 +3,188 @@ package urlcheck
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"log/slog"
 	"net"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/crypto/ssh"
 )
 
+type ErrorKind string
+
+const (
+	KindForbiddenContent ErrorKind = "forbidden_content"
+	KindDirectoryListing ErrorKind = "directory_listing"
+	KindDefaultPage      ErrorKind = "default_page"
+	KindInvalidRequest   ErrorKind = "invalid_request"
+	KindConnection       ErrorKind = "connection"
+	KindTimeout          ErrorKind = "timeout"
+	KindNXDomain         ErrorKind = "dns_nxdomain"
+	KindDNSFailure       ErrorKind = "dns_failure"
+	KindTruncatedBody    ErrorKind = "truncated_body"
+	KindMissingTrailer   ErrorKind = "missing_trailer"
+	KindNotAttempted     ErrorKind = "not_attempted"
+	KindSlow             ErrorKind = "slow"
+	KindAssertion        ErrorKind = "assertion"
+	KindValidation       ErrorKind = "validation"
+	KindRender           ErrorKind = "render"
+	KindSecurityHeaders  ErrorKind = "security_headers"
+	KindMixedContent     ErrorKind = "mixed_content"
+	KindRedirectLoop     ErrorKind = "redirect_loop"
+	KindTooManyRedirects ErrorKind = "too_many_redirects"
+	KindCanonical        ErrorKind = "canonical"
+	KindParked           ErrorKind = "parked_domain"
+	KindGRPCHealth       ErrorKind = "grpc_health"
+	KindGraphQL          ErrorKind = "graphql_errors"
+	KindAuth             ErrorKind = "auth_failed"
+	KindNotFound         ErrorKind = "not_found"
+	KindEncoding         ErrorKind = "content_encoding"
+	KindWarning          ErrorKind = "warning"
+)
+
+type Location struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
 type Result struct {
-	URL      string `json:"url"`
-	OK       bool   `json:"ok"`
-	Status   int    `json:"status"`
-	Error    string `json:"error,omitempty"`
-	Attempts int    `json:"attempts"`
+	URL              string            `json:"url"`
+	OK               bool              `json:"ok"`
+	Status           int               `json:"status"`
+	Error            string            `json:"error,omitempty"`
+	ErrorKind        ErrorKind         `json:"error_kind,omitempty"`
+	Attempts         int               `json:"attempts"`
+	Duration         time.Duration     `json:"duration"`
+	Source           *Location         `json:"source,omitempty"`
+	Origin           string            `json:"origin,omitempty"`
+	RequestedURL     string            `json:"requested_url,omitempty"`
+	DisplayURL       string            `json:"display_url,omitempty"`
+	Normalization    []string          `json:"normalization,omitempty"`
+	VhostProbe       string            `json:"vhost_probe,omitempty"`
+	DNSVerified      string            `json:"dns_verified,omitempty"`
+	Reverify         string            `json:"reverify,omitempty"`
+	FirstError       string            `json:"first_error,omitempty"`
+	FinalURL         string            `json:"final_url,omitempty"`
+	SkipReason       string            `json:"skip_reason,omitempty"`
+	ExpectContinue   string            `json:"expect_continue,omitempty"`
+	Fingerprint      *Fingerprint      `json:"fingerprint,omitempty"`
+	Slow             bool              `json:"slow,omitempty"`
+	FailedAssertions []string          `json:"failed_assertions,omitempty"`
+	BaselineStatus   int               `json:"baseline_status,omitempty"`
+	Change           string            `json:"change,omitempty"`
+	Labels           map[string]string `json:"labels,omitempty"`
+	Render           *RenderReport     `json:"render,omitempty"`
+	SecurityFindings []string          `json:"security_findings,omitempty"`
+	MixedContent     []string          `json:"mixed_content,omitempty"`
+	RedirectChain    []string          `json:"redirect_chain,omitempty"`
+	LinkFindings     []string          `json:"link_findings,omitempty"`
+	ArchiveURL       string            `json:"archive_url,omitempty"`
+	MovedTo          string            `json:"moved_to,omitempty"`
+	Curl             string            `json:"curl,omitempty"`
+	DNSRecords       []string          `json:"dns_records,omitempty"`
+	Remote           string            `json:"remote,omitempty"`
+	AddrFamily       string            `json:"addr_family,omitempty"`
+	TLSVersion       string            `json:"tls_version,omitempty"`
+	GRPCStatus       string            `json:"grpc_status,omitempty"`
+	Cached           bool              `json:"cached,omitempty"`
+	ContentEncoding  string            `json:"content_encoding,omitempty"`
+	CompressedSize   int64             `json:"compressed_size,omitempty"`
+	DecodedSize      int64             `json:"decoded_size,omitempty"`
+	HTTPSUpgrade     string            `json:"https_upgrade,omitempty"`
+	Severity         Severity          `json:"severity,omitempty"`
+	Warnings         []string          `json:"warnings,omitempty"`
 }
 
 type Checker struct {
-	client      *http.Client
-	concurrency int
-	timeout     time.Duration
-	retries     int
+	client        *http.Client
+	concurrency   int
+	timeout       time.Duration
+	retries       int
+	contentRules  []ContentRule
+	dedupe        bool
+	misconfig     bool
+	method        string
+	body          []byte
+	expect        bool
+	onResult      func(Result)
+	onRetry       func(url string, attempt int, err error)
+	budget        time.Duration
+	vhostAudit    bool
+	nxLookup      func(context.Context, string) ([]string, error)
+	nxResolver    string
+	fingerprint   bool
+	asnLookup     func(context.Context, string) (string, error)
+	asnCache      *sync.Map
+	pollInterval  time.Duration
+	maxLatency    time.Duration
+	slowFails     bool
+	assertions    []Assertion
+	tracer        trace.Tracer
+	logger        *slog.Logger
+	hostOverrides []HostOverride
+	urlOverrides  map[string]HostOverride
+	tlsClients    *sync.Map
+	tlsRouter     *tlsRouter
+	stop          <-chan struct{}
+	grace         time.Duration
+	maxDuration   time.Duration
+	pacer         *hostPacer
+	requestHooks  []func(*http.Request)
+	responseHooks []func(*http.Response, error)
+	validator     Validator
+	pause         *pauseGate
+	renderer      Renderer
+	securityAudit bool
+	securityFails bool
+	mixedContent  bool
+	mixedFails    bool
+	maxRedirects  int
+	canonical     bool
+	canonFails    bool
+	parked        bool
+	archive       string
+	archivePacer  *hostPacer
+	curl          bool
+	checkType     CheckType
+	resolver      *net.Resolver
+	mxLookup      func(context.Context, string) ([]*net.MX, error)
+	fileURLs      bool
+	bucketAuth    bool
+	buckets       *bucketStore
+	encodings     []string
+	httpsUpgrade  bool
+	certWarn      time.Duration
+	warnErrors    bool
+	retryBudget   *atomic.Int64
+	counters      *runCounters
+	dialer        *net.Dialer
+	priority      func(string) int
+	graphQuery    string
+	sshKey        ssh.Signer
+	hostKeys      ssh.HostKeyCallback
+}
+
+type Option func(*Checker)
+
+func WithOnResult(fn func(Result)) Option {
+	return func(c *Checker) {
+		c.onResult = fn
+	}
 }
 
-func NewChecker(concurrency int, timeout time.Duration, retries int, client *http.Client) *Checker {
+func WithOnRetry(fn func(url string, attempt int, err error)) Option {
+	return func(c *Checker) {
+		c.onRetry = fn
+	}
+}
+
+func NewChecker(concurrency int, timeout time.Duration, retries int, client *http.Client, opts ...Option) *Checker {
 	if concurrency < 1 {
 		concurrency = 1
 	}
@@ -38,12 +197,26 @@ func NewChecker(concurrency int, timeout time.Duration, retries int, client *htt
 	if client == nil {
 		client = &http.Client{}
 	}
-	return &Checker{
-		client:      client,
-		concurrency: concurrency,
-		timeout:     timeout,
-		retries:     retries,
+	c := &Checker{
+		client:       client,
+		concurrency:  concurrency,
+		timeout:      timeout,
+		retries:      retries,
+		method:       http.MethodGet,
+		pause:        &pauseGate{},
+		maxRedirects: maxRedirectHops,
+		buckets:      &bucketStore{},
+		counters:     &runCounters{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.client.CheckRedirect == nil {
+		client := *c.client
+		client.CheckRedirect = c.checkRedirect
+		c.client = &client
 	}
+	return c
 }
 
 func (c *Checker) Check(ctx context.Context, urls []string) ([]Result, error) {
@@ -51,33 +224,118 @@ func (c *Checker) Check(ctx context.Context, urls []string) ([]Result, error) {
 		ctx = context.Background()
 	}
 	results := make([]Result, len(urls))
+	order := c.dispatchOrder(urls)
+	next := 0
+	dispatched, _, reason := c.dispatch(ctx, func() (string, bool) {
+		if next >= len(urls) {
+			return "", false
+		}
+		next++
+		return urls[order[next-1]], true
+	}, func(seq int, res Result) {
+		results[order[seq]] = res
+	})
+	if reason != "" {
+		for _, idx := range order[dispatched:] {
+			results[idx] = notAttempted(urls[idx], reason)
+		}
+	}
+	if err := ctx.Err(); err != nil && !errors.Is(err, context.Canceled) {
+		return results, err
+	}
+	return results, nil
+}
+
+func (c *Checker) dispatch(ctx context.Context, next func() (string, bool), emit func(seq int, res Result)) (dispatched, pulled int, reason string) {
 	type job struct {
-		idx int
+		seq int
 		url string
 	}
 	type workerResult struct {
-		idx int
+		seq int
 		res Result
 	}
+	run := &runState{}
+	if c.dedupe {
+		run.hops = newHopCache()
+	}
+	workCtx, cancelWork := context.WithCancelCause(ctx)
+	defer cancelWork(nil)
+	if c.maxDuration > 0 {
+		deadline := time.AfterFunc(c.maxDuration, func() { cancelWork(errDeadline) })
+		defer deadline.Stop()
+	}
+	interrupted := false
+	interrupt := func() {
+		interrupted = true
+		time.AfterFunc(c.grace, func() { cancelWork(errInterrupted) })
+	}
 	jobs := make(chan job)
-	out := make(chan workerResult, len(urls))
+	out := make(chan workerResult, c.concurrency)
 	var wg sync.WaitGroup
 	for i := 0; i < c.concurrency; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 			for j := range jobs {
-				out <- workerResult{idx: j.idx, res: c.checkOne(ctx, j.url)}
+				c.counters.inFlight.Add(1)
+				res := c.runJob(ctx, workCtx, j.url, run)
+				c.counters.inFlight.Add(-1)
+				c.counters.completed.Add(1)
+				out <- workerResult{seq: j.seq, res: res}
 			}
 		}()
 	}
+	var budget <-chan time.Time
+	if c.budget > 0 {
+		timer := time.NewTimer(c.budget)
+		defer timer.Stop()
+		budget = timer.C
+	}
 	go func() {
 		defer close(jobs)
-		for idx, url := range urls {
+		for {
 			select {
-			case <-ctx.Done():
+			case <-budget:
+				return
+			case <-c.stop:
+				interrupt()
 				return
-			case jobs <- job{idx: idx, url: url}:
+			default:
+			}
+			for resumed := c.waitResumed(); resumed != nil; resumed = c.waitResumed() {
+				select {
+				case <-resumed:
+				case <-workCtx.Done():
+					return
+				case <-budget:
+					return
+				case <-c.stop:
+					interrupt()
+					return
+				}
+			}
+			url, ok := next()
+			if !ok {
+				select {
+				case <-c.stop:
+					interrupt()
+				default:
+				}
+				return
+			}
+			pulled++
+			select {
+			case <-workCtx.Done():
+				return
+			case <-budget:
+				return
+			case <-c.stop:
+				interrupt()
+				return
+			case jobs <- job{seq: dispatched, url: url}:
+				dispatched++
+				c.counters.dispatched.Add(1)
 			}
 		}
 	}()
@@ -86,61 +344,228 @@ func (c *Checker) Check(ctx context.Context, urls []string) ([]Result, error) {
 		close(out)
 	}()
 	for r := range out {
-		results[r.idx] = r.res
+		if c.onResult != nil {
+			c.onResult(r.res)
+		}
+		emit(r.seq, r.res)
 	}
-	if err := ctx.Err(); err != nil && !errors.Is(err, context.Canceled) {
-		return results, err
+	switch {
+	case interrupted:
+		reason = "not attempted (interrupted)"
+	case errors.Is(context.Cause(workCtx), errDeadline):
+		reason = "not checked (deadline)"
+	case c.budget > 0:
+		reason = "not attempted (budget exhausted)"
 	}
-	return results, nil
+	return dispatched, pulled, reason
+}
+
+func notAttempted(url, reason string) Result {
+	return Result{URL: url, SkipReason: reason, ErrorKind: KindNotAttempted}
+}
+
+// runState holds what is shared between the jobs of a single Check call.
+type runState struct {
+	hops    *hopCache
+	archive sync.Map
+	pages   sync.Map
+	parked  sync.Map
+}
+
+type runKey struct{}
+
+func (c *Checker) runJob(ctx, workCtx context.Context, url string, run *runState) Result {
+	start := time.Now()
+	jobCtx, span := c.startSpan(workCtx, url)
+	jobCtx = context.WithValue(jobCtx, runKey{}, run)
+	requested, notes, err := NormalizeURL(url)
+	if err != nil {
+		requested, notes = url, []string{"not normalized: " + err.Error()}
+	}
+	var paced atomic.Int64
+	res := c.checkTarget(withPacedTime(jobCtx, &paced), requested, run.hops)
+	// Politeness waits and the follow-up probes below (nxdomain, https
+	// upgrade, archive lookups) are not part of the url's own latency.
+	res.Duration = time.Since(start) - time.Duration(paced.Load())
+	res.URL = url
+	res.RequestedURL = requested
+	res.Normalization = notes
+	if display := DisplayURL(requested); display != requested {
+		res.DisplayURL = display
+	}
+	res = c.verifyNXDomain(jobCtx, requested, res)
+	res = c.checkHTTPSUpgrade(jobCtx, requested, res)
+	res = c.suggestArchive(jobCtx, requested, res, run)
+	res = c.assertLatency(requested, res)
+	res = c.checkLatency(res)
+	res = c.checkRender(jobCtx, requested, res)
+	res = c.grade(res)
+	res = c.attachCurl(requested, res)
+	if workCtx.Err() != nil && ctx.Err() == nil && !res.OK && res.Status == 0 {
+		res = notAttempted(url, "not completed ("+context.Cause(workCtx).Error()+")")
+	}
+	endSpan(span, res)
+	return res
 }
 
 func (c *Checker) checkOne(ctx context.Context, target string) Result {
+	res, _ := c.fetch(ctx, c.client, target)
+	return res
+}
+
+func (c *Checker) fetch(ctx context.Context, client *http.Client, target string) (Result, string) {
+	override := c.hostOverride(target)
+	client = c.clientFor(override, client)
+	retries := c.retriesFor(override)
 	attempts := 0
 	var lastErr error
-	for attempts <= c.retries {
+	for attempts <= retries {
+		if err := c.pace(ctx, target); err != nil {
+			lastErr = err
+			break
+		}
 		attempts++
-		reqCtx, cancel := context.WithTimeout(ctx, c.timeout)
-		req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, target, nil)
+		reqCtx, cancel := context.WithTimeout(ctx, c.timeoutFor(override))
+		var continued *bool
+		if c.expect {
+			continued = new(bool)
+			reqCtx = withContinueTrace(reqCtx, continued)
+		}
+		var remote string
+		reqCtx = withConnTrace(reqCtx, &remote)
+		req, err := c.newRequest(reqCtx, target, override)
 		if err != nil {
 			cancel()
-			lastErr = err
+			lastErr = &requestError{err: err}
 			break
 		}
-		resp, err := c.client.Do(req)
+		resp, err := c.do(client, req)
 		if err != nil {
 			cancel()
 			lastErr = err
-			if c.shouldRetry(err) && attempts <= c.retries {
+			retry := c.shouldRetry(err) && attempts <= retries && c.spendRetry()
+			c.debug(ctx, "attempt failed", "url", target, "attempt", attempts, "error", err, "retry", retry)
+			if retry {
+				if c.onRetry != nil {
+					c.onRetry(target, attempts, err)
+				}
+				retryEvent(ctx, attempts, err)
 				continue
 			}
 			break
 		}
-		_, _ = io.Copy(io.Discard, resp.Body)
+		encoded := c.decodeBody(resp)
+		var body []byte
+		var readErr error
+		if c.needsBody() {
+			body, readErr = io.ReadAll(io.LimitReader(resp.Body, maxInspectBytes))
+		}
+		if readErr == nil {
+			_, readErr = io.Copy(io.Discard, resp.Body)
+		}
 		resp.Body.Close()
 		cancel()
-		ok := resp.StatusCode >= 200 && resp.StatusCode < 400
-		return Result{
+		c.debug(ctx, "attempt completed", "url", target, "attempt", attempts, "status", resp.StatusCode)
+		ok, reason := c.validateResponse(resp, body)
+		statusOK, failedAssertions := c.assertResponse(target, resp, body)
+		// A status assertion replaces the default 2xx/3xx rule, but a custom
+		// validator's verdict stands: assertions can only add failures to it.
+		if statusOK != nil && c.validator == nil {
+			ok = *statusOK
+		} else if statusOK != nil {
+			ok = ok && *statusOK
+		}
+		res := Result{
 			URL:      target,
 			OK:       ok,
 			Status:   resp.StatusCode,
 			Attempts: attempts,
 		}
+		res.MovedTo = permanentTarget(resp)
+		res.AddrFamily = addrFamily(remote)
+		if continued != nil {
+			res.ExpectContinue = continueOutcome(*continued)
+		}
+		if !ok && reason != "" {
+			res.Error = reason
+			res.ErrorKind = KindValidation
+		}
+		if kind, reason := bodyIntegrity(resp, readErr); kind != "" {
+			res.OK = false
+			res.Error = reason
+			res.ErrorKind = kind
+		} else if needle, found := c.forbiddenMatch(target, body); found {
+			res.OK = false
+			res.Error = fmt.Sprintf("forbidden content %q", needle)
+			res.ErrorKind = KindForbiddenContent
+		} else if kind, reason := c.detectMisconfig(resp.StatusCode, body); kind != "" {
+			res.OK = false
+			res.Error = reason
+			res.ErrorKind = kind
+		} else if reason := c.detectParked(ctx, resp, body); reason != "" {
+			res.OK = false
+			res.Error = reason
+			res.ErrorKind = KindParked
+		}
+		if len(failedAssertions) > 0 {
+			res.FailedAssertions = failedAssertions
+			if res.Error == "" {
+				res.OK = false
+				res.Error = "assertion failed: " + failedAssertions[0]
+				res.ErrorKind = KindAssertion
+			}
+		}
+		res = c.checkEncoding(resp, encoded, res)
+		if warning := c.certWarning(resp); warning != "" {
+			res.Warnings = append(res.Warnings, warning)
+		}
+		res = c.auditSecurity(target, resp, res)
+		res = c.detectMixedContent(resp, body, res)
+		res = c.checkCanonical(ctx, resp, body, res)
+		if c.fingerprint {
+			res.Fingerprint = c.fingerprintResponse(ctx, resp, remote)
+		}
+		if c.vhostAudit && res.OK {
+			res.VhostProbe = c.probeVhost(ctx, client, target, body)
+		}
+		return res, resp.Header.Get("Location")
 	}
 	errText := ""
+	var kind ErrorKind
+	var chain []string
 	if lastErr != nil {
 		errText = lastErr.Error()
+		kind = classifyError(lastErr)
 	}
-	return Result{
-		URL:      target,
-		OK:       false,
-		Status:   0,
-		Error:    errText,
-		Attempts: attempts,
+	var redirErr *redirectError
+	if errors.As(lastErr, &redirErr) {
+		errText, chain = redirErr.Error(), redirErr.chain
 	}
+	if c.validator != nil && lastErr != nil {
+		if ok, reason := c.validator(nil, lastErr); ok {
+			return Result{URL: target, OK: true, Attempts: attempts}, ""
+		} else if reason != "" {
+			errText, kind = reason, KindValidation
+		}
+	}
+	return Result{
+		URL:           target,
+		OK:            false,
+		Status:        0,
+		Error:         errText,
+		ErrorKind:     kind,
+		Attempts:      attempts,
+		RedirectChain: chain,
+	}, ""
 }
 
 func (c *Checker) shouldRetry(err error) bool {
-	if errors.Is(err, context.DeadlineExceeded) {
+	var redirErr *redirectError
+	if errors.Is(err, context.DeadlineExceeded) || errors.As(err, &redirErr) {
+		return false
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
 		return false
 	}
 	var netErr net.Error
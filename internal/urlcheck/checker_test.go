@@ -150,3 +150,38 @@ func TestContextCancellation(t *testing.T) {
 		t.Fatalf("expected deadline exceeded, got %v", err)
 	}
 }
+
+func TestOnResultCalledPerURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	var seen []string
+	checker := NewChecker(2, time.Second, 0, server.Client(), WithOnResult(func(r Result) {
+		seen = append(seen, r.URL)
+	}))
+	urls := []string{server.URL + "/a", server.URL + "/b", server.URL + "/c"}
+	if _, err := checker.Check(context.Background(), urls); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(seen) != len(urls) {
+		t.Fatalf("expected %d callbacks, got %d", len(urls), len(seen))
+	}
+}
+
+func TestOnRetryReportsAttempts(t *testing.T) {
+	client := &http.Client{Transport: &transientRoundTripper{}}
+	var retried []int
+	checker := NewChecker(1, time.Second, 2, client, WithOnRetry(func(url string, attempt int, err error) {
+		if url != "http://example.com" || err == nil {
+			t.Errorf("unexpected retry callback: %q %v", url, err)
+		}
+		retried = append(retried, attempt)
+	}))
+	if _, err := checker.Check(context.Background(), []string{"http://example.com"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(retried) != 1 || retried[0] != 1 {
+		t.Fatalf("expected one retry after attempt 1, got %v", retried)
+	}
+}
@@ -0,0 +1,190 @@
+package urlcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type CheckType string
+
+const (
+	CheckHTTP       CheckType = "http"
+	CheckDNS        CheckType = "dns"
+	CheckTCP        CheckType = "tcp"
+	CheckTLS        CheckType = "tls"
+	CheckGRPCHealth CheckType = "grpc-health"
+	CheckGraphQL    CheckType = "graphql"
+)
+
+func WithCheckType(kind CheckType) Option {
+	return func(c *Checker) {
+		c.checkType = kind
+	}
+}
+
+func (c *Checker) checkTarget(ctx context.Context, target string, hops *hopCache) Result {
+	if obj, ok := c.bucketTarget(target); ok {
+		return c.checkBucket(ctx, target, obj)
+	}
+	switch {
+	case isGRPCURL(target):
+		return c.checkGRPCHealth(ctx, target)
+	case isFileURL(target):
+		return c.checkFile(target)
+	case isMailto(target):
+		return c.checkMailto(ctx, target)
+	case strings.HasPrefix(target, "ftp://"):
+		return c.checkFTP(ctx, target)
+	case strings.HasPrefix(target, "sftp://"):
+		return c.checkSFTP(ctx, target)
+	}
+	switch c.checkType {
+	case CheckDNS:
+		return c.checkDNS(ctx, target)
+	case CheckTCP, CheckTLS:
+		return c.checkConnect(ctx, target, c.checkType == CheckTLS)
+	case CheckGRPCHealth:
+		return c.checkGRPCHealth(ctx, target)
+	case CheckGraphQL:
+		return c.checkGraphQL(ctx, target)
+	}
+	if hops != nil {
+		return c.checkDeduped(ctx, target, hops)
+	}
+	return c.checkOne(ctx, target)
+}
+
+func (c *Checker) checkDNS(ctx context.Context, target string) Result {
+	res := Result{URL: target}
+	u, err := url.Parse(target)
+	if err != nil || u.Hostname() == "" {
+		res.Error = "no hostname to resolve"
+		res.ErrorKind = KindInvalidRequest
+		return res
+	}
+	override := c.hostOverride(target)
+	retries := c.retriesFor(override)
+	for {
+		res.Attempts++
+		lookupCtx, cancel := context.WithTimeout(ctx, c.timeoutFor(override))
+		records, err := c.lookupRecords(lookupCtx, u.Hostname())
+		cancel()
+		if err == nil {
+			res.OK = true
+			res.DNSRecords = records
+			return res
+		}
+		res.Error = err.Error()
+		res.ErrorKind = classifyError(err)
+		var dnsErr *net.DNSError
+		if res.Attempts > retries || (errors.As(err, &dnsErr) && dnsErr.IsNotFound) || ctx.Err() != nil || !c.spendRetry() {
+			return res
+		}
+	}
+}
+
+func (c *Checker) lookupRecords(ctx context.Context, host string) ([]string, error) {
+	resolver := c.resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	var records []string
+	if net.ParseIP(host) == nil {
+		cname, err := resolver.LookupCNAME(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		if cname = strings.TrimSuffix(cname, "."); !strings.EqualFold(cname, host) {
+			records = append(records, "CNAME "+cname)
+		}
+	}
+	addrs, err := resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	for _, addr := range addrs {
+		if addr.IP.To4() != nil {
+			records = append(records, "A "+addr.IP.String())
+		} else {
+			records = append(records, "AAAA "+addr.IP.String())
+		}
+	}
+	return records, nil
+}
+
+func (c *Checker) checkConnect(ctx context.Context, target string, useTLS bool) Result {
+	res := Result{URL: target}
+	host, addr, err := dialAddress(target, useTLS)
+	if err != nil {
+		res.Error = err.Error()
+		res.ErrorKind = KindInvalidRequest
+		return res
+	}
+	override := c.hostOverride(target)
+	retries := c.retriesFor(override)
+	for {
+		res.Attempts++
+		dialCtx, cancel := context.WithTimeout(ctx, c.timeoutFor(override))
+		conn, err := c.dial(dialCtx, host, addr, useTLS)
+		cancel()
+		if err == nil {
+			res.OK = true
+			res.Remote = conn.RemoteAddr().String()
+			res.AddrFamily = addrFamily(res.Remote)
+			if tlsConn, ok := conn.(*tls.Conn); ok {
+				res.TLSVersion = tls.VersionName(tlsConn.ConnectionState().Version)
+			}
+			conn.Close()
+			return res
+		}
+		res.Error = err.Error()
+		res.ErrorKind = classifyError(err)
+		if res.Attempts > retries || !c.shouldRetry(err) || ctx.Err() != nil || !c.spendRetry() {
+			return res
+		}
+	}
+}
+
+func (c *Checker) dial(ctx context.Context, host, addr string, useTLS bool) (net.Conn, error) {
+	if !useTLS {
+		return c.netDialer().DialContext(ctx, "tcp", addr)
+	}
+	d := tls.Dialer{NetDialer: c.netDialer(), Config: c.tlsConfig(host)}
+	return d.DialContext(ctx, "tcp", addr)
+}
+
+func (c *Checker) tlsConfig(host string) *tls.Config {
+	config := &tls.Config{}
+	if t, ok := c.client.Transport.(*http.Transport); ok && t.TLSClientConfig != nil {
+		config = t.TLSClientConfig.Clone()
+	}
+	if config.ServerName == "" {
+		config.ServerName = host
+	}
+	return config
+}
+
+func dialAddress(target string, useTLS bool) (host, addr string, err error) {
+	u, err := url.Parse(target)
+	if err != nil || u.Hostname() == "" {
+		return "", "", errors.New("no host to connect to")
+	}
+	port := u.Port()
+	if port == "" {
+		switch {
+		case u.Scheme == "http":
+			port = "80"
+		case u.Scheme == "https" || (u.Scheme == "tls" && useTLS):
+			port = "443"
+		default:
+			return "", "", fmt.Errorf("no port in %q", target)
+		}
+	}
+	return u.Hostname(), net.JoinHostPort(u.Hostname(), port), nil
+}
@@ -0,0 +1,134 @@
+package urlcheck
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCheckDNSResolvesWithoutHTTP(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+	}))
+	defer server.Close()
+	checker := NewChecker(2, time.Second, 0, nil, WithCheckType(CheckDNS))
+	results, err := checker.Check(context.Background(), []string{server.URL + "/path", "http://[::1]:1/", "not a url"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r := results[0]; !r.OK || r.Status != 0 || !slices.Equal(r.DNSRecords, []string{"A 127.0.0.1"}) || r.Attempts != 1 {
+		t.Fatalf("unexpected ipv4 result %+v", r)
+	}
+	if r := results[1]; !r.OK || !slices.Equal(r.DNSRecords, []string{"AAAA ::1"}) {
+		t.Fatalf("unexpected ipv6 result %+v", r)
+	}
+	if r := results[2]; r.OK || r.ErrorKind != KindInvalidRequest {
+		t.Fatalf("unexpected result for an invalid url %+v", r)
+	}
+	if n := atomic.LoadInt32(&hits); n != 0 {
+		t.Fatalf("dns check made %d http requests", n)
+	}
+}
+
+func TestCheckDNSFailuresAndRetries(t *testing.T) {
+	var dials int32
+	checker := NewChecker(1, time.Second, 2, nil, WithCheckType(CheckDNS))
+	checker.resolver = &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			atomic.AddInt32(&dials, 1)
+			return nil, errors.New("resolver unreachable")
+		},
+	}
+	results, err := checker.Check(context.Background(), []string{"https://gone.example.test/"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r := results[0]
+	if r.OK || r.ErrorKind != KindDNSFailure || r.Attempts != 3 || len(r.DNSRecords) != 0 {
+		t.Fatalf("unexpected result %+v", r)
+	}
+	if atomic.LoadInt32(&dials) == 0 {
+		t.Fatal("custom resolver was not used")
+	}
+}
+
+func TestCheckTCPConnectsWithoutHTTP(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+	}))
+	defer server.Close()
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedAddr := closed.Addr().String()
+	closed.Close()
+	addr := strings.TrimPrefix(server.URL, "http://")
+	checker := NewChecker(2, time.Second, 1, nil, WithCheckType(CheckTCP))
+	results, err := checker.Check(context.Background(), []string{"tcp://" + addr, "tcp://" + closedAddr, "tcp://db.example"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r := results[0]; !r.OK || r.Remote != addr || r.TLSVersion != "" {
+		t.Fatalf("unexpected result %+v", r)
+	}
+	if r := results[1]; r.OK || r.ErrorKind != KindConnection || r.Attempts != 2 {
+		t.Fatalf("unexpected result for a closed port %+v", r)
+	}
+	if r := results[2]; r.OK || r.ErrorKind != KindInvalidRequest || !strings.Contains(r.Error, "no port") {
+		t.Fatalf("unexpected result without a port %+v", r)
+	}
+	if n := atomic.LoadInt32(&hits); n != 0 {
+		t.Fatalf("tcp check made %d http requests", n)
+	}
+}
+
+func TestCheckTLSHandshake(t *testing.T) {
+	secure := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer secure.Close()
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer plain.Close()
+	checker := NewChecker(2, time.Second, 0, secure.Client(), WithCheckType(CheckTLS))
+	results, err := checker.Check(context.Background(), []string{secure.URL, "tls://" + strings.TrimPrefix(plain.URL, "http://")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r := results[0]; !r.OK || !strings.HasPrefix(r.TLSVersion, "TLS 1.") {
+		t.Fatalf("unexpected tls result %+v", r)
+	}
+	if r := results[1]; r.OK || r.Error == "" {
+		t.Fatalf("expected a handshake failure against a plain server, got %+v", r)
+	}
+	untrusted := NewChecker(1, time.Second, 0, nil, WithCheckType(CheckTLS))
+	results, _ = untrusted.Check(context.Background(), []string{secure.URL})
+	if r := results[0]; r.OK || !strings.Contains(r.Error, "certificate") {
+		t.Fatalf("expected an untrusted certificate failure, got %+v", r)
+	}
+}
+
+func TestDialAddressDefaults(t *testing.T) {
+	for target, want := range map[string]string{
+		"http://a.example":     "a.example:80",
+		"https://a.example":    "a.example:443",
+		"tls://a.example":      "a.example:443",
+		"tcp://a.example:5432": "a.example:5432",
+		"https://[::1]:8443/x": "[::1]:8443",
+	} {
+		if _, got, err := dialAddress(target, true); err != nil || got != want {
+			t.Fatalf("dialAddress(%q) = %q, %v; want %q", target, got, err, want)
+		}
+	}
+	if _, _, err := dialAddress("tls://a.example", false); err == nil {
+		t.Fatal("expected tls:// without a port to need one for plain tcp")
+	}
+}
@@ -0,0 +1,135 @@
+package urlcheck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+type Pair struct {
+	Candidate string `json:"candidate"`
+	Reference string `json:"reference"`
+}
+
+type Comparison struct {
+	Pair
+	CandidateStatus int      `json:"candidate_status"`
+	ReferenceStatus int      `json:"reference_status"`
+	HeaderDiffs     []string `json:"header_diffs,omitempty"`
+	Similarity      float64  `json:"similarity"`
+	Drift           []string `json:"drift,omitempty"`
+	Error           string   `json:"error,omitempty"`
+}
+
+type snapshot struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+func (c *Checker) Compare(ctx context.Context, pairs []Pair, headers []string, minSimilarity float64) []Comparison {
+	out := make([]Comparison, len(pairs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < c.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				out[idx] = c.comparePair(ctx, pairs[idx], headers, minSimilarity)
+			}
+		}()
+	}
+	for idx := range pairs {
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
+	return out
+}
+
+func (c *Checker) comparePair(ctx context.Context, pair Pair, headers []string, minSimilarity float64) Comparison {
+	cmp := Comparison{Pair: pair}
+	cand, err := c.snapshot(ctx, pair.Candidate)
+	if err != nil {
+		cmp.Error = "candidate: " + err.Error()
+		cmp.Drift = append(cmp.Drift, "candidate unreachable")
+		return cmp
+	}
+	ref, err := c.snapshot(ctx, pair.Reference)
+	if err != nil {
+		cmp.Error = "reference: " + err.Error()
+		cmp.Drift = append(cmp.Drift, "reference unreachable")
+		return cmp
+	}
+	cmp.CandidateStatus = cand.status
+	cmp.ReferenceStatus = ref.status
+	if cand.status != ref.status {
+		cmp.Drift = append(cmp.Drift, fmt.Sprintf("status %d != %d", cand.status, ref.status))
+	}
+	for _, name := range headers {
+		cv, rv := cand.header.Get(name), ref.header.Get(name)
+		if cv != rv {
+			cmp.HeaderDiffs = append(cmp.HeaderDiffs, fmt.Sprintf("%s: %q != %q", http.CanonicalHeaderKey(name), cv, rv))
+		}
+	}
+	if len(cmp.HeaderDiffs) > 0 {
+		cmp.Drift = append(cmp.Drift, fmt.Sprintf("%d header(s) differ", len(cmp.HeaderDiffs)))
+	}
+	cmp.Similarity = similarity(string(cand.body), string(ref.body))
+	if cmp.Similarity < minSimilarity {
+		cmp.Drift = append(cmp.Drift, fmt.Sprintf("body similarity %.2f < %.2f", cmp.Similarity, minSimilarity))
+	}
+	return cmp
+}
+
+func (c *Checker) snapshot(ctx context.Context, target string) (snapshot, error) {
+	override := c.hostOverride(target)
+	reqCtx, cancel := context.WithTimeout(ctx, c.timeoutFor(override))
+	defer cancel()
+	req, err := c.newRequest(reqCtx, target, override)
+	if err != nil {
+		return snapshot{}, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return snapshot{}, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxInspectBytes))
+	if err != nil {
+		return snapshot{}, err
+	}
+	return snapshot{status: resp.StatusCode, header: resp.Header, body: body}, nil
+}
+
+func similarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	wa, wb := wordSet(a), wordSet(b)
+	union := len(wa)
+	shared := 0
+	for w := range wb {
+		if wa[w] {
+			shared++
+		} else {
+			union++
+		}
+	}
+	if union == 0 {
+		return 1
+	}
+	return float64(shared) / float64(union)
+}
+
+func wordSet(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(s) {
+		set[w] = true
+	}
+	return set
+}
@@ -0,0 +1,54 @@
+package urlcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompareReportsDrift(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/blue", "/green":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("hello from the shop front page"))
+		case "/new":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"missing"}`))
+		}
+	}))
+	defer server.Close()
+	checker := NewChecker(2, time.Second, 0, server.Client())
+	pairs := []Pair{
+		{Candidate: server.URL + "/green", Reference: server.URL + "/blue"},
+		{Candidate: server.URL + "/new", Reference: server.URL + "/blue"},
+		{Candidate: "http://127.0.0.1:1/", Reference: server.URL + "/blue"},
+	}
+	got := checker.Compare(context.Background(), pairs, []string{"content-type"}, 0.9)
+	if len(got[0].Drift) != 0 || got[0].Similarity != 1 {
+		t.Fatalf("expected identical pair, got %+v", got[0])
+	}
+	drift := strings.Join(got[1].Drift, "; ")
+	if !strings.Contains(drift, "status 404 != 200") || !strings.Contains(drift, "1 header(s) differ") || !strings.Contains(drift, "body similarity") {
+		t.Fatalf("unexpected drift: %+v", got[1])
+	}
+	if got[1].HeaderDiffs[0] != `Content-Type: "application/json" != "text/html"` {
+		t.Fatalf("unexpected header diff: %v", got[1].HeaderDiffs)
+	}
+	if got[2].Error == "" || got[2].Drift[0] != "candidate unreachable" {
+		t.Fatalf("expected unreachable candidate, got %+v", got[2])
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	if s := similarity("a b c d", "a b c e"); s != 0.6 {
+		t.Fatalf("unexpected similarity %v", s)
+	}
+	if s := similarity("", ""); s != 1 {
+		t.Fatalf("empty bodies should be identical, got %v", s)
+	}
+}
@@ -0,0 +1,58 @@
+package urlcheck
+
+import (
+	"bytes"
+	"regexp"
+)
+
+const maxInspectBytes = 1 << 20
+
+type ContentRule struct {
+	Pattern   *regexp.Regexp
+	Forbidden []string
+}
+
+func WithForbiddenContent(needles ...string) Option {
+	return func(c *Checker) {
+		c.contentRules = append(c.contentRules, ContentRule{Forbidden: needles})
+	}
+}
+
+func WithContentRules(rules ...ContentRule) Option {
+	return func(c *Checker) {
+		c.contentRules = append(c.contentRules, rules...)
+	}
+}
+
+func (c *Checker) needsBody() bool {
+	if len(c.contentRules) > 0 || c.misconfig || c.vhostAudit || c.validator != nil || c.mixedContent || c.canonical || c.parked {
+		return true
+	}
+	for _, a := range c.assertions {
+		if a.needsBody() {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Checker) forbiddenMatch(target string, body []byte) (string, bool) {
+	if len(body) == 0 {
+		return "", false
+	}
+	lower := bytes.ToLower(body)
+	for _, rule := range c.contentRules {
+		if rule.Pattern != nil && !rule.Pattern.MatchString(target) {
+			continue
+		}
+		for _, needle := range rule.Forbidden {
+			if needle == "" {
+				continue
+			}
+			if bytes.Contains(lower, bytes.ToLower([]byte(needle))) {
+				return needle, true
+			}
+		}
+	}
+	return "", false
+}
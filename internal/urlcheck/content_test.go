@@ -0,0 +1,52 @@
+package urlcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestForbiddenContentFailsOK(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/leak":
+			w.Write([]byte("<h1>Fatal Error</h1><pre>Stack trace: ...</pre>"))
+		default:
+			w.Write([]byte("hello"))
+		}
+	}))
+	defer server.Close()
+	checker := NewChecker(1, time.Second, 0, server.Client(), WithForbiddenContent("stack trace"))
+	results, err := checker.Check(context.Background(), []string{server.URL + "/leak", server.URL + "/fine"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[0].OK || results[0].ErrorKind != KindForbiddenContent || results[0].Status != http.StatusOK {
+		t.Fatalf("expected forbidden content failure, got %+v", results[0])
+	}
+	if !results[1].OK {
+		t.Fatalf("expected clean page to pass, got %+v", results[1])
+	}
+}
+
+func TestContentRulePattern(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<title>Index of /</title>"))
+	}))
+	defer server.Close()
+	rule := ContentRule{Pattern: regexp.MustCompile(`/files/`), Forbidden: []string{"index of /"}}
+	checker := NewChecker(1, time.Second, 0, server.Client(), WithContentRules(rule))
+	results, err := checker.Check(context.Background(), []string{server.URL + "/files/", server.URL + "/other"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[0].OK {
+		t.Fatalf("expected matching pattern to fail, got %+v", results[0])
+	}
+	if !results[1].OK {
+		t.Fatalf("expected non-matching pattern to pass, got %+v", results[1])
+	}
+}
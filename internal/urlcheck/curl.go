@@ -0,0 +1,113 @@
+package urlcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxInlineBody = 4096
+
+func WithCurlCommands() Option {
+	return func(c *Checker) {
+		c.curl = true
+	}
+}
+
+func (c *Checker) attachCurl(target string, res Result) Result {
+	if !c.curl || res.OK || res.SkipReason != "" {
+		return res
+	}
+	cmd, err := c.curlCommand(target)
+	if err == nil {
+		res.Curl = cmd
+	}
+	return res
+}
+
+func (c *Checker) curlCommand(target string) (string, error) {
+	override := c.hostOverride(target)
+	req, err := c.newRequest(context.Background(), target, override)
+	if err != nil {
+		return "", err
+	}
+	args := []string{"curl", "-sSv", "-o", "/dev/null"}
+	switch req.Method {
+	case http.MethodGet:
+	case http.MethodHead:
+		args = append(args, "--head")
+	default:
+		args = append(args, "-X", req.Method)
+	}
+	args = append(args, "-L", "--max-redirs", fmt.Sprint(c.maxRedirects), "--max-time", fmt.Sprint(c.timeoutFor(override).Seconds()))
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, v := range req.Header[name] {
+			if sensitiveHeader(name) {
+				v = "[redacted]"
+			}
+			args = append(args, "-H", shellQuote(name+": "+v))
+		}
+	}
+	if req.Header.Get("User-Agent") == "" {
+		args = append(args, "-H", shellQuote("User-Agent: Go-http-client/1.1"))
+	}
+	if proxy := c.proxyFor(req); proxy != nil {
+		args = append(args, "--proxy", shellQuote(proxy.Redacted()))
+	}
+	if c.body != nil {
+		if len(c.body) <= maxInlineBody && utf8.Valid(c.body) {
+			args = append(args, "--data-binary", shellQuote(string(c.body)))
+		} else {
+			args = append(args, "--data-binary", fmt.Sprintf("@body-%d-bytes", len(c.body)))
+		}
+	}
+	args = append(args, shellQuote(target))
+	return strings.Join(args, " "), nil
+}
+
+// sensitiveHeader reports headers whose values are credentials. The curl
+// command ends up in reports and notifications, so like the proxy password
+// their values are left out.
+func sensitiveHeader(name string) bool {
+	switch name = strings.ToLower(name); name {
+	case "authorization", "proxy-authorization", "cookie":
+		return true
+	}
+	for _, word := range []string{"token", "secret", "password", "api-key", "apikey", "auth"} {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Checker) proxyFor(req *http.Request) *url.URL {
+	proxy := http.ProxyFromEnvironment
+	if t, ok := c.client.Transport.(*http.Transport); ok {
+		proxy = t.Proxy
+	}
+	if proxy == nil {
+		return nil
+	}
+	u, err := proxy(req)
+	if err != nil {
+		return nil
+	}
+	return u
+}
+
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:=@,+%") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,45 @@
+package urlcheck
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+type DialerConfig struct {
+	Timeout       time.Duration
+	KeepAlive     time.Duration
+	FallbackDelay time.Duration
+}
+
+func WithDialer(d DialerConfig) Option {
+	return func(c *Checker) {
+		c.dialer = &net.Dialer{Timeout: d.Timeout, KeepAlive: d.KeepAlive, FallbackDelay: d.FallbackDelay}
+		c.tuneTransport(func(t *http.Transport) {
+			t.DialContext = c.dialer.DialContext
+		})
+	}
+}
+
+func (c *Checker) netDialer() *net.Dialer {
+	if c.dialer != nil {
+		return c.dialer
+	}
+	return &net.Dialer{}
+}
+
+func addrFamily(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	ip := net.ParseIP(host)
+	switch {
+	case ip == nil:
+		return ""
+	case ip.To4() != nil:
+		return "ipv4"
+	default:
+		return "ipv6"
+	}
+}
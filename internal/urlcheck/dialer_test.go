@@ -0,0 +1,67 @@
+package urlcheck
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAddrFamily(t *testing.T) {
+	for addr, want := range map[string]string{
+		"127.0.0.1:443":       "ipv4",
+		"[::1]:443":           "ipv6",
+		"[::ffff:1.2.3.4]:80": "ipv4",
+		"10.0.0.1":            "ipv4",
+		"example.com:80":      "",
+		"":                    "",
+	} {
+		if got := addrFamily(addr); got != want {
+			t.Errorf("addrFamily(%q) = %q, want %q", addr, got, want)
+		}
+	}
+}
+
+func TestWithDialerTunesTransport(t *testing.T) {
+	c := NewChecker(1, time.Second, 0, nil, WithDialer(DialerConfig{Timeout: 2 * time.Second, KeepAlive: -1, FallbackDelay: 50 * time.Millisecond}))
+	tr, ok := c.client.Transport.(*http.Transport)
+	if !ok || tr.DialContext == nil {
+		t.Fatalf("expected a dialer on the transport, got %T", c.client.Transport)
+	}
+	if d := c.netDialer(); d.Timeout != 2*time.Second || d.KeepAlive != -1 || d.FallbackDelay != 50*time.Millisecond {
+		t.Fatalf("unexpected dialer %+v", d)
+	}
+}
+
+func TestAddrFamilyRecorded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	results, err := NewChecker(1, time.Second, 0, nil, WithDialer(DialerConfig{Timeout: time.Second})).Check(context.Background(), []string{server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := results[0]; !r.OK || r.AddrFamily != "ipv4" {
+		t.Fatalf("unexpected result %+v", r)
+	}
+	lis, err := net.Listen("tcp6", "[::1]:0")
+	if err != nil {
+		t.Skip("ipv6 loopback unavailable")
+	}
+	defer lis.Close()
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	results, _ = NewChecker(1, time.Second, 0, nil, WithCheckType(CheckTCP)).Check(context.Background(), []string{"tcp://" + lis.Addr().String()})
+	if r := results[0]; !r.OK || r.AddrFamily != "ipv6" || !strings.HasPrefix(r.Remote, "[::1]") {
+		t.Fatalf("unexpected result %+v", r)
+	}
+}
@@ -0,0 +1,57 @@
+package urlcheck
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+)
+
+const (
+	DNSConfirmed = "confirmed"
+	DNSRefuted   = "refuted"
+)
+
+func WithNXDomainVerifier(resolverAddr string) Option {
+	return func(c *Checker) {
+		resolver := &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				d := net.Dialer{Timeout: c.timeout}
+				return d.DialContext(ctx, network, resolverAddr)
+			},
+		}
+		c.nxResolver = resolverAddr
+		c.nxLookup = resolver.LookupHost
+	}
+}
+
+func (c *Checker) verifyNXDomain(ctx context.Context, target string, res Result) Result {
+	if c.nxLookup == nil || res.ErrorKind != KindNXDomain {
+		return res
+	}
+	u, err := url.Parse(target)
+	if err != nil || u.Hostname() == "" {
+		return res
+	}
+	lookupCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	addrs, err := c.nxLookup(lookupCtx, u.Hostname())
+	var dnsErr *net.DNSError
+	switch {
+	case err == nil && len(addrs) > 0:
+		res.DNSVerified = DNSRefuted
+		res.ErrorKind = KindDNSFailure
+		res.Error += " (resolved by " + c.resolverName() + ", likely a resolver problem)"
+	case errors.As(err, &dnsErr) && dnsErr.IsNotFound:
+		res.DNSVerified = DNSConfirmed
+	}
+	return res
+}
+
+func (c *Checker) resolverName() string {
+	if c.nxResolver == "" {
+		return "second resolver"
+	}
+	return c.nxResolver
+}
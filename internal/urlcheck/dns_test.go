@@ -0,0 +1,71 @@
+package urlcheck
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type nxRoundTripper struct {
+	calls int32
+}
+
+func (n *nxRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&n.calls, 1)
+	return nil, &net.DNSError{Err: "no such host", Name: req.URL.Hostname(), IsNotFound: true}
+}
+
+func TestNXDomainIsNotRetried(t *testing.T) {
+	rt := &nxRoundTripper{}
+	checker := NewChecker(1, time.Second, 3, &http.Client{Transport: rt})
+	results, err := checker.Check(context.Background(), []string{"https://gone.example"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[0].Attempts != 1 || atomic.LoadInt32(&rt.calls) != 1 {
+		t.Fatalf("expected a single attempt, got %+v", results[0])
+	}
+	if results[0].ErrorKind != KindNXDomain {
+		t.Fatalf("expected nxdomain kind, got %+v", results[0])
+	}
+}
+
+func TestNXDomainVerification(t *testing.T) {
+	cases := []struct {
+		name     string
+		lookup   func(context.Context, string) ([]string, error)
+		verified string
+		kind     ErrorKind
+	}{
+		{
+			name: "confirmed",
+			lookup: func(context.Context, string) ([]string, error) {
+				return nil, &net.DNSError{Err: "no such host", IsNotFound: true}
+			},
+			verified: DNSConfirmed,
+			kind:     KindNXDomain,
+		},
+		{
+			name: "refuted",
+			lookup: func(context.Context, string) ([]string, error) {
+				return []string{"192.0.2.1"}, nil
+			},
+			verified: DNSRefuted,
+			kind:     KindDNSFailure,
+		},
+	}
+	for _, tc := range cases {
+		checker := NewChecker(1, time.Second, 0, &http.Client{Transport: &nxRoundTripper{}})
+		checker.nxLookup = tc.lookup
+		results, err := checker.Check(context.Background(), []string{"https://gone.example"})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if results[0].DNSVerified != tc.verified || results[0].ErrorKind != tc.kind {
+			t.Fatalf("%s: unexpected result %+v", tc.name, results[0])
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package urlcheck
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+func WithAcceptEncoding(encodings ...string) Option {
+	return func(c *Checker) {
+		for _, e := range encodings {
+			c.encodings = append(c.encodings, strings.ToLower(strings.TrimSpace(e)))
+		}
+	}
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type encodedBody struct {
+	io.Reader
+	io.Closer
+	raw     *countingReader
+	decoded *countingReader
+}
+
+func (c *Checker) decodeBody(resp *http.Response) *encodedBody {
+	if len(c.encodings) == 0 {
+		return nil
+	}
+	body := &encodedBody{Closer: resp.Body, raw: &countingReader{r: resp.Body}}
+	var decoded io.Reader = body.raw
+	switch responseEncoding(resp) {
+	case "gzip", "x-gzip":
+		if zr, err := gzip.NewReader(body.raw); err == nil {
+			decoded = zr
+		} else {
+			decoded = errReader{err}
+		}
+	case "deflate":
+		br := bufio.NewReader(body.raw)
+		if head, err := br.Peek(2); err == nil && (uint16(head[0])<<8|uint16(head[1]))%31 == 0 {
+			zr, err := zlib.NewReader(br)
+			if err != nil {
+				zr = io.NopCloser(errReader{err})
+			}
+			decoded = zr
+		} else {
+			decoded = flate.NewReader(br)
+		}
+	case "br":
+		decoded = brotli.NewReader(body.raw)
+	}
+	body.decoded = &countingReader{r: decoded}
+	body.Reader = body.decoded
+	resp.Body = body
+	return body
+}
+
+type errReader struct{ err error }
+
+func (e errReader) Read([]byte) (int, error) { return 0, e.err }
+
+func responseEncoding(resp *http.Response) string {
+	enc := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if enc == "" {
+		return "identity"
+	}
+	return enc
+}
+
+func (c *Checker) checkEncoding(resp *http.Response, body *encodedBody, res Result) Result {
+	if body == nil {
+		return res
+	}
+	actual := responseEncoding(resp)
+	res.ContentEncoding = actual
+	res.CompressedSize = body.raw.n
+	res.DecodedSize = body.decoded.n
+	if !res.OK || slices.Contains(c.encodings, actual) || (actual == "identity" && body.raw.n == 0) {
+		return res
+	}
+	res.OK = false
+	res.Error = "server ignored Accept-Encoding: asked for " + strings.Join(c.encodings, ", ") + ", got " + actual
+	res.ErrorKind = KindEncoding
+	return res
+}
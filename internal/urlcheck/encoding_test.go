@@ -0,0 +1,74 @@
+package urlcheck
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+)
+
+func TestAcceptEncoding(t *testing.T) {
+	page := strings.Repeat("hello encoding ", 200)
+	var gz, br, zl bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	gw.Write([]byte(page))
+	gw.Close()
+	bw := brotli.NewWriter(&br)
+	bw.Write([]byte(page))
+	bw.Close()
+	zw := zlib.NewWriter(&zl)
+	zw.Write([]byte(page))
+	zw.Close()
+	var seen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Get("Accept-Encoding"))
+		accept := r.Header.Get("Accept-Encoding")
+		switch {
+		case r.URL.Path == "/plain":
+			w.Write([]byte(page))
+		case strings.Contains(accept, "br"):
+			w.Header().Set("Content-Encoding", "br")
+			w.Write(br.Bytes())
+		case strings.Contains(accept, "deflate"):
+			w.Header().Set("Content-Encoding", "deflate")
+			w.Write(zl.Bytes())
+		case strings.Contains(accept, "gzip"):
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Write(gz.Bytes())
+		default:
+			w.Write([]byte(page))
+		}
+	}))
+	defer server.Close()
+	check := func(path string, encodings ...string) Result {
+		checker := NewChecker(1, time.Second, 0, nil, WithAcceptEncoding(encodings...), WithForbiddenContent("goodbye"))
+		results, err := checker.Check(context.Background(), []string{server.URL + path})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return results[0]
+	}
+	for _, tc := range []struct {
+		enc        string
+		compressed int
+	}{{"gzip", gz.Len()}, {"br", br.Len()}, {"deflate", zl.Len()}, {"identity", len(page)}} {
+		r := check("/", tc.enc)
+		if !r.OK || r.ContentEncoding != tc.enc || r.CompressedSize != int64(tc.compressed) || r.DecodedSize != int64(len(page)) {
+			t.Fatalf("%s: unexpected result %+v", tc.enc, r)
+		}
+	}
+	r := check("/plain", "gzip", "br")
+	if r.OK || r.ErrorKind != KindEncoding || r.Error != "server ignored Accept-Encoding: asked for gzip, br, got identity" {
+		t.Fatalf("unexpected result for an ignored encoding %+v", r)
+	}
+	if seen[len(seen)-1] != "gzip, br" {
+		t.Fatalf("unexpected Accept-Encoding header %q", seen[len(seen)-1])
+	}
+}
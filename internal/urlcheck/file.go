@@ -0,0 +1,62 @@
+package urlcheck
+
+import (
+	"errors"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func WithFileURLs() Option {
+	return func(c *Checker) {
+		c.fileURLs = true
+	}
+}
+
+func isFileURL(target string) bool {
+	return len(target) > 7 && strings.EqualFold(target[:7], "file://")
+}
+
+func (c *Checker) checkFile(target string) Result {
+	if !c.fileURLs {
+		return Result{URL: target, SkipReason: "file:// checks are disabled"}
+	}
+	res := Result{URL: target, Attempts: 1}
+	u, err := url.Parse(target)
+	if err != nil || (u.Host != "" && u.Host != "localhost") || u.Path == "" {
+		res.Error = "file url needs a local absolute path (file:///path)"
+		res.ErrorKind = KindInvalidRequest
+		return res
+	}
+	path := localPath(u)
+	f, err := os.Open(path)
+	if err == nil {
+		_, err = f.Stat()
+		f.Close()
+	}
+	switch {
+	case err == nil:
+		res.OK = true
+	case errors.Is(err, fs.ErrNotExist):
+		res.Error = path + ": no such file or directory"
+		res.ErrorKind = KindNotFound
+	case errors.Is(err, fs.ErrPermission):
+		res.Error = path + ": permission denied"
+		res.ErrorKind = KindAuth
+	default:
+		res.Error = err.Error()
+	}
+	return res
+}
+
+// localPath turns a file url's path into an OS path, dropping the slash in
+// front of a windows drive letter (file:///C:/docs -> C:\docs).
+func localPath(u *url.URL) string {
+	path := filepath.FromSlash(u.Path)
+	if trimmed := filepath.FromSlash(strings.TrimPrefix(u.Path, "/")); filepath.VolumeName(trimmed) != "" {
+		path = trimmed
+	}
+	return path
+}
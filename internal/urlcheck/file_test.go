@@ -0,0 +1,43 @@
+package urlcheck
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCheckFile(t *testing.T) {
+	dir := t.TempDir()
+	doc := filepath.Join(dir, "guide.md")
+	if err := os.WriteFile(doc, []byte("# guide"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	targets := []string{
+		"file://" + doc,
+		"file://localhost" + dir,
+		"file://" + filepath.Join(dir, "missing.md"),
+		"file://fileserver/share/doc.md",
+	}
+	results, err := NewChecker(2, time.Second, 0, nil, WithFileURLs()).Check(context.Background(), targets)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if r := results[i]; !r.OK || r.Attempts != 1 {
+			t.Fatalf("unexpected result %+v", r)
+		}
+	}
+	if r := results[2]; r.OK || r.ErrorKind != KindNotFound {
+		t.Fatalf("unexpected missing file result %+v", r)
+	}
+	if r := results[3]; r.OK || r.ErrorKind != KindInvalidRequest {
+		t.Fatalf("unexpected remote host result %+v", r)
+	}
+
+	results, _ = NewChecker(1, time.Second, 0, nil).Check(context.Background(), targets[:1])
+	if r := results[0]; r.OK || r.SkipReason == "" || r.Error != "" {
+		t.Fatalf("expected file urls to be opt-in, got %+v", r)
+	}
+}
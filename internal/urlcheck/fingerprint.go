@@ -0,0 +1,204 @@
+package urlcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+)
+
+type Fingerprint struct {
+	Provider   string   `json:"provider,omitempty"`
+	Evidence   []string `json:"evidence,omitempty"`
+	CertIssuer string   `json:"cert_issuer,omitempty"`
+	IP         string   `json:"ip,omitempty"`
+	ASN        string   `json:"asn,omitempty"`
+}
+
+type ProviderStats struct {
+	Total  int `json:"total"`
+	Failed int `json:"failed"`
+}
+
+var headerProviders = []struct {
+	provider string
+	header   string
+	contains string
+}{
+	{"GitHub Pages", "Server", "GitHub.com"},
+	{"Cloudflare", "Cf-Ray", ""},
+	{"Cloudflare", "Server", "cloudflare"},
+	{"CloudFront", "X-Amz-Cf-Id", ""},
+	{"S3", "Server", "AmazonS3"},
+	{"Fastly", "Fastly-Debug-Digest", ""},
+	{"Fastly", "X-Served-By", "cache-"},
+	{"Akamai", "Server", "AkamaiGHost"},
+	{"Vercel", "X-Vercel-Id", ""},
+	{"Netlify", "X-Nf-Request-Id", ""},
+	{"Google", "Server", "Google Frontend"},
+	{"Google", "Server", "gws"},
+}
+
+var asnProviders = map[string]string{
+	"AS13335":  "Cloudflare",
+	"AS54113":  "Fastly",
+	"AS16509":  "AWS",
+	"AS14618":  "AWS",
+	"AS36459":  "GitHub",
+	"AS15169":  "Google",
+	"AS396982": "Google",
+	"AS8075":   "Microsoft",
+	"AS20940":  "Akamai",
+	"AS16625":  "Akamai",
+}
+
+func WithFingerprinting() Option {
+	return func(c *Checker) {
+		c.fingerprint = true
+		c.asnCache = &sync.Map{}
+		if c.asnLookup == nil {
+			c.asnLookup = cymruASN
+		}
+	}
+}
+
+func withConnTrace(ctx context.Context, remote *string) context.Context {
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Conn != nil {
+				*remote = info.Conn.RemoteAddr().String()
+			}
+		},
+	})
+}
+
+func (c *Checker) fingerprintResponse(ctx context.Context, resp *http.Response, remote string) *Fingerprint {
+	fp := &Fingerprint{}
+	for _, rule := range headerProviders {
+		v := resp.Header.Get(rule.header)
+		if v == "" || !strings.Contains(v, rule.contains) {
+			continue
+		}
+		if fp.Provider == "" {
+			fp.Provider = rule.provider
+		}
+		if fp.Provider == rule.provider {
+			fp.Evidence = append(fp.Evidence, "header "+rule.header+": "+v)
+		}
+	}
+	fp.CertIssuer = certIssuer(resp.TLS)
+	if fp.Provider == "" && strings.Contains(fp.CertIssuer, "Cloudflare") {
+		fp.Provider = "Cloudflare"
+		fp.Evidence = append(fp.Evidence, "cert issuer: "+fp.CertIssuer)
+	}
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		fp.IP = host
+	}
+	if fp.IP != "" && c.asnLookup != nil {
+		fp.ASN = c.lookupASN(ctx, fp.IP)
+		if provider, ok := asnProviders[fp.ASN]; ok && fp.Provider == "" {
+			fp.Provider = provider
+			fp.Evidence = append(fp.Evidence, "asn: "+fp.ASN)
+		}
+	}
+	return fp
+}
+
+func certIssuer(state *tls.ConnectionState) string {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return ""
+	}
+	issuer := state.PeerCertificates[0].Issuer
+	if len(issuer.Organization) > 0 {
+		return issuer.Organization[0]
+	}
+	return issuer.CommonName
+}
+
+func (c *Checker) lookupASN(ctx context.Context, ip string) string {
+	if c.asnCache != nil {
+		if asn, ok := c.asnCache.Load(ip); ok {
+			return asn.(string)
+		}
+	}
+	lookupCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	asn, err := c.asnLookup(lookupCtx, ip)
+	if err != nil {
+		return ""
+	}
+	if c.asnCache != nil {
+		c.asnCache.Store(ip, asn)
+	}
+	return asn
+}
+
+func cymruASN(ctx context.Context, ip string) (string, error) {
+	name, err := cymruName(ip)
+	if err != nil {
+		return "", err
+	}
+	records, err := net.DefaultResolver.LookupTXT(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	for _, record := range records {
+		if asn := parseCymru(record); asn != "" {
+			return asn, nil
+		}
+	}
+	return "", fmt.Errorf("no asn record for %s", ip)
+}
+
+func cymruName(ip string) (string, error) {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return "", fmt.Errorf("invalid ip %q", ip)
+	}
+	if v4 := addr.To4(); v4 != nil {
+		return fmt.Sprintf("%d.%d.%d.%d.origin.asn.cymru.com", v4[3], v4[2], v4[1], v4[0]), nil
+	}
+	const hex = "0123456789abcdef"
+	var b strings.Builder
+	for i := len(addr) - 1; i >= 0; i-- {
+		b.WriteByte(hex[addr[i]&0x0f])
+		b.WriteByte('.')
+		b.WriteByte(hex[addr[i]>>4])
+		b.WriteByte('.')
+	}
+	b.WriteString("origin6.asn.cymru.com")
+	return b.String(), nil
+}
+
+func parseCymru(record string) string {
+	first, _, _ := strings.Cut(record, "|")
+	fields := strings.Fields(first)
+	if len(fields) == 0 {
+		return ""
+	}
+	return "AS" + fields[0]
+}
+
+func ProviderRollup(results []Result) map[string]ProviderStats {
+	rollup := make(map[string]ProviderStats)
+	for _, r := range results {
+		if r.Fingerprint == nil {
+			continue
+		}
+		provider := r.Fingerprint.Provider
+		if provider == "" {
+			provider = "unknown"
+		}
+		stats := rollup[provider]
+		stats.Total++
+		if !r.OK {
+			stats.Failed++
+		}
+		rollup[provider] = stats
+	}
+	return rollup
+}
@@ -0,0 +1,62 @@
+package urlcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFingerprintIdentifiesProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/pages" {
+			w.Header().Set("Server", "GitHub.com")
+			w.Header().Set("X-Served-By", "cache-fra-1234")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	lookups := 0
+	checker := NewChecker(1, time.Second, 0, server.Client(), WithFingerprinting())
+	checker.asnLookup = func(_ context.Context, ip string) (string, error) {
+		lookups++
+		return "AS13335", nil
+	}
+	results, err := checker.Check(context.Background(), []string{server.URL + "/pages", server.URL + "/plain"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pages := results[0].Fingerprint
+	if pages == nil || pages.Provider != "GitHub Pages" || pages.IP != "127.0.0.1" || pages.ASN != "AS13335" {
+		t.Fatalf("unexpected fingerprint: %+v", pages)
+	}
+	if len(pages.Evidence) != 1 {
+		t.Fatalf("expected only evidence for the chosen provider, got %v", pages.Evidence)
+	}
+	plain := results[1].Fingerprint
+	if plain == nil || plain.Provider != "Cloudflare" || plain.Evidence[0] != "asn: AS13335" {
+		t.Fatalf("expected asn fallback, got %+v", plain)
+	}
+	if lookups != 1 {
+		t.Fatalf("expected cached asn lookups, got %d", lookups)
+	}
+	rollup := ProviderRollup(results)
+	if rollup["GitHub Pages"].Total != 1 || rollup["Cloudflare"].Total != 1 {
+		t.Fatalf("unexpected rollup: %+v", rollup)
+	}
+}
+
+func TestCymruName(t *testing.T) {
+	name, err := cymruName("104.16.1.2")
+	if err != nil || name != "2.1.16.104.origin.asn.cymru.com" {
+		t.Fatalf("unexpected name %q (%v)", name, err)
+	}
+	name, err = cymruName("2001:db8::1")
+	if err != nil || name[:8] != "1.0.0.0." || name[len(name)-22:] != ".origin6.asn.cymru.com" {
+		t.Fatalf("unexpected name %q (%v)", name, err)
+	}
+	if asn := parseCymru("13335 | 104.16.0.0/13 | US | arin | 2014-03-28"); asn != "AS13335" {
+		t.Fatalf("unexpected asn %q", asn)
+	}
+}
@@ -0,0 +1,143 @@
+package urlcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/textproto"
+	"net/url"
+	"strings"
+)
+
+func (c *Checker) checkFTP(ctx context.Context, target string) Result {
+	res := Result{URL: target}
+	u, err := url.Parse(target)
+	if err != nil || u.Hostname() == "" {
+		res.Error = "no host to connect to"
+		res.ErrorKind = KindInvalidRequest
+		return res
+	}
+	if err := ftpSafeURL(u); err != nil {
+		res.Error = err.Error()
+		res.ErrorKind = KindInvalidRequest
+		return res
+	}
+	override := c.hostOverride(target)
+	retries := c.retriesFor(override)
+	for {
+		res.Attempts++
+		ftpCtx, cancel := context.WithTimeout(ctx, c.timeoutFor(override))
+		err := probeFTP(ftpCtx, c.netDialer(), u)
+		cancel()
+		if err == nil {
+			res.OK = true
+			return res
+		}
+		res.Error = err.Error()
+		var ftpErr *remoteError
+		if errors.As(err, &ftpErr) {
+			res.ErrorKind = ftpErr.kind
+			return res
+		}
+		res.ErrorKind = classifyError(err)
+		if res.Attempts > retries || !c.shouldRetry(err) || ctx.Err() != nil || !c.spendRetry() {
+			return res
+		}
+	}
+}
+
+// ftpSafeURL rejects decoded paths and credentials that would end the FTP
+// command line early: "%0D%0A" in a scanned link must not become a second
+// command on the control connection.
+func ftpSafeURL(u *url.URL) error {
+	unsafe := func(v string) bool { return strings.ContainsAny(v, "\r\n\x00") }
+	if unsafe(u.Path) {
+		return fmt.Errorf("ftp path contains a control character")
+	}
+	if u.User != nil {
+		pass, _ := u.User.Password()
+		if unsafe(u.User.Username()) || unsafe(pass) {
+			return fmt.Errorf("ftp credentials contain a control character")
+		}
+	}
+	return nil
+}
+
+type remoteError struct {
+	kind ErrorKind
+	msg  string
+}
+
+func (e *remoteError) Error() string {
+	return e.msg
+}
+
+func probeFTP(ctx context.Context, d *net.Dialer, u *url.URL) error {
+	port := u.Port()
+	if port == "" {
+		port = "21"
+	}
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(u.Hostname(), port))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	tp := textproto.NewConn(conn)
+	if _, _, err := tp.ReadResponse(2); err != nil {
+		return ftpError(err, KindConnection, "greeting")
+	}
+	user, pass := "anonymous", "anonymous@"
+	if u.User != nil {
+		user = u.User.Username()
+		if p, ok := u.User.Password(); ok {
+			pass = p
+		}
+	}
+	code, msg, err := ftpCmd(tp, 0, "USER %s", user)
+	if err == nil && code == 331 {
+		code, msg, err = ftpCmd(tp, 0, "PASS %s", pass)
+	}
+	if err == nil && code/100 != 2 {
+		err = &textproto.Error{Code: code, Msg: msg}
+	}
+	if err != nil {
+		return ftpError(err, KindAuth, "login as "+user)
+	}
+	defer ftpCmd(tp, 0, "QUIT")
+	path := u.Path
+	if path == "" || path == "/" {
+		return nil
+	}
+	if _, _, err := ftpCmd(tp, 2, "TYPE I"); err != nil {
+		return ftpError(err, KindConnection, "TYPE I")
+	}
+	if _, _, err := ftpCmd(tp, 2, "SIZE %s", path); err == nil {
+		return nil
+	}
+	if _, _, err := ftpCmd(tp, 2, "CWD %s", path); err != nil {
+		return ftpError(err, KindNotFound, path)
+	}
+	return nil
+}
+
+func ftpCmd(tp *textproto.Conn, expect int, format string, args ...any) (int, string, error) {
+	id, err := tp.Cmd(format, args...)
+	if err != nil {
+		return 0, "", err
+	}
+	tp.StartResponse(id)
+	defer tp.EndResponse(id)
+	return tp.ReadResponse(expect)
+}
+
+func ftpError(err error, kind ErrorKind, what string) error {
+	var protoErr *textproto.Error
+	if !errors.As(err, &protoErr) {
+		return err
+	}
+	return &remoteError{kind: kind, msg: fmt.Sprintf("ftp %s failed: %d %s", what, protoErr.Code, strings.TrimSpace(protoErr.Msg))}
+}
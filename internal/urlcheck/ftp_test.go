@@ -0,0 +1,145 @@
+package urlcheck
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func fakeFTP(t *testing.T, files, dirs map[string]bool) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { lis.Close() })
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				fmt.Fprint(conn, "220 fake ftp\r\n")
+				user := ""
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					cmd, arg, _ := strings.Cut(strings.TrimSpace(line), " ")
+					switch cmd {
+					case "USER":
+						user = arg
+						fmt.Fprint(conn, "331 password please\r\n")
+					case "PASS":
+						if user == "anonymous" || (user == "docs" && arg == "s3cret") {
+							fmt.Fprint(conn, "230 logged in\r\n")
+						} else {
+							fmt.Fprint(conn, "530 Login incorrect.\r\n")
+						}
+					case "TYPE":
+						fmt.Fprint(conn, "200 binary\r\n")
+					case "SIZE":
+						if files[arg] {
+							fmt.Fprint(conn, "213 42\r\n")
+						} else {
+							fmt.Fprint(conn, "550 not a file\r\n")
+						}
+					case "CWD":
+						if dirs[arg] {
+							fmt.Fprint(conn, "250 ok\r\n")
+						} else {
+							fmt.Fprint(conn, "550 No such file or directory.\r\n")
+						}
+					case "QUIT":
+						fmt.Fprint(conn, "221 bye\r\n")
+						return
+					default:
+						fmt.Fprint(conn, "502 not implemented\r\n")
+					}
+				}
+			}()
+		}
+	}()
+	return lis.Addr().String()
+}
+
+func TestCheckFTP(t *testing.T) {
+	addr := fakeFTP(t, map[string]bool{"/pub/release.tar.gz": true}, map[string]bool{"/pub": true})
+	checker := NewChecker(2, time.Second, 0, nil)
+	results, err := checker.Check(context.Background(), []string{
+		"ftp://" + addr + "/pub/release.tar.gz",
+		"ftp://" + addr + "/pub",
+		"ftp://docs:s3cret@" + addr + "/",
+		"ftp://" + addr + "/pub/gone.zip",
+		"ftp://docs:wrong@" + addr + "/pub",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if r := results[i]; !r.OK || r.Attempts != 1 {
+			t.Fatalf("unexpected result %+v", r)
+		}
+	}
+	if r := results[3]; r.OK || r.ErrorKind != KindNotFound || r.Error != "ftp /pub/gone.zip failed: 550 No such file or directory." {
+		t.Fatalf("unexpected missing file result %+v", r)
+	}
+	if r := results[4]; r.OK || r.ErrorKind != KindAuth || r.Error != "ftp login as docs failed: 530 Login incorrect." {
+		t.Fatalf("unexpected auth result %+v", r)
+	}
+}
+
+func TestCheckFTPConnectionRefused(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := lis.Addr().String()
+	lis.Close()
+	results, _ := NewChecker(1, time.Second, 1, nil).Check(context.Background(), []string{"ftp://" + addr + "/x"})
+	if r := results[0]; r.OK || r.ErrorKind != KindConnection || r.Attempts != 2 {
+		t.Fatalf("unexpected result %+v", r)
+	}
+}
+
+func TestCheckFTPRejectsCommandInjection(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	var dialed atomic.Int32
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			dialed.Add(1)
+			conn.Close()
+		}
+	}()
+	addr := lis.Addr().String()
+	results, _ := NewChecker(1, time.Second, 0, nil).Check(context.Background(), []string{
+		"ftp://" + addr + "/pub%0D%0ADELE%20x",
+		"ftp://us%0Aer:pw@" + addr + "/pub",
+		"ftp://user:p%00w@" + addr + "/pub",
+	})
+	for _, r := range results {
+		if r.OK || r.ErrorKind != KindInvalidRequest || !strings.Contains(r.Error, "a control character") {
+			t.Fatalf("unexpected result %+v", r)
+		}
+	}
+	if n := dialed.Load(); n != 0 {
+		t.Fatalf("expected no connection for unsafe urls, got %d", n)
+	}
+}
@@ -0,0 +1,111 @@
+package urlcheck
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultGraphQLQuery = "query { __typename }"
+	graphQLErrors       = "graphql errors: "
+)
+
+func WithGraphQLQuery(query string) Option {
+	return func(c *Checker) {
+		c.graphQuery = query
+	}
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func (c *Checker) checkGraphQL(ctx context.Context, target string) Result {
+	query := c.graphQuery
+	if query == "" {
+		query = defaultGraphQLQuery
+	}
+	payload, _ := json.Marshal(map[string]string{"query": query})
+	override := c.hostOverride(target)
+	retries := c.retriesFor(override)
+	res := Result{URL: target}
+	for {
+		res.Attempts++
+		reqCtx, cancel := context.WithTimeout(ctx, c.timeoutFor(override))
+		status, body, err := c.postGraphQL(reqCtx, target, override, payload)
+		cancel()
+		if err != nil {
+			res.Error = err.Error()
+			res.ErrorKind = classifyError(err)
+			if res.Attempts > retries || !c.shouldRetry(err) || ctx.Err() != nil || !c.spendRetry() {
+				return res
+			}
+			continue
+		}
+		res.Status = status
+		res.Error, res.ErrorKind = "", ""
+		res.OK = status >= 200 && status < 300
+		problem := graphQLProblem(body)
+		switch {
+		case !res.OK && strings.HasPrefix(problem, graphQLErrors):
+			res.Error = "status " + strconv.Itoa(status) + ", " + problem
+		case !res.OK:
+			res.Error = "status " + strconv.Itoa(status)
+		case problem != "":
+			res.OK = false
+			res.Error = problem
+			res.ErrorKind = KindGraphQL
+		}
+		return res
+	}
+}
+
+func (c *Checker) postGraphQL(ctx context.Context, target string, override *HostOverride, payload []byte) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(payload))
+	if err != nil {
+		return 0, nil, &requestError{err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/graphql-response+json, application/json")
+	if override != nil {
+		for k, v := range override.Headers {
+			req.Header.Set(k, v)
+		}
+	}
+	resp, err := c.do(c.client, req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxInspectBytes))
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, body, nil
+}
+
+func graphQLProblem(body []byte) string {
+	var resp graphQLResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "response is not graphql json"
+	}
+	if len(resp.Errors) > 0 {
+		msgs := make([]string, len(resp.Errors))
+		for i, e := range resp.Errors {
+			msgs[i] = e.Message
+		}
+		return graphQLErrors + strings.Join(msgs, "; ")
+	}
+	if len(resp.Data) == 0 || string(resp.Data) == "null" {
+		return "graphql response has no data"
+	}
+	return ""
+}
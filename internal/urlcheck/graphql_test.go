@@ -0,0 +1,78 @@
+package urlcheck
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCheckGraphQL(t *testing.T) {
+	var lastQuery, lastAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query string `json:"query"`
+		}
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" || json.NewDecoder(r.Body).Decode(&req) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		lastQuery, lastAuth = req.Query, r.Header.Get("Authorization")
+		switch r.URL.Path {
+		case "/ok":
+			w.Write([]byte(`{"data":{"__typename":"Query"}}`))
+		case "/errors":
+			w.Write([]byte(`{"data":null,"errors":[{"message":"not authorized"},{"message":"rate limited"}]}`))
+		case "/html":
+			w.Write([]byte(`<html>maintenance</html>`))
+		case "/down":
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte(`{"errors":[{"message":"upstream"}]}`))
+		}
+	}))
+	defer server.Close()
+	checker := NewChecker(1, time.Second, 0, nil, WithCheckType(CheckGraphQL),
+		WithHostOverrides(HostOverride{URL: server.URL + "/ok", Headers: map[string]string{"Authorization": "Bearer t"}}))
+	results, err := checker.Check(context.Background(), []string{server.URL + "/ok", server.URL + "/errors", server.URL + "/html", server.URL + "/down"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r := results[0]; !r.OK || r.Status != 200 {
+		t.Fatalf("unexpected result %+v", r)
+	}
+	if r := results[1]; r.OK || r.Status != 200 || r.ErrorKind != KindGraphQL || r.Error != "graphql errors: not authorized; rate limited" {
+		t.Fatalf("unexpected errors result %+v", r)
+	}
+	if r := results[2]; r.OK || r.ErrorKind != KindGraphQL || r.Error != "response is not graphql json" {
+		t.Fatalf("unexpected html result %+v", r)
+	}
+	if r := results[3]; r.OK || r.Status != 502 || r.Error != "status 502, graphql errors: upstream" {
+		t.Fatalf("unexpected 502 result %+v", r)
+	}
+	if lastQuery != defaultGraphQLQuery {
+		t.Fatalf("unexpected default query %q", lastQuery)
+	}
+	custom := NewChecker(1, time.Second, 0, nil, WithCheckType(CheckGraphQL), WithGraphQLQuery("{ health }"),
+		WithHostOverrides(HostOverride{URL: server.URL + "/ok", Headers: map[string]string{"Authorization": "Bearer t"}}))
+	results, _ = custom.Check(context.Background(), []string{server.URL + "/ok"})
+	if !results[0].OK || lastQuery != "{ health }" || lastAuth != "Bearer t" {
+		t.Fatalf("custom query or headers not sent: %+v %q %q", results[0], lastQuery, lastAuth)
+	}
+}
+
+func TestGraphQLProblem(t *testing.T) {
+	for body, want := range map[string]string{
+		`{"data":{"a":1}}`:                "",
+		`{"data":{"a":1},"errors":[]}`:    "",
+		`{"data":null}`:                   "graphql response has no data",
+		`{}`:                              "graphql response has no data",
+		`{"errors":[{"message":"boom"}]}`: "graphql errors: boom",
+		`not json`:                        "response is not graphql json",
+	} {
+		if got := graphQLProblem([]byte(body)); got != want {
+			t.Fatalf("graphQLProblem(%s) = %q, want %q", body, got, want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package urlcheck
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func isGRPCURL(target string) bool {
+	return strings.HasPrefix(target, "grpc://") || strings.HasPrefix(target, "grpcs://")
+}
+
+func (c *Checker) checkGRPCHealth(ctx context.Context, target string) Result {
+	res := Result{URL: target}
+	u, err := url.Parse(target)
+	if err != nil || u.Hostname() == "" {
+		res.Error = "no host to connect to"
+		res.ErrorKind = KindInvalidRequest
+		return res
+	}
+	secure := u.Scheme == "grpcs" || u.Scheme == "https"
+	port := u.Port()
+	switch {
+	case port != "":
+	case secure:
+		port = "443"
+	case u.Scheme == "http":
+		port = "80"
+	default:
+		res.Error = fmt.Sprintf("no port in %q", target)
+		res.ErrorKind = KindInvalidRequest
+		return res
+	}
+	creds := insecure.NewCredentials()
+	if secure {
+		creds = credentials.NewTLS(c.tlsConfig(u.Hostname()))
+	}
+	conn, err := grpc.NewClient("passthrough:///"+net.JoinHostPort(u.Hostname(), port), grpc.WithTransportCredentials(creds))
+	if err != nil {
+		res.Error = err.Error()
+		res.ErrorKind = KindInvalidRequest
+		return res
+	}
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+	req := &healthpb.HealthCheckRequest{Service: strings.Trim(u.Path, "/")}
+	override := c.hostOverride(target)
+	retries := c.retriesFor(override)
+	for {
+		res.Attempts++
+		callCtx, cancel := context.WithTimeout(ctx, c.timeoutFor(override))
+		resp, err := client.Check(callCtx, req)
+		cancel()
+		if err == nil {
+			res.GRPCStatus = resp.GetStatus().String()
+			res.OK = resp.GetStatus() == healthpb.HealthCheckResponse_SERVING
+			if !res.OK {
+				res.Error = "health status " + res.GRPCStatus
+				res.ErrorKind = KindGRPCHealth
+			}
+			return res
+		}
+		st := status.Convert(err)
+		res.Error = st.Message()
+		switch st.Code() {
+		case codes.Unavailable:
+			res.ErrorKind = KindConnection
+		case codes.DeadlineExceeded:
+			res.ErrorKind = KindTimeout
+		case codes.Unimplemented:
+			res.Error = "grpc.health.v1.Health not implemented"
+			res.ErrorKind = KindGRPCHealth
+		case codes.NotFound:
+			res.Error = fmt.Sprintf("unknown service %q", req.Service)
+			res.ErrorKind = KindGRPCHealth
+		default:
+			res.Error = st.Code().String() + ": " + st.Message()
+			res.ErrorKind = KindGRPCHealth
+		}
+		if res.Attempts > retries || st.Code() != codes.Unavailable || ctx.Err() != nil || !c.spendRetry() {
+			return res
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package urlcheck
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestCheckGRPCHealth(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	hs := health.NewServer()
+	hs.SetServingStatus("shop.Orders", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(server, hs)
+	go server.Serve(lis)
+	defer server.Stop()
+	bare, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain := grpc.NewServer()
+	go plain.Serve(bare)
+	defer plain.Stop()
+
+	addr := lis.Addr().String()
+	checker := NewChecker(2, 2*time.Second, 0, nil)
+	results, err := checker.Check(context.Background(), []string{
+		"grpc://" + addr,
+		"grpc://" + addr + "/shop.Orders",
+		"grpc://" + addr + "/shop.Missing",
+		"grpc://" + bare.Addr().String(),
+		"grpc://db.example",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r := results[0]; !r.OK || r.GRPCStatus != "SERVING" || r.Attempts != 1 {
+		t.Fatalf("unexpected result %+v", r)
+	}
+	if r := results[1]; r.OK || r.GRPCStatus != "NOT_SERVING" || r.ErrorKind != KindGRPCHealth || r.Error != "health status NOT_SERVING" {
+		t.Fatalf("unexpected not-serving result %+v", r)
+	}
+	if r := results[2]; r.OK || r.ErrorKind != KindGRPCHealth || r.Error != `unknown service "shop.Missing"` {
+		t.Fatalf("unexpected unknown service result %+v", r)
+	}
+	if r := results[3]; r.OK || r.ErrorKind != KindGRPCHealth || r.Error != "grpc.health.v1.Health not implemented" {
+		t.Fatalf("unexpected unimplemented result %+v", r)
+	}
+	if r := results[4]; r.OK || r.ErrorKind != KindInvalidRequest {
+		t.Fatalf("unexpected result without a port %+v", r)
+	}
+}
+
+func TestCheckGRPCHealthFlagAndUnavailable(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := lis.Addr().String()
+	lis.Close()
+	checker := NewChecker(1, time.Second, 1, nil, WithCheckType(CheckGRPCHealth))
+	results, err := checker.Check(context.Background(), []string{"http://" + addr})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r := results[0]; r.OK || r.ErrorKind != KindConnection || r.Attempts != 2 {
+		t.Fatalf("unexpected result %+v", r)
+	}
+}
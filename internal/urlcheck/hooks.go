@@ -0,0 +1,28 @@
+package urlcheck
+
+import "net/http"
+
+func WithRequestHook(fn func(*http.Request)) Option {
+	return func(c *Checker) {
+		c.requestHooks = append(c.requestHooks, fn)
+	}
+}
+
+func WithResponseHook(fn func(*http.Response, error)) Option {
+	return func(c *Checker) {
+		c.responseHooks = append(c.responseHooks, fn)
+	}
+}
+
+func (c *Checker) do(client *http.Client, req *http.Request) (*http.Response, error) {
+	for _, hook := range c.requestHooks {
+		hook(req)
+	}
+	c.counters.requests.Add(1)
+	resp, err := client.Do(req)
+	c.counters.requests.Add(-1)
+	for _, hook := range c.responseHooks {
+		hook(resp, err)
+	}
+	return resp, err
+}
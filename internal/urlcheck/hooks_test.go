@@ -0,0 +1,43 @@
+package urlcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRequestAndResponseHooksRunPerAttempt(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Signature") != "signed" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+	var statuses []int
+	var errs int
+	checker := NewChecker(1, time.Second, 1, server.Client(),
+		WithRequestHook(func(r *http.Request) { r.Header.Set("X-Signature", "signed") }),
+		WithResponseHook(func(resp *http.Response, err error) {
+			if err != nil {
+				errs++
+				return
+			}
+			statuses = append(statuses, resp.StatusCode)
+		}),
+	)
+	results, err := checker.Check(context.Background(), []string{server.URL, "http://127.0.0.1:1/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results[0].OK {
+		t.Fatalf("expected the request hook to sign the request, got %+v", results[0])
+	}
+	if len(statuses) != 1 || statuses[0] != http.StatusOK {
+		t.Fatalf("unexpected statuses seen by the response hook: %v", statuses)
+	}
+	if errs != results[1].Attempts || errs != 2 {
+		t.Fatalf("expected the response hook to see every failed attempt, got %d errors for %d attempts", errs, results[1].Attempts)
+	}
+}
@@ -0,0 +1,181 @@
+package urlcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"maps"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+type HostOverride struct {
+	Host    string
+	URL     string
+	Timeout time.Duration
+	Retries *int
+	Method  string
+	Headers map[string]string
+	TLS     *tls.Config
+}
+
+func WithHostOverrides(overrides ...HostOverride) Option {
+	return func(c *Checker) {
+		for _, o := range overrides {
+			if o.TLS != nil && c.tlsClients == nil {
+				c.tlsClients = &sync.Map{}
+			}
+			if o.URL == "" {
+				c.hostOverrides = append(c.hostOverrides, o)
+				continue
+			}
+			if normalized, _, err := NormalizeURL(o.URL); err == nil {
+				o.URL = normalized
+			}
+			if c.urlOverrides == nil {
+				c.urlOverrides = make(map[string]HostOverride)
+			}
+			if _, dup := c.urlOverrides[o.URL]; !dup {
+				c.urlOverrides[o.URL] = o
+			}
+		}
+	}
+}
+
+func (o HostOverride) matches(host string) bool {
+	if suffix, ok := strings.CutPrefix(o.Host, "*."); ok {
+		return strings.HasSuffix(host, "."+suffix)
+	}
+	return strings.EqualFold(host, o.Host)
+}
+
+func (o HostOverride) over(base *HostOverride) *HostOverride {
+	if base == nil {
+		return &o
+	}
+	merged := *base
+	if o.Timeout > 0 {
+		merged.Timeout = o.Timeout
+	}
+	if o.Retries != nil {
+		merged.Retries = o.Retries
+	}
+	if o.Method != "" {
+		merged.Method = o.Method
+	}
+	if o.TLS != nil {
+		merged.TLS = o.TLS
+	}
+	if len(o.Headers) > 0 {
+		merged.Headers = maps.Clone(base.Headers)
+		if merged.Headers == nil {
+			merged.Headers = map[string]string{}
+		}
+		maps.Copy(merged.Headers, o.Headers)
+	}
+	return &merged
+}
+
+// hostOverride returns the settings for target: the first matching host
+// override, with an override for the exact (normalized) url layered on top.
+func (c *Checker) hostOverride(target string) *HostOverride {
+	if len(c.hostOverrides) == 0 && len(c.urlOverrides) == 0 {
+		return nil
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil
+	}
+	host := strings.ToLower(u.Hostname())
+	var found *HostOverride
+	for i := range c.hostOverrides {
+		if o := &c.hostOverrides[i]; o.matches(host) {
+			found = o
+			break
+		}
+	}
+	if o, ok := c.urlOverrides[target]; ok {
+		return o.over(found)
+	}
+	return found
+}
+
+func (c *Checker) timeoutFor(o *HostOverride) time.Duration {
+	if o != nil && o.Timeout > 0 {
+		return o.Timeout
+	}
+	return c.timeout
+}
+
+func (c *Checker) retriesFor(o *HostOverride) int {
+	if o != nil && o.Retries != nil {
+		return max(*o.Retries, 0)
+	}
+	return c.retries
+}
+
+func (c *Checker) clientFor(o *HostOverride, client *http.Client) *http.Client {
+	if o == nil || o.TLS == nil {
+		return client
+	}
+	tuned := *client
+	switch t := client.Transport.(type) {
+	case nil:
+		tuned.Transport = c.tlsTransport(http.DefaultTransport.(*http.Transport), o.TLS)
+	case *http.Transport:
+		tuned.Transport = c.tlsTransport(t, o.TLS)
+	default:
+		if c.tlsRouter == nil {
+			return client
+		}
+		// WithTransport wrapped the bare transport; tag the request so the
+		// router underneath the wrappers picks the override's TLS config.
+		tuned.Transport = tlsTagger{next: t, config: o.TLS}
+	}
+	return &tuned
+}
+
+func (c *Checker) tlsTransport(base *http.Transport, config *tls.Config) http.RoundTripper {
+	if rt, ok := c.tlsClients.Load(config); ok {
+		return rt.(http.RoundTripper)
+	}
+	t := base.Clone()
+	t.TLSClientConfig = config
+	rt, _ := c.tlsClients.LoadOrStore(config, t)
+	return rt.(http.RoundTripper)
+}
+
+type tlsConfigKey struct{}
+
+// tlsRouter is installed beneath WithTransport wrappers so host override TLS
+// settings still apply when the client transport is no longer a bare
+// *http.Transport.
+type tlsRouter struct {
+	c    *Checker
+	base *http.Transport
+}
+
+func (r *tlsRouter) RoundTrip(req *http.Request) (*http.Response, error) {
+	if config, ok := req.Context().Value(tlsConfigKey{}).(*tls.Config); ok {
+		return r.c.tlsTransport(r.base, config).RoundTrip(req)
+	}
+	return r.base.RoundTrip(req)
+}
+
+type tlsTagger struct {
+	next   http.RoundTripper
+	config *tls.Config
+}
+
+func (t tlsTagger) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.next.RoundTrip(req.WithContext(context.WithValue(req.Context(), tlsConfigKey{}, t.config)))
+}
+
+func (c *Checker) methodFor(o *HostOverride) string {
+	if o != nil && o.Method != "" {
+		return o.Method
+	}
+	return c.method
+}
@@ -0,0 +1,140 @@
+package urlcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHostOverridesApplyHeadersAndTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer t" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}))
+	defer server.Close()
+	c := NewChecker(1, 10*time.Millisecond, 0, nil, WithHostOverrides(HostOverride{
+		Host:    "127.0.0.1",
+		Timeout: time.Second,
+		Headers: map[string]string{"Authorization": "Bearer t"},
+	}))
+	results, err := c.Check(context.Background(), []string{server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results[0].OK {
+		t.Fatalf("expected override headers and timeout to apply: %+v", results[0])
+	}
+}
+
+func TestHostOverrideMatching(t *testing.T) {
+	c := NewChecker(1, time.Second, 0, nil, WithHostOverrides(
+		HostOverride{Host: "*.example.com", Timeout: 2 * time.Second},
+		HostOverride{Host: "Example.org", Timeout: 3 * time.Second},
+	))
+	cases := map[string]time.Duration{
+		"https://api.example.com/x": 2 * time.Second,
+		"https://example.com/":      time.Second,
+		"https://example.org/":      3 * time.Second,
+		"https://other.net/":        time.Second,
+	}
+	for target, want := range cases {
+		if got := c.timeoutFor(c.hostOverride(target)); got != want {
+			t.Errorf("%s: got %v, want %v", target, got, want)
+		}
+	}
+}
+
+func TestURLOverridesRetriesAndMethod(t *testing.T) {
+	var mu sync.Mutex
+	methods := map[string][]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		methods[r.URL.Path] = append(methods[r.URL.Path], r.Method)
+		mu.Unlock()
+		if r.URL.Path == "/flaky" {
+			hj, _ := w.(http.Hijacker)
+			conn, _, _ := hj.Hijack()
+			conn.Close()
+		}
+	}))
+	defer server.Close()
+	none, two := 0, 2
+	c := NewChecker(1, time.Second, 0, server.Client(), WithHostOverrides(
+		HostOverride{Host: "127.0.0.1", Retries: &none, Headers: map[string]string{"X-Env": "ci"}},
+		HostOverride{URL: server.URL + "/flaky", Retries: &two},
+		HostOverride{URL: server.URL + "/head", Method: http.MethodHead},
+	))
+	results, err := c.Check(context.Background(), []string{server.URL + "/flaky", server.URL + "/head", server.URL + "/plain"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Attempts != 3 {
+		t.Fatalf("expected the url override to allow 2 retries, got %d attempts", results[0].Attempts)
+	}
+	if got := methods["/head"]; len(got) != 1 || got[0] != http.MethodHead {
+		t.Fatalf("expected a HEAD request for /head, got %v", got)
+	}
+	if got := methods["/plain"]; len(got) != 1 || got[0] != http.MethodGet {
+		t.Fatalf("expected a GET request for /plain, got %v", got)
+	}
+	if o := c.hostOverride(server.URL + "/head"); o == nil || o.Headers["X-Env"] != "ci" || o.Retries == nil || *o.Retries != 0 {
+		t.Fatalf("expected the url override to inherit host settings, got %+v", o)
+	}
+}
+
+func TestURLOverridesFirstWins(t *testing.T) {
+	overrides := make([]HostOverride, 0, 1001)
+	for i := range 1000 {
+		overrides = append(overrides, HostOverride{URL: fmt.Sprintf("https://a.example/%d", i), Method: http.MethodHead})
+	}
+	overrides = append(overrides, HostOverride{URL: "https://a.example/7", Method: http.MethodPost})
+	c := NewChecker(1, time.Second, 0, nil, WithHostOverrides(overrides...))
+	if o := c.hostOverride("https://a.example/7"); o == nil || o.Method != http.MethodHead {
+		t.Fatalf("expected the first override for a url to win, got %+v", o)
+	}
+	if o := c.hostOverride("https://a.example/other"); o != nil {
+		t.Fatalf("unexpected override %+v", o)
+	}
+}
+
+func TestHostOverrideTLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	c := NewChecker(2, time.Second, 0, nil, WithHostOverrides(HostOverride{URL: server.URL + "/trusted", TLS: &tls.Config{InsecureSkipVerify: true}}))
+	results, _ := c.Check(context.Background(), []string{server.URL + "/trusted", server.URL + "/other"})
+	if !results[0].OK {
+		t.Fatalf("expected the override's tls config to be used, got %+v", results[0])
+	}
+	if results[1].OK {
+		t.Fatalf("expected the default tls config to reject the certificate, got %+v", results[1])
+	}
+}
+
+func TestHostOverrideTLSConfigUnderWrappedTransport(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	var seen int
+	wrap := func(next http.RoundTripper) http.RoundTripper {
+		return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			seen++
+			return next.RoundTrip(req)
+		})
+	}
+	c := NewChecker(1, time.Second, 0, nil, WithTransport(wrap), WithHostOverrides(HostOverride{URL: server.URL + "/trusted", TLS: &tls.Config{InsecureSkipVerify: true}}))
+	results, _ := c.Check(context.Background(), []string{server.URL + "/trusted", server.URL + "/other"})
+	if !results[0].OK || results[1].OK || seen != 2 {
+		t.Fatalf("expected the override's tls config beneath the wrapper, got %+v (wrapper saw %d)", results, seen)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
@@ -0,0 +1,66 @@
+package urlcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+type requestError struct {
+	err error
+}
+
+func (e *requestError) Error() string { return e.err.Error() }
+
+func (e *requestError) Unwrap() error { return e.err }
+
+func classifyError(err error) ErrorKind {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		return KindInvalidRequest
+	}
+	var redirErr *redirectError
+	if errors.As(err, &redirErr) {
+		return redirErr.kind
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return KindTimeout
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		if dnsErr.IsNotFound {
+			return KindNXDomain
+		}
+		return KindDNSFailure
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return KindTimeout
+	}
+	return KindConnection
+}
+
+func bodyIntegrity(resp *http.Response, readErr error) (ErrorKind, string) {
+	if readErr != nil {
+		if errors.Is(readErr, io.ErrUnexpectedEOF) {
+			return KindTruncatedBody, "body truncated: " + readErr.Error()
+		}
+		return classifyError(readErr), "body read failed: " + readErr.Error()
+	}
+	var missing []string
+	for name, values := range resp.Trailer {
+		if len(values) == 0 || strings.TrimSpace(values[0]) == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return KindMissingTrailer, fmt.Sprintf("declared trailers not received: %s", strings.Join(missing, ", "))
+	}
+	return "", ""
+}
@@ -0,0 +1,82 @@
+package urlcheck
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTruncatedBodyDetected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		buf.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 100\r\n\r\nshort")
+		buf.Flush()
+	}))
+	defer server.Close()
+	checker := NewChecker(1, time.Second, 0, server.Client())
+	results, err := checker.Check(context.Background(), []string{server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[0].OK || results[0].ErrorKind != KindTruncatedBody {
+		t.Fatalf("expected truncated body, got %+v", results[0])
+	}
+}
+
+func TestMissingTrailerDetected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Trailer", "X-Checksum")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("chunk"))
+		w.(http.Flusher).Flush()
+	}))
+	defer server.Close()
+	checker := NewChecker(1, time.Second, 0, server.Client())
+	results, err := checker.Check(context.Background(), []string{server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[0].OK || results[0].ErrorKind != KindMissingTrailer {
+		t.Fatalf("expected missing trailer, got %+v", results[0])
+	}
+}
+
+func TestTrailerReceivedPasses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Trailer", "X-Checksum")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("chunk"))
+		w.Header().Set("X-Checksum", "abc")
+	}))
+	defer server.Close()
+	checker := NewChecker(1, time.Second, 0, server.Client())
+	results, err := checker.Check(context.Background(), []string{server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !results[0].OK {
+		t.Fatalf("expected trailer to be accepted, got %+v", results[0])
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	if kind := classifyError(&net.DNSError{Err: "no such host", IsNotFound: true}); kind != KindNXDomain {
+		t.Fatalf("expected nxdomain kind, got %s", kind)
+	}
+	if kind := classifyError(&net.DNSError{Err: "server misbehaving", IsTemporary: true}); kind != KindDNSFailure {
+		t.Fatalf("expected dns failure kind, got %s", kind)
+	}
+	if kind := classifyError(context.DeadlineExceeded); kind != KindTimeout {
+		t.Fatalf("expected timeout kind, got %s", kind)
+	}
+	if kind := classifyError(&net.OpError{Op: "dial", Err: &net.AddrError{Err: "refused"}}); kind != KindConnection {
+		t.Fatalf("expected connection kind, got %s", kind)
+	}
+}
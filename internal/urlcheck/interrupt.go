@@ -0,0 +1,15 @@
+package urlcheck
+
+import (
+	"errors"
+	"time"
+)
+
+var errInterrupted = errors.New("interrupted")
+
+func WithGracefulStop(stop <-chan struct{}, grace time.Duration) Option {
+	return func(c *Checker) {
+		c.stop = stop
+		c.grace = grace
+	}
+}
@@ -0,0 +1,63 @@
+package urlcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func interruptServer(t *testing.T, delay time.Duration, stop chan struct{}) *httptest.Server {
+	var once sync.Once
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			once.Do(func() { close(stop) })
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGracefulStopFinishesInFlight(t *testing.T) {
+	stop := make(chan struct{})
+	server := interruptServer(t, 100*time.Millisecond, stop)
+	c := NewChecker(1, 5*time.Second, 0, nil, WithGracefulStop(stop, 5*time.Second))
+	results, err := c.Check(context.Background(), []string{server.URL + "/fast", server.URL + "/slow", server.URL + "/a", server.URL + "/b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results[0].OK || !results[1].OK {
+		t.Fatalf("expected completed and in-flight checks to succeed: %+v", results[:2])
+	}
+	for _, r := range results[2:] {
+		if r.ErrorKind != KindNotAttempted || r.SkipReason != "not attempted (interrupted)" {
+			t.Fatalf("expected undispatched url to be not attempted, got %+v", r)
+		}
+	}
+	if s := Summarize(results, 0); !s.Partial || s.OK != 2 || s.Skipped != 2 {
+		t.Fatalf("unexpected summary %+v", s)
+	}
+}
+
+func TestGracefulStopCancelsAfterGrace(t *testing.T) {
+	stop := make(chan struct{})
+	server := interruptServer(t, 5*time.Second, stop)
+	c := NewChecker(1, 10*time.Second, 0, nil, WithGracefulStop(stop, 20*time.Millisecond))
+	started := time.Now()
+	results, err := c.Check(context.Background(), []string{server.URL + "/slow", server.URL + "/next"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(started) > 2*time.Second {
+		t.Fatalf("in-flight check was not cancelled after the grace period")
+	}
+	if results[0].SkipReason != "not completed (interrupted)" || results[1].ErrorKind != KindNotAttempted {
+		t.Fatalf("unexpected results %+v", results)
+	}
+}
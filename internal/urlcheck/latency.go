@@ -0,0 +1,23 @@
+package urlcheck
+
+import "time"
+
+func WithMaxLatency(d time.Duration, fail bool) Option {
+	return func(c *Checker) {
+		c.maxLatency = d
+		c.slowFails = fail
+	}
+}
+
+func (c *Checker) checkLatency(res Result) Result {
+	if c.maxLatency <= 0 || !res.OK || res.Duration <= c.maxLatency {
+		return res
+	}
+	res.Slow = true
+	if c.slowFails {
+		res.OK = false
+		res.Error = "slow: took " + res.Duration.Round(time.Millisecond).String() + ", limit " + c.maxLatency.String()
+		res.ErrorKind = KindSlow
+	}
+	return res
+}
@@ -0,0 +1,38 @@
+package urlcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaxLatencyMarksSlowResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(50 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	urls := []string{server.URL + "/fast", server.URL + "/slow"}
+	checker := NewChecker(2, time.Second, 0, server.Client(), WithMaxLatency(25*time.Millisecond, true))
+	results, err := checker.Check(context.Background(), urls)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !results[0].OK || results[0].Slow {
+		t.Fatalf("expected fast url to pass, got %+v", results[0])
+	}
+	slow := results[1]
+	if slow.OK || !slow.Slow || slow.ErrorKind != KindSlow || !strings.Contains(slow.Error, "limit 25ms") {
+		t.Fatalf("expected slow failure, got %+v", slow)
+	}
+	checker = NewChecker(2, time.Second, 0, server.Client(), WithMaxLatency(25*time.Millisecond, false))
+	results, _ = checker.Check(context.Background(), urls)
+	if !results[1].OK || !results[1].Slow || results[1].Error != "" {
+		t.Fatalf("expected warn-only slow flag, got %+v", results[1])
+	}
+}
@@ -0,0 +1,18 @@
+package urlcheck
+
+import (
+	"context"
+	"log/slog"
+)
+
+func WithLogger(l *slog.Logger) Option {
+	return func(c *Checker) {
+		c.logger = l
+	}
+}
+
+func (c *Checker) debug(ctx context.Context, msg string, args ...any) {
+	if c.logger != nil {
+		c.logger.DebugContext(ctx, msg, args...)
+	}
+}
@@ -0,0 +1,27 @@
+package urlcheck
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithLoggerLogsAttempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c := NewChecker(1, time.Second, 1, nil, WithLogger(logger))
+	if _, err := c.Check(context.Background(), []string{server.URL}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if strings.Count(out, `msg="attempt failed"`) != 2 || !strings.Contains(out, "retry=true") || !strings.Contains(out, "retry=false") {
+		t.Fatalf("unexpected debug log:\n%s", out)
+	}
+}
@@ -0,0 +1,110 @@
+package urlcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"strings"
+)
+
+func isMailto(target string) bool {
+	return len(target) > 7 && strings.EqualFold(target[:7], "mailto:")
+}
+
+func mailtoDomains(target string) ([]string, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+	to, err := url.PathUnescape(u.Opaque)
+	if err != nil {
+		return nil, err
+	}
+	if to == "" {
+		to = u.Query().Get("to")
+	}
+	var domains []string
+	for _, addr := range strings.Split(to, ",") {
+		parsed, err := mail.ParseAddress(strings.TrimSpace(addr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %q: %w", addr, err)
+		}
+		_, domain, _ := strings.Cut(parsed.Address, "@")
+		domains = append(domains, strings.ToLower(domain))
+	}
+	return domains, nil
+}
+
+func (c *Checker) checkMailto(ctx context.Context, target string) Result {
+	res := Result{URL: target}
+	domains, err := mailtoDomains(target)
+	if err != nil {
+		res.Error = err.Error()
+		res.ErrorKind = KindInvalidRequest
+		return res
+	}
+	override := c.hostOverride(target)
+	retries := c.retriesFor(override)
+	for {
+		res.Attempts++
+		lookupCtx, cancel := context.WithTimeout(ctx, c.timeoutFor(override))
+		res.DNSRecords = nil
+		err = nil
+		for _, domain := range domains {
+			var records []string
+			if records, err = c.mailRecords(lookupCtx, domain); err != nil {
+				break
+			}
+			res.DNSRecords = append(res.DNSRecords, records...)
+		}
+		cancel()
+		if err == nil {
+			res.OK = true
+			return res
+		}
+		res.Error = err.Error()
+		res.ErrorKind = classifyError(err)
+		var dnsErr *net.DNSError
+		if res.Attempts > retries || (errors.As(err, &dnsErr) && dnsErr.IsNotFound) || ctx.Err() != nil || !c.spendRetry() {
+			return res
+		}
+	}
+}
+
+func (c *Checker) mailRecords(ctx context.Context, domain string) ([]string, error) {
+	resolver := c.resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	lookupMX := c.mxLookup
+	if lookupMX == nil {
+		lookupMX = resolver.LookupMX
+	}
+	mxs, err := lookupMX(ctx, domain)
+	var dnsErr *net.DNSError
+	if err != nil && !(errors.As(err, &dnsErr) && dnsErr.IsNotFound) {
+		return nil, err
+	}
+	var records []string
+	for _, mx := range mxs {
+		host := strings.TrimSuffix(mx.Host, ".")
+		if host == "" {
+			return nil, &net.DNSError{Err: "domain does not accept mail (null MX)", Name: domain, IsNotFound: true}
+		}
+		records = append(records, fmt.Sprintf("MX %d %s", mx.Pref, host))
+	}
+	if len(records) > 0 {
+		return records, nil
+	}
+	addrs, err := resolver.LookupIPAddr(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	for _, addr := range addrs {
+		records = append(records, "A "+addr.IP.String())
+	}
+	return records, nil
+}
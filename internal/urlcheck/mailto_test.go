@@ -0,0 +1,46 @@
+package urlcheck
+
+import (
+	"context"
+	"net"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCheckMailto(t *testing.T) {
+	checker := NewChecker(2, time.Second, 0, nil)
+	checker.mxLookup = func(ctx context.Context, domain string) ([]*net.MX, error) {
+		switch domain {
+		case "example.com":
+			return []*net.MX{{Host: "mx1.example.com.", Pref: 10}}, nil
+		case "nomail.example":
+			return []*net.MX{{Host: ".", Pref: 0}}, nil
+		}
+		return nil, &net.DNSError{Err: "no such host", Name: domain, IsNotFound: true}
+	}
+	results, err := checker.Check(context.Background(), []string{
+		"mailto:support@example.com?subject=Hi",
+		"MAILTO:Ops%20Team%20%3Cops@example.com%3E",
+		"mailto:postmaster@localhost",
+		"mailto:not-an-address",
+		"mailto:someone@nomail.example",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if r := results[i]; !r.OK || !slices.Equal(r.DNSRecords, []string{"MX 10 mx1.example.com"}) {
+			t.Fatalf("unexpected result %+v", r)
+		}
+	}
+	if r := results[2]; !r.OK || len(r.DNSRecords) == 0 || r.DNSRecords[0][:2] != "A " {
+		t.Fatalf("expected an A record fallback, got %+v", r)
+	}
+	if r := results[3]; r.OK || r.ErrorKind != KindInvalidRequest {
+		t.Fatalf("unexpected result for a bad address %+v", r)
+	}
+	if r := results[4]; r.OK || r.ErrorKind != KindNXDomain || r.Attempts != 1 {
+		t.Fatalf("unexpected result for a null MX %+v", r)
+	}
+}
@@ -0,0 +1,51 @@
+package urlcheck
+
+import (
+	"bytes"
+	"net/http"
+)
+
+var directoryListingMarkers = []string{
+	"<title>index of /",
+	"<h1>index of /",
+	"<title>directory listing for /",
+	"<h1>directory listing for /",
+	"[to parent directory]",
+}
+
+var defaultPageMarkers = []string{
+	"<h1>it works!</h1>",
+	"apache2 ubuntu default page",
+	"apache2 debian default page",
+	"test page for the apache http server",
+	"welcome to nginx!",
+	"test page for the nginx http server",
+	"iis windows server",
+	"internet information services",
+	"welcome to centos",
+	"your new web server is ready",
+}
+
+func WithMisconfigDetection() Option {
+	return func(c *Checker) {
+		c.misconfig = true
+	}
+}
+
+func (c *Checker) detectMisconfig(status int, body []byte) (ErrorKind, string) {
+	if !c.misconfig || status != http.StatusOK || len(body) == 0 {
+		return "", ""
+	}
+	lower := bytes.ToLower(body)
+	for _, marker := range directoryListingMarkers {
+		if bytes.Contains(lower, []byte(marker)) {
+			return KindDirectoryListing, "directory listing exposed"
+		}
+	}
+	for _, marker := range defaultPageMarkers {
+		if bytes.Contains(lower, []byte(marker)) {
+			return KindDefaultPage, "web server default page"
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,37 @@
+package urlcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMisconfigDetection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/files/":
+			w.Write([]byte("<html><head><title>Index of /files</title></head></html>"))
+		case "/":
+			w.Write([]byte("<html><body><h1>It works!</h1></body></html>"))
+		default:
+			w.Write([]byte("<html><body>real content</body></html>"))
+		}
+	}))
+	defer server.Close()
+	checker := NewChecker(1, time.Second, 0, server.Client(), WithMisconfigDetection())
+	results, err := checker.Check(context.Background(), []string{server.URL + "/files/", server.URL + "/", server.URL + "/app"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[0].OK || results[0].ErrorKind != KindDirectoryListing {
+		t.Fatalf("expected directory listing, got %+v", results[0])
+	}
+	if results[1].OK || results[1].ErrorKind != KindDefaultPage {
+		t.Fatalf("expected default page, got %+v", results[1])
+	}
+	if !results[2].OK {
+		t.Fatalf("expected real page to pass, got %+v", results[2])
+	}
+}
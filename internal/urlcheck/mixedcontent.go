@@ -0,0 +1,119 @@
+package urlcheck
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var subresourceAttrs = map[string][]string{
+	"script": {"src"},
+	"link":   {"href"},
+	"img":    {"src", "srcset"},
+	"iframe": {"src"},
+	"frame":  {"src"},
+	"audio":  {"src"},
+	"video":  {"src", "poster"},
+	"source": {"src", "srcset"},
+	"track":  {"src"},
+	"embed":  {"src"},
+	"object": {"data"},
+	"input":  {"src"},
+}
+
+var subresourceRels = []string{"stylesheet", "icon", "preload", "modulepreload", "manifest", "apple-touch-icon"}
+
+func WithMixedContentDetection(fail bool) Option {
+	return func(c *Checker) {
+		c.mixedContent = true
+		c.mixedFails = fail
+	}
+}
+
+func (c *Checker) detectMixedContent(resp *http.Response, body []byte, res Result) Result {
+	if !c.mixedContent || resp.Request == nil || resp.Request.URL.Scheme != "https" || !isHTML(resp.Header.Get("Content-Type")) {
+		return res
+	}
+	res.MixedContent = mixedContent(body)
+	if c.mixedFails && len(res.MixedContent) > 0 && res.Error == "" {
+		res.OK = false
+		res.Error = "mixed content: " + res.MixedContent[0]
+		res.ErrorKind = KindMixedContent
+	}
+	return res
+}
+
+func isHTML(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "text/html" || mediaType == "application/xhtml+xml")
+}
+
+func mixedContent(body []byte) []string {
+	var findings []string
+	line := 1
+	z := html.NewTokenizer(bytes.NewReader(body))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return findings
+		}
+		start := line
+		line += bytes.Count(z.Raw(), []byte("\n"))
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+		name, hasAttr := z.TagName()
+		attrs, ok := subresourceAttrs[string(name)]
+		if !ok || !hasAttr {
+			continue
+		}
+		values := make(map[string]string)
+		for hasAttr {
+			var k, v []byte
+			k, v, hasAttr = z.TagAttr()
+			values[string(k)] = string(v)
+		}
+		if string(name) == "link" && !subresourceLink(values["rel"]) {
+			continue
+		}
+		for _, attr := range attrs {
+			for _, ref := range attrRefs(attr, values[attr]) {
+				if strings.HasPrefix(strings.ToLower(ref), "http://") {
+					findings = append(findings, "line "+strconv.Itoa(start)+": <"+string(name)+" "+attr+"> "+ref)
+				}
+			}
+		}
+	}
+}
+
+func subresourceLink(rel string) bool {
+	for _, r := range strings.Fields(strings.ToLower(rel)) {
+		for _, want := range subresourceRels {
+			if r == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func attrRefs(attr, value string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	if attr != "srcset" {
+		return []string{value}
+	}
+	var refs []string
+	for _, candidate := range strings.Split(value, ",") {
+		if fields := strings.Fields(candidate); len(fields) > 0 {
+			refs = append(refs, fields[0])
+		}
+	}
+	return refs
+}
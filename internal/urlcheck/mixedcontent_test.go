@@ -0,0 +1,82 @@
+package urlcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+const mixedPage = `<!doctype html>
+<html>
+<head>
+  <link rel="stylesheet" href="http://cdn.example/site.css">
+  <link rel="canonical" href="http://example.com/">
+  <script src="https://cdn.example/ok.js"></script>
+  <script src="HTTP://cdn.example/app.js"></script>
+</head>
+<body>
+  <a href="http://example.com/plain-link">not a subresource</a>
+  <img src="/local.png" srcset="http://img.example/a.png 1x, https://img.example/b.png 2x">
+  <iframe src="http://widgets.example/embed"></iframe>
+</body>
+</html>`
+
+func TestMixedContentFindings(t *testing.T) {
+	want := []string{
+		"line 4: <link href> http://cdn.example/site.css",
+		"line 7: <script src> HTTP://cdn.example/app.js",
+		"line 11: <img srcset> http://img.example/a.png",
+		"line 12: <iframe src> http://widgets.example/embed",
+	}
+	if got := mixedContent([]byte(mixedPage)); !slices.Equal(got, want) {
+		t.Fatalf("got %q\nwant %q", got, want)
+	}
+}
+
+func TestMixedContentDetection(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/data.json" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"src": "<script src=\"http://x.example/a.js\"></script>"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(mixedPage))
+	}))
+	defer server.Close()
+	results, err := NewChecker(1, time.Second, 0, server.Client(), WithMixedContentDetection(false)).Check(context.Background(), []string{server.URL + "/", server.URL + "/data.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results[0].OK || len(results[0].MixedContent) != 4 {
+		t.Fatalf("warn mode should report findings without failing: %+v", results[0])
+	}
+	if results[1].MixedContent != nil {
+		t.Fatalf("non-html responses should not be scanned: %+v", results[1])
+	}
+	results, err = NewChecker(1, time.Second, 0, server.Client(), WithMixedContentDetection(true)).Check(context.Background(), []string{server.URL + "/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := results[0]; r.OK || r.ErrorKind != KindMixedContent || r.Error != "mixed content: line 4: <link href> http://cdn.example/site.css" {
+		t.Fatalf("fail mode should fail the page: %+v", r)
+	}
+}
+
+func TestMixedContentIgnoresPlainHTTPPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(mixedPage))
+	}))
+	defer server.Close()
+	results, err := NewChecker(1, time.Second, 0, server.Client(), WithMixedContentDetection(true)).Check(context.Background(), []string{server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results[0].OK || results[0].MixedContent != nil {
+		t.Fatalf("http pages cannot have mixed content: %+v", results[0])
+	}
+}
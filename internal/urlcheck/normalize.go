@@ -0,0 +1,80 @@
+package urlcheck
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+func NormalizeURL(raw string) (string, []string, error) {
+	var notes []string
+	s := strings.TrimSpace(raw)
+	if s != raw {
+		notes = append(notes, "trimmed whitespace")
+	}
+	if isMailto(s) {
+		return s, notes, nil
+	}
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+		notes = append(notes, "added scheme https")
+	}
+	if scheme, _, _ := strings.Cut(s, "://"); scheme != strings.ToLower(scheme) {
+		notes = append(notes, "lowercased scheme")
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return raw, nil, err
+	}
+	host, port := u.Hostname(), u.Port()
+	if lower := strings.ToLower(host); lower != host {
+		host = lower
+		notes = append(notes, "lowercased host")
+	}
+	ascii, err := idna.Lookup.ToASCII(host)
+	if err != nil {
+		return raw, nil, fmt.Errorf("invalid host %q: %w", host, err)
+	}
+	if ascii != host {
+		notes = append(notes, fmt.Sprintf("idna %s -> %s", host, ascii))
+		host = ascii
+	}
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+		notes = append(notes, "removed default port")
+	}
+	if port != "" || strings.Contains(host, ":") {
+		if port != "" {
+			u.Host = net.JoinHostPort(host, port)
+		} else {
+			u.Host = "[" + host + "]"
+		}
+	} else {
+		u.Host = host
+	}
+	if u.Fragment != "" || u.RawFragment != "" {
+		u.Fragment, u.RawFragment = "", ""
+		notes = append(notes, "removed fragment")
+	}
+	return u.String(), notes, nil
+}
+
+func DisplayURL(requested string) string {
+	u, err := url.Parse(requested)
+	if err != nil {
+		return requested
+	}
+	host := u.Hostname()
+	display, err := idna.Display.ToUnicode(host)
+	if err != nil || display == host {
+		return requested
+	}
+	displayHost := display
+	if port := u.Port(); port != "" {
+		displayHost = net.JoinHostPort(display, port)
+	}
+	return strings.Replace(requested, "://"+u.Host, "://"+displayHost, 1)
+}
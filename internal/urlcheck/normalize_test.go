@@ -0,0 +1,59 @@
+package urlcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	cases := []struct {
+		in, want string
+		notes    int
+	}{
+		{"https://example.com/a", "https://example.com/a", 0},
+		{"Example.COM/path#frag", "https://example.com/path", 3},
+		{"http://example.com:80/", "http://example.com/", 1},
+		{"https://bücher.example/", "https://xn--bcher-kva.example/", 1},
+		{"http://127.0.0.1:8080/x", "http://127.0.0.1:8080/x", 0},
+		{" mailto:ops@example.com ", "mailto:ops@example.com", 1},
+	}
+	for _, tc := range cases {
+		got, notes, err := NormalizeURL(tc.in)
+		if err != nil {
+			t.Fatalf("NormalizeURL(%q): %v", tc.in, err)
+		}
+		if got != tc.want || len(notes) != tc.notes {
+			t.Fatalf("NormalizeURL(%q) = %q %v; want %q with %d notes", tc.in, got, notes, tc.want, tc.notes)
+		}
+	}
+}
+
+func TestDisplayURLShowsUnicode(t *testing.T) {
+	if got := DisplayURL("https://xn--bcher-kva.example/a"); got != "https://bücher.example/a" {
+		t.Fatalf("unexpected display url %q", got)
+	}
+	if got := DisplayURL("https://example.com/"); got != "https://example.com/" {
+		t.Fatalf("unexpected display url %q", got)
+	}
+}
+
+func TestResultReportsOriginalAndRequestedURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	input := strings.Replace(server.URL, "http://", "HTTP://", 1) + "/page#section"
+	checker := NewChecker(1, time.Second, 0, server.Client())
+	results, err := checker.Check(context.Background(), []string{input})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r := results[0]
+	if r.URL != input || r.RequestedURL != server.URL+"/page" || len(r.Normalization) != 2 || !r.OK {
+		t.Fatalf("unexpected result %+v", r)
+	}
+}
@@ -0,0 +1,127 @@
+package urlcheck
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const templatedSimilarity = 0.9
+
+var parkingMarkers = []string{
+	"this domain is for sale",
+	"this domain may be for sale",
+	"buy this domain",
+	"the domain name is for sale",
+	"domain is parked",
+	"this domain has been registered",
+	"parked free, courtesy of",
+	"is parked free",
+	"sedoparking.com",
+	"sedo.com/search/details",
+	"parkingcrew.net",
+	"bodis.com",
+	"dan.com/buy-domain",
+	"afternic.com/forsale",
+	"hugedomains.com",
+	"domainmarket.com",
+	"above.com/marketplace",
+	"img.sedoparking.com",
+	"parklogic.com",
+	"window.park",
+}
+
+type wildcardProbe struct {
+	done     chan struct{}
+	body     string
+	resolved bool
+}
+
+func WithParkedDetection() Option {
+	return func(c *Checker) {
+		c.parked = true
+	}
+}
+
+func (c *Checker) detectParked(ctx context.Context, resp *http.Response, body []byte) string {
+	if !c.parked || resp.StatusCode != http.StatusOK || len(body) == 0 || resp.Request == nil {
+		return ""
+	}
+	lower := bytes.ToLower(body)
+	for _, marker := range parkingMarkers {
+		if bytes.Contains(lower, []byte(marker)) {
+			return "parked domain: page contains " + `"` + marker + `"`
+		}
+	}
+	if strings.HasSuffix(resp.Request.URL.Path, "/lander") {
+		return "parked domain: redirected to a parking lander"
+	}
+	host := resp.Request.URL.Hostname()
+	domain := strings.TrimPrefix(host, "www.")
+	if !bytes.Contains(lower, []byte(strings.ToLower(domain))) {
+		return ""
+	}
+	probe := c.probeWildcard(ctx, resp.Request.URL)
+	if !probe.resolved {
+		return ""
+	}
+	if similarity(maskHost(string(lower), host, domain), probe.body) >= templatedSimilarity {
+		return "parked domain: wildcard dns serves the same templated page for any subdomain"
+	}
+	return ""
+}
+
+// probeWildcard probes a domain once per Check call, so watch cycles notice
+// when its wildcard dns changes.
+func (c *Checker) probeWildcard(ctx context.Context, page *url.URL) *wildcardProbe {
+	domain := strings.TrimPrefix(page.Hostname(), "www.")
+	probe := &wildcardProbe{done: make(chan struct{})}
+	if run, ok := ctx.Value(runKey{}).(*runState); ok {
+		entry, loaded := run.parked.LoadOrStore(domain, probe)
+		probe = entry.(*wildcardProbe)
+		if loaded {
+			<-probe.done
+			return probe
+		}
+	}
+	defer close(probe.done)
+	var label [6]byte
+	rand.Read(label[:])
+	probeHost := "urlcheck-" + hex.EncodeToString(label[:]) + "." + domain
+	target := *page
+	target.Host = probeHost
+	if port := page.Port(); port != "" {
+		target.Host += ":" + port
+	}
+	target.Path, target.RawQuery = "/", ""
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return probe
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return probe
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxInspectBytes))
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return probe
+	}
+	probe.resolved = true
+	probe.body = maskHost(strings.ToLower(string(body)), probeHost)
+	return probe
+}
+
+func maskHost(body string, hosts ...string) string {
+	for _, host := range hosts {
+		body = strings.ReplaceAll(body, strings.ToLower(host), "{host}")
+	}
+	return body
+}
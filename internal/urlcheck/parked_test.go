@@ -0,0 +1,106 @@
+package urlcheck
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParkedDetection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, _ := net.SplitHostPort(r.Host)
+		switch {
+		case host == "sale.test":
+			fmt.Fprint(w, "<h1>Buy this domain</h1>")
+		case host == "lander.test" && r.URL.Path == "/":
+			http.Redirect(w, r, "/lander", http.StatusFound)
+		case host == "lander.test":
+			fmt.Fprint(w, "<div id=root></div>")
+		case strings.HasSuffix(host, "parked.test"):
+			fmt.Fprintf(w, "<title>%s</title><p>Welcome to %s</p><p>related searches: insurance loans hosting</p>", host, host)
+		case strings.HasSuffix(host, "spa.test"):
+			fmt.Fprint(w, "<html><body><div id=app></div><script src=/app.js></script></body></html>")
+		case host == "real.test":
+			fmt.Fprint(w, "<title>real.test</title><p>Our company at real.test builds things</p>")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	addr := server.Listener.Addr().String()
+	_, port, _ := net.SplitHostPort(addr)
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, target string) (net.Conn, error) {
+			host, _, _ := net.SplitHostPort(target)
+			if strings.HasPrefix(host, "urlcheck-") && strings.HasSuffix(host, ".real.test") {
+				return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+			}
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}}
+	checker := NewChecker(2, time.Second, 0, client, WithParkedDetection())
+	urls := []string{
+		"http://sale.test:" + port + "/",
+		"http://lander.test:" + port + "/",
+		"http://www.parked.test:" + port + "/",
+		"http://spa.test:" + port + "/",
+		"http://real.test:" + port + "/",
+	}
+	results, err := checker.Check(context.Background(), urls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`parked domain: page contains "buy this domain"`,
+		"parked domain: redirected to a parking lander",
+		"parked domain: wildcard dns serves the same templated page for any subdomain",
+		"",
+		"",
+	}
+	for i, r := range results {
+		if want[i] == "" {
+			if !r.OK {
+				t.Fatalf("%s should not be flagged: %+v", urls[i], r)
+			}
+			continue
+		}
+		if r.OK || r.ErrorKind != KindParked || r.Error != want[i] {
+			t.Fatalf("%s: got %q (%s), want %q", urls[i], r.Error, r.ErrorKind, want[i])
+		}
+	}
+}
+
+func TestParkedProbeRepeatsEachRun(t *testing.T) {
+	var probes atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, _ := net.SplitHostPort(r.Host)
+		if strings.HasPrefix(host, "urlcheck-") {
+			probes.Add(1)
+		}
+		fmt.Fprintf(w, "<title>%s</title>", host)
+	}))
+	defer server.Close()
+	addr := server.Listener.Addr().String()
+	_, port, _ := net.SplitHostPort(addr)
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}}
+	checker := NewChecker(2, time.Second, 0, client, WithParkedDetection())
+	urls := []string{"http://parked.test:" + port + "/a", "http://parked.test:" + port + "/b"}
+	for range 2 {
+		if _, err := checker.Check(context.Background(), urls); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := probes.Load(); n != 2 {
+		t.Fatalf("expected one wildcard probe per run, got %d", n)
+	}
+}
@@ -0,0 +1,35 @@
+package urlcheck
+
+import "sync"
+
+type pauseGate struct {
+	mu      sync.Mutex
+	resumed chan struct{}
+}
+
+func (c *Checker) Pause() {
+	c.pause.mu.Lock()
+	defer c.pause.mu.Unlock()
+	if c.pause.resumed == nil {
+		c.pause.resumed = make(chan struct{})
+	}
+}
+
+func (c *Checker) Resume() {
+	c.pause.mu.Lock()
+	defer c.pause.mu.Unlock()
+	if c.pause.resumed != nil {
+		close(c.pause.resumed)
+		c.pause.resumed = nil
+	}
+}
+
+func (c *Checker) Paused() bool {
+	return c.waitResumed() != nil
+}
+
+func (c *Checker) waitResumed() <-chan struct{} {
+	c.pause.mu.Lock()
+	defer c.pause.mu.Unlock()
+	return c.pause.resumed
+}
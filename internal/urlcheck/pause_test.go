@@ -0,0 +1,73 @@
+package urlcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPauseStopsDispatchUntilResume(t *testing.T) {
+	var served atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served.Add(1)
+	}))
+	defer server.Close()
+	checker := NewChecker(2, time.Second, 0, server.Client())
+	checker.Pause()
+	if !checker.Paused() {
+		t.Fatal("expected checker to report paused")
+	}
+	urls := []string{server.URL + "/1", server.URL + "/2", server.URL + "/3"}
+	done := make(chan []Result)
+	go func() {
+		results, _ := checker.Check(context.Background(), urls)
+		done <- results
+	}()
+	time.Sleep(100 * time.Millisecond)
+	if n := served.Load(); n != 0 {
+		t.Fatalf("expected no requests while paused, got %d", n)
+	}
+	select {
+	case <-done:
+		t.Fatal("run finished while paused")
+	default:
+	}
+	checker.Resume()
+	select {
+	case results := <-done:
+		if len(results) != len(urls) {
+			t.Fatalf("expected %d results, got %d", len(urls), len(results))
+		}
+		for _, r := range results {
+			if !r.OK {
+				t.Fatalf("unexpected result after resume: %+v", r)
+			}
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("run did not finish after resume")
+	}
+	if checker.Paused() {
+		t.Fatal("checker still reports paused")
+	}
+}
+
+func TestPausedRunStillHonoursCancellation(t *testing.T) {
+	checker := NewChecker(1, time.Second, 0, http.DefaultClient)
+	checker.Pause()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		checker.Check(ctx, []string{"http://example.invalid/"})
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("paused run ignored cancellation")
+	}
+}
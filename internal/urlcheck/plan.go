@@ -0,0 +1,76 @@
+package urlcheck
+
+import (
+	"maps"
+	"strings"
+	"time"
+)
+
+type PlannedCheck struct {
+	URL           string            `json:"url"`
+	RequestedURL  string            `json:"requested_url"`
+	Normalization []string          `json:"normalization,omitempty"`
+	Duplicates    int               `json:"duplicates,omitempty"`
+	Check         string            `json:"check"`
+	Method        string            `json:"method,omitempty"`
+	Timeout       time.Duration     `json:"timeout"`
+	Retries       int               `json:"retries"`
+	Headers       map[string]string `json:"headers,omitempty"`
+}
+
+func (c *Checker) Plan(urls []string) []PlannedCheck {
+	var plan []PlannedCheck
+	index := make(map[string]int)
+	for _, u := range urls {
+		requested, notes, err := NormalizeURL(u)
+		if err != nil {
+			requested, notes = u, []string{"not normalized: " + err.Error()}
+		}
+		if i, ok := index[requested]; ok {
+			plan[i].Duplicates++
+			continue
+		}
+		index[requested] = len(plan)
+		override := c.hostOverride(requested)
+		p := PlannedCheck{
+			URL:           u,
+			RequestedURL:  requested,
+			Normalization: notes,
+			Check:         c.checkKind(requested),
+			Timeout:       c.timeoutFor(override),
+			Retries:       c.retriesFor(override),
+		}
+		if p.Check == string(CheckHTTP) || p.Check == string(CheckGraphQL) {
+			p.Method = c.methodFor(override)
+			if p.Check == string(CheckGraphQL) {
+				p.Method = "POST"
+			}
+			if override != nil && len(override.Headers) > 0 {
+				p.Headers = maps.Clone(override.Headers)
+			}
+		}
+		plan = append(plan, p)
+	}
+	return plan
+}
+
+func (c *Checker) checkKind(target string) string {
+	if obj, ok := c.bucketTarget(target); ok {
+		return obj.provider
+	}
+	switch {
+	case isGRPCURL(target):
+		return string(CheckGRPCHealth)
+	case isFileURL(target):
+		return "file"
+	case isMailto(target):
+		return "mailto"
+	case strings.HasPrefix(target, "ftp://"):
+		return "ftp"
+	case strings.HasPrefix(target, "sftp://"):
+		return "sftp"
+	case c.checkType != "":
+		return string(c.checkType)
+	}
+	return string(CheckHTTP)
+}
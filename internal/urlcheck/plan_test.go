@@ -0,0 +1,32 @@
+package urlcheck
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPlan(t *testing.T) {
+	retries := 0
+	checker := NewChecker(1, 3*time.Second, 2, nil,
+		WithRequestBody("HEAD", nil),
+		WithHostOverrides(HostOverride{Host: "api.example", Timeout: time.Second, Retries: &retries, Headers: map[string]string{"Authorization": "Bearer x"}}))
+	plan := checker.Plan([]string{
+		"https://a.example/",
+		"A.example/",
+		"https://api.example/v1",
+		"s3://bucket/key",
+		"mailto:ops@example.com",
+	})
+	if len(plan) != 4 {
+		t.Fatalf("expected duplicates to be folded, got %+v", plan)
+	}
+	if p := plan[0]; p.Duplicates != 1 || p.Check != "http" || p.Method != "HEAD" || p.Timeout != 3*time.Second || p.Retries != 2 || p.Headers != nil {
+		t.Fatalf("unexpected plan entry %+v", p)
+	}
+	if p := plan[1]; p.Timeout != time.Second || p.Retries != 0 || p.Headers["Authorization"] != "Bearer x" {
+		t.Fatalf("expected host override settings, got %+v", p)
+	}
+	if plan[2].Check != "s3" || plan[2].Method != "" || plan[3].Check != "mailto" {
+		t.Fatalf("unexpected check kinds %+v", plan[2:])
+	}
+}
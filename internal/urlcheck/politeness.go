@@ -0,0 +1,81 @@
+package urlcheck
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type hostPacer struct {
+	delay  time.Duration
+	jitter time.Duration
+	mu     sync.Mutex
+	next   map[string]time.Time
+}
+
+func WithHostDelay(delay, jitter time.Duration) Option {
+	return func(c *Checker) {
+		if delay <= 0 && jitter <= 0 {
+			c.pacer = nil
+			return
+		}
+		c.pacer = &hostPacer{delay: delay, jitter: jitter, next: make(map[string]time.Time)}
+	}
+}
+
+func (p *hostPacer) reserve(host string, now time.Time) time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	at := p.next[host]
+	if at.Before(now) {
+		at = now
+	}
+	gap := p.delay
+	if p.jitter > 0 {
+		gap += rand.N(p.jitter)
+	}
+	p.next[host] = at.Add(gap)
+	return at
+}
+
+func (c *Checker) pace(ctx context.Context, target string) error {
+	if c.pacer == nil {
+		return nil
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil
+	}
+	started := time.Now()
+	err = c.pacer.wait(ctx, u.Host)
+	if paced, ok := ctx.Value(pacedKey{}).(*atomic.Int64); ok {
+		paced.Add(int64(time.Since(started)))
+	}
+	return err
+}
+
+type pacedKey struct{}
+
+// withPacedTime makes pace add the time it waits to paced, so the politeness
+// delay can be kept out of the url's latency.
+func withPacedTime(ctx context.Context, paced *atomic.Int64) context.Context {
+	return context.WithValue(ctx, pacedKey{}, paced)
+}
+
+func (p *hostPacer) wait(ctx context.Context, host string) error {
+	wait := time.Until(p.reserve(host, time.Now()))
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,63 @@
+package urlcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHostDelaySpacesSameHostRequests(t *testing.T) {
+	var mu sync.Mutex
+	var seen []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, time.Now())
+		mu.Unlock()
+	}))
+	defer server.Close()
+	checker := NewChecker(3, time.Second, 0, server.Client(), WithHostDelay(50*time.Millisecond, 0))
+	urls := []string{server.URL + "/a", server.URL + "/b", server.URL + "/c"}
+	if _, err := checker.Check(context.Background(), urls); err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(seen, func(i, j int) bool { return seen[i].Before(seen[j]) })
+	for i := 1; i < len(seen); i++ {
+		if gap := seen[i].Sub(seen[i-1]); gap < 40*time.Millisecond {
+			t.Fatalf("requests %d and %d were only %v apart", i-1, i, gap)
+		}
+	}
+}
+
+func TestHostPacerKeysByHostAndAddsJitter(t *testing.T) {
+	p := &hostPacer{delay: time.Second, jitter: 500 * time.Millisecond, next: make(map[string]time.Time)}
+	now := time.Now()
+	if at := p.reserve("a.example", now); !at.Equal(now) {
+		t.Fatalf("first request should not wait, got %v", at.Sub(now))
+	}
+	if at := p.reserve("b.example", now); !at.Equal(now) {
+		t.Fatalf("other hosts should not wait, got %v", at.Sub(now))
+	}
+	wait := p.reserve("a.example", now).Sub(now)
+	if wait < time.Second || wait >= 1500*time.Millisecond {
+		t.Fatalf("expected a delay within [1s, 1.5s), got %v", wait)
+	}
+}
+
+func TestHostDelayNotCountedAsLatency(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	checker := NewChecker(3, time.Second, 0, server.Client(), WithHostDelay(150*time.Millisecond, 0), WithMaxLatency(100*time.Millisecond, true))
+	results, err := checker.Check(context.Background(), []string{server.URL + "/a", server.URL + "/b", server.URL + "/c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if !r.OK || r.Duration >= 100*time.Millisecond {
+			t.Fatalf("politeness delay leaked into latency: %+v", r)
+		}
+	}
+}
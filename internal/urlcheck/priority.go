@@ -0,0 +1,34 @@
+package urlcheck
+
+import (
+	"cmp"
+	"slices"
+)
+
+func WithPriority(rank func(url string) int) Option {
+	return func(c *Checker) {
+		c.priority = rank
+	}
+}
+
+func (c *Checker) dispatchOrder(urls []string) []int {
+	return PriorityOrder(urls, c.priority)
+}
+
+// PriorityOrder returns the indexes of urls sorted by rank, lowest first and
+// stable within a rank. A nil rank keeps input order.
+func PriorityOrder(urls []string, rank func(url string) int) []int {
+	order := make([]int, len(urls))
+	for i := range order {
+		order[i] = i
+	}
+	if rank == nil {
+		return order
+	}
+	ranks := make([]int, len(urls))
+	for i, u := range urls {
+		ranks[i] = rank(u)
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return cmp.Compare(ranks[a], ranks[b]) })
+	return order
+}
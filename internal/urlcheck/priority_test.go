@@ -0,0 +1,64 @@
+package urlcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPriorityControlsDispatchOrder(t *testing.T) {
+	var mu sync.Mutex
+	var hits []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits = append(hits, r.URL.Path)
+		mu.Unlock()
+	}))
+	defer server.Close()
+	urls := []string{server.URL + "/a", server.URL + "/critical-1", server.URL + "/b", server.URL + "/critical-2"}
+	rank := func(u string) int {
+		if strings.Contains(u, "critical") {
+			return 0
+		}
+		return 1
+	}
+	results, err := NewChecker(1, time.Second, 0, nil, WithPriority(rank)).Check(context.Background(), urls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(hits, " "); got != "/critical-1 /critical-2 /a /b" {
+		t.Fatalf("unexpected dispatch order %s", got)
+	}
+	for i, r := range results {
+		if r.URL != urls[i] || !r.OK {
+			t.Fatalf("results must stay in input order, got %+v at %d", r, i)
+		}
+	}
+}
+
+func TestPriorityNotAttemptedKeepsInputOrder(t *testing.T) {
+	stop := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(stop)
+		time.Sleep(50 * time.Millisecond)
+	}))
+	defer server.Close()
+	urls := []string{server.URL + "/a", server.URL + "/b"}
+	rank := func(u string) int {
+		if strings.HasSuffix(u, "/b") {
+			return 0
+		}
+		return 1
+	}
+	results, _ := NewChecker(1, time.Second, 0, nil, WithPriority(rank), WithGracefulStop(stop, time.Second)).Check(context.Background(), urls)
+	if r := results[0]; r.URL != urls[0] || r.ErrorKind != KindNotAttempted {
+		t.Fatalf("expected the low priority url to be skipped, got %+v", r)
+	}
+	if r := results[1]; r.URL != urls[1] || !r.OK {
+		t.Fatalf("expected the prioritized url to be checked, got %+v", r)
+	}
+}
@@ -0,0 +1,59 @@
+package urlcheck
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+type Processor interface {
+	Process(ctx context.Context, results []Result) ([]Result, error)
+}
+
+type ProcessorFunc func(ctx context.Context, results []Result) ([]Result, error)
+
+func (f ProcessorFunc) Process(ctx context.Context, results []Result) ([]Result, error) {
+	return f(ctx, results)
+}
+
+func PostProcess(ctx context.Context, results []Result, procs ...Processor) ([]Result, error) {
+	for _, p := range procs {
+		out, err := p.Process(ctx, results)
+		if err != nil {
+			return results, err
+		}
+		results = out
+	}
+	return results, nil
+}
+
+type ExecProcessor struct {
+	Command string
+	Args    []string
+}
+
+func (p ExecProcessor) Process(ctx context.Context, results []Result) ([]Result, error) {
+	in, err := json.Marshal(results)
+	if err != nil {
+		return nil, err
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.Command, p.Args...)
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %w: %s", p.Command, err, msg)
+		}
+		return nil, fmt.Errorf("%s: %w", p.Command, err)
+	}
+	var out []Result
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		return nil, fmt.Errorf("%s: invalid output: %w", p.Command, err)
+	}
+	return out, nil
+}
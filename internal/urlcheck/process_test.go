@@ -0,0 +1,56 @@
+package urlcheck
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+)
+
+func TestPostProcessChainsProcessors(t *testing.T) {
+	results := []Result{{URL: "https://a.example", OK: true}, {URL: "https://b.example", OK: false}}
+	dropOK := ProcessorFunc(func(_ context.Context, in []Result) ([]Result, error) {
+		var out []Result
+		for _, r := range in {
+			if !r.OK {
+				out = append(out, r)
+			}
+		}
+		return out, nil
+	})
+	tag := ProcessorFunc(func(_ context.Context, in []Result) ([]Result, error) {
+		for i := range in {
+			in[i].Error = "tagged"
+		}
+		return in, nil
+	})
+	out, err := PostProcess(context.Background(), results, dropOK, tag)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out) != 1 || out[0].URL != "https://b.example" || out[0].Error != "tagged" {
+		t.Fatalf("unexpected results: %+v", out)
+	}
+}
+
+func TestExecProcessorRoundTripsJSON(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat not available")
+	}
+	results := []Result{{URL: "https://a.example", OK: true, Status: 200}}
+	out, err := ExecProcessor{Command: "cat"}.Process(context.Background(), results)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out) != 1 || out[0].URL != results[0].URL || out[0].Status != 200 {
+		t.Fatalf("unexpected results: %+v", out)
+	}
+}
+
+func TestExecProcessorReportsFailure(t *testing.T) {
+	if _, err := exec.LookPath("false"); err != nil {
+		t.Skip("false not available")
+	}
+	if _, err := (ExecProcessor{Command: "false"}).Process(context.Background(), nil); err == nil {
+		t.Fatal("expected error from failing processor")
+	}
+}
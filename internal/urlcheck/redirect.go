@@ -0,0 +1,184 @@
+package urlcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const maxRedirectHops = 10
+
+type redirectError struct {
+	kind  ErrorKind
+	limit int
+	chain []string
+}
+
+func (e *redirectError) Error() string {
+	if e.kind == KindRedirectLoop {
+		return "redirect loop: " + strings.Join(e.chain, " -> ")
+	}
+	return fmt.Sprintf("more than %d redirects: %s", e.limit, strings.Join(e.chain, " -> "))
+}
+
+func WithMaxRedirects(n int) Option {
+	return func(c *Checker) {
+		if n >= 0 {
+			c.maxRedirects = n
+		}
+	}
+}
+
+func (c *Checker) checkRedirect(req *http.Request, via []*http.Request) error {
+	chain := make([]string, 0, len(via)+1)
+	for _, r := range via {
+		chain = append(chain, r.URL.String())
+	}
+	return c.redirectProblem(chain, req.URL.String())
+}
+
+func (c *Checker) redirectProblem(chain []string, next string) error {
+	seen := slices.Contains(chain, next)
+	chain = append(slices.Clip(chain), next)
+	if seen {
+		return &redirectError{kind: KindRedirectLoop, chain: chain}
+	}
+	if len(chain)-1 > c.maxRedirects {
+		return &redirectError{kind: KindTooManyRedirects, limit: c.maxRedirects, chain: chain}
+	}
+	return nil
+}
+
+func WithRedirectDedupe() Option {
+	return func(c *Checker) {
+		c.dedupe = true
+	}
+}
+
+type hop struct {
+	done     chan struct{}
+	res      Result
+	location string
+}
+
+type hopCache struct {
+	mu   sync.Mutex
+	hops map[string]*hop
+}
+
+func newHopCache() *hopCache {
+	return &hopCache{hops: make(map[string]*hop)}
+}
+
+func (h *hopCache) get(ctx context.Context, target string, fetch func(context.Context, string) (Result, string)) (Result, string) {
+	h.mu.Lock()
+	entry, ok := h.hops[target]
+	if !ok {
+		entry = &hop{done: make(chan struct{})}
+		h.hops[target] = entry
+	}
+	h.mu.Unlock()
+	if !ok {
+		entry.res, entry.location = fetch(ctx, target)
+		close(entry.done)
+	}
+	<-entry.done
+	return entry.res, entry.location
+}
+
+func (c *Checker) checkDeduped(ctx context.Context, target string, hops *hopCache) Result {
+	client := *c.client
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	fetch := func(ctx context.Context, u string) (Result, string) {
+		return c.fetch(ctx, &client, u)
+	}
+	current := target
+	chain := []string{target}
+	permanent := true
+	for {
+		res, location := hops.get(ctx, current, fetch)
+		if !isRedirect(res.Status) || location == "" {
+			res.URL = target
+			res.FinalURL = current
+			if permanent && current != target {
+				res.MovedTo = current
+			}
+			return res
+		}
+		permanent = permanent && isPermanentRedirect(res.Status)
+		next, err := resolveLocation(current, location)
+		if err != nil {
+			res.URL = target
+			res.OK = false
+			res.Error = err.Error()
+			return res
+		}
+		if err := c.redirectProblem(chain, next); err != nil {
+			rerr := err.(*redirectError)
+			res.URL = target
+			res.FinalURL = current
+			res.OK = false
+			res.Error = rerr.Error()
+			res.ErrorKind = rerr.kind
+			res.RedirectChain = rerr.chain
+			return res
+		}
+		chain = append(chain, next)
+		current = next
+	}
+}
+
+func permanentTarget(resp *http.Response) string {
+	final := resp.Request
+	if final == nil || final.Response == nil {
+		return ""
+	}
+	for r := final; r != nil && r.Response != nil; r = r.Response.Request {
+		if !isPermanentRedirect(r.Response.StatusCode) {
+			return ""
+		}
+	}
+	return final.URL.String()
+}
+
+func isPermanentRedirect(status int) bool {
+	return status == http.StatusMovedPermanently || status == http.StatusPermanentRedirect
+}
+
+func isRedirect(status int) bool {
+	switch status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+func resolveLocation(base, location string) (string, error) {
+	b, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	l, err := url.Parse(location)
+	if err != nil {
+		return "", fmt.Errorf("invalid redirect location %q: %w", location, err)
+	}
+	return b.ResolveReference(l).String(), nil
+}
+
+func RedirectGroups(results []Result) map[string][]string {
+	groups := make(map[string][]string)
+	for _, r := range results {
+		if r.FinalURL == "" || r.FinalURL == r.URL {
+			continue
+		}
+		groups[r.FinalURL] = append(groups[r.FinalURL], r.URL)
+	}
+	return groups
+}
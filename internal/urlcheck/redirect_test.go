@@ -0,0 +1,157 @@
+package urlcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRedirectDedupeChecksTargetOnce(t *testing.T) {
+	var targetHits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a", "/b":
+			http.Redirect(w, r, "/hop", http.StatusMovedPermanently)
+		case "/hop":
+			http.Redirect(w, r, "/target", http.StatusFound)
+		case "/target":
+			atomic.AddInt32(&targetHits, 1)
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+	checker := NewChecker(2, time.Second, 0, server.Client(), WithRedirectDedupe())
+	results, err := checker.Check(context.Background(), []string{server.URL + "/a", server.URL + "/b"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, r := range results {
+		if !r.OK || r.Status != http.StatusOK || r.FinalURL != server.URL+"/target" {
+			t.Fatalf("unexpected result %+v", r)
+		}
+	}
+	if hits := atomic.LoadInt32(&targetHits); hits != 1 {
+		t.Fatalf("expected target to be fetched once, got %d", hits)
+	}
+	groups := RedirectGroups(results)
+	if len(groups[server.URL+"/target"]) != 2 {
+		t.Fatalf("expected both urls grouped, got %v", groups)
+	}
+}
+
+func TestRedirectDedupeStopsLoops(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/x" {
+			http.Redirect(w, r, "/y", http.StatusFound)
+			return
+		}
+		http.Redirect(w, r, "/x", http.StatusFound)
+	}))
+	defer server.Close()
+	checker := NewChecker(1, time.Second, 0, server.Client(), WithRedirectDedupe())
+	results, err := checker.Check(context.Background(), []string{server.URL + "/x"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[0].OK {
+		t.Fatalf("expected redirect loop to fail, got %+v", results[0])
+	}
+}
+
+func newRedirectServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/a":
+			http.Redirect(w, r, "/b", http.StatusFound)
+		case r.URL.Path == "/b":
+			http.Redirect(w, r, "/a", http.StatusFound)
+		case strings.HasPrefix(r.URL.Path, "/hop/"):
+			n, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/hop/"))
+			if n > 0 {
+				http.Redirect(w, r, "/hop/"+strconv.Itoa(n-1), http.StatusMovedPermanently)
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRedirectLoopAndChainLength(t *testing.T) {
+	server := newRedirectServer(t)
+	for _, dedupe := range []bool{false, true} {
+		opts := []Option{WithMaxRedirects(3)}
+		if dedupe {
+			opts = append(opts, WithRedirectDedupe())
+		}
+		checker := NewChecker(1, time.Second, 2, nil, opts...)
+		results, err := checker.Check(context.Background(), []string{server.URL + "/a", server.URL + "/hop/4", server.URL + "/hop/3"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		loop := results[0]
+		wantLoop := []string{server.URL + "/a", server.URL + "/b", server.URL + "/a"}
+		if loop.OK || loop.ErrorKind != KindRedirectLoop || !slices.Equal(loop.RedirectChain, wantLoop) {
+			t.Fatalf("dedupe=%v: expected loop, got %+v", dedupe, loop)
+		}
+		if want := "redirect loop: " + strings.Join(wantLoop, " -> "); loop.Error != want {
+			t.Fatalf("dedupe=%v: got error %q, want %q", dedupe, loop.Error, want)
+		}
+		if !dedupe && loop.Attempts != 1 {
+			t.Fatalf("redirect loops should not be retried, got %d attempts", loop.Attempts)
+		}
+		long := results[1]
+		if long.OK || long.ErrorKind != KindTooManyRedirects || len(long.RedirectChain) != 5 || !strings.HasPrefix(long.Error, "more than 3 redirects: ") {
+			t.Fatalf("dedupe=%v: expected too many redirects, got %+v", dedupe, long)
+		}
+		if !results[2].OK {
+			t.Fatalf("dedupe=%v: chain within the limit should pass: %+v", dedupe, results[2])
+		}
+	}
+}
+
+func TestCustomCheckRedirectIsKept(t *testing.T) {
+	server := newRedirectServer(t)
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	results, err := NewChecker(1, time.Second, 0, client).Check(context.Background(), []string{server.URL + "/a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Status != http.StatusFound || results[0].RedirectChain != nil {
+		t.Fatalf("caller's redirect policy was replaced: %+v", results[0])
+	}
+}
+
+func TestMovedToOnlyForPermanentChains(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/mid", http.StatusMovedPermanently)
+		case "/mid":
+			http.Redirect(w, r, "/new", http.StatusPermanentRedirect)
+		case "/temp":
+			http.Redirect(w, r, "/new", http.StatusFound)
+		case "/new":
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+	urls := []string{server.URL + "/old", server.URL + "/temp", server.URL + "/new"}
+	want := []string{server.URL + "/new", "", ""}
+	for _, opts := range [][]Option{nil, {WithRedirectDedupe()}} {
+		results, err := NewChecker(2, time.Second, 0, server.Client(), opts...).Check(context.Background(), urls)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i, r := range results {
+			if r.MovedTo != want[i] {
+				t.Fatalf("%s: expected moved_to %q, got %q", r.URL, want[i], r.MovedTo)
+			}
+		}
+	}
+}
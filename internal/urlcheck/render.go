@@ -0,0 +1,64 @@
+package urlcheck
+
+import (
+	"context"
+	"strconv"
+)
+
+type RenderReport struct {
+	ReadyState     string   `json:"ready_state,omitempty"`
+	Title          string   `json:"title,omitempty"`
+	TextLength     int      `json:"text_length"`
+	JSErrors       []string `json:"js_errors,omitempty"`
+	FailedRequests []string `json:"failed_requests,omitempty"`
+}
+
+type Renderer func(ctx context.Context, url string) (*RenderReport, error)
+
+func WithRenderer(r Renderer) Option {
+	return func(c *Checker) {
+		c.renderer = r
+	}
+}
+
+func (c *Checker) checkRender(ctx context.Context, target string, res Result) Result {
+	if c.renderer == nil || !res.OK {
+		return res
+	}
+	if res.FinalURL != "" {
+		target = res.FinalURL
+	}
+	report, err := c.renderer(ctx, target)
+	res.Render = report
+	if problem := renderProblem(report, err); problem != "" {
+		res.OK = false
+		res.Error = "render: " + problem
+		res.ErrorKind = KindRender
+	}
+	return res
+}
+
+func renderProblem(report *RenderReport, err error) string {
+	switch {
+	case err != nil:
+		return err.Error()
+	case report == nil:
+		return "no report"
+	case len(report.JSErrors) > 0:
+		return plural(len(report.JSErrors), "js error") + ": " + report.JSErrors[0]
+	case len(report.FailedRequests) > 0:
+		return plural(len(report.FailedRequests), "failed request") + ": " + report.FailedRequests[0]
+	case report.ReadyState != "" && report.ReadyState != "complete":
+		return "document stuck in " + report.ReadyState
+	case report.TextLength == 0:
+		return "page rendered no text"
+	}
+	return ""
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return strconv.Itoa(n) + " " + noun + "s"
+}
@@ -0,0 +1,63 @@
+package urlcheck
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRendererFailsPagesThatRenderBroken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	var rendered []string
+	renderer := func(ctx context.Context, url string) (*RenderReport, error) {
+		rendered = append(rendered, url)
+		switch {
+		case strings.HasSuffix(url, "/app"):
+			return &RenderReport{ReadyState: "complete", TextLength: 10, JSErrors: []string{"TypeError: x is undefined"}}, nil
+		case strings.HasSuffix(url, "/assets"):
+			return &RenderReport{ReadyState: "complete", TextLength: 10, FailedRequests: []string{"a.js: 404", "b.css: net::ERR_FAILED"}}, nil
+		case strings.HasSuffix(url, "/blank"):
+			return &RenderReport{ReadyState: "complete"}, nil
+		case strings.HasSuffix(url, "/crash"):
+			return nil, errors.New("browser crashed")
+		}
+		return &RenderReport{ReadyState: "complete", Title: "Home", TextLength: 42}, nil
+	}
+	checker := NewChecker(1, time.Second, 0, server.Client(), WithRenderer(renderer))
+	urls := []string{server.URL + "/", server.URL + "/app", server.URL + "/assets", server.URL + "/blank", server.URL + "/crash", server.URL + "/missing"}
+	results, err := checker.Check(context.Background(), urls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results[0].OK || results[0].Render == nil || results[0].Render.Title != "Home" {
+		t.Fatalf("healthy page should pass with a render report: %+v", results[0])
+	}
+	want := []string{
+		"",
+		"render: 1 js error: TypeError: x is undefined",
+		"render: 2 failed requests: a.js: 404",
+		"render: page rendered no text",
+		"render: browser crashed",
+	}
+	for i := 1; i < len(want); i++ {
+		if results[i].OK || results[i].ErrorKind != KindRender || results[i].Error != want[i] {
+			t.Fatalf("result %d: got %q (%s), want %q", i, results[i].Error, results[i].ErrorKind, want[i])
+		}
+	}
+	if results[5].ErrorKind == KindRender || results[5].Render != nil {
+		t.Fatalf("failed fetch should not be rendered: %+v", results[5])
+	}
+	if len(rendered) != 5 || rendered[1] != server.URL+"/app" {
+		t.Fatalf("unexpected rendered urls: %v", rendered)
+	}
+}
@@ -0,0 +1,102 @@
+package urlcheck
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"time"
+)
+
+func WithRequestBody(method string, body []byte) Option {
+	return func(c *Checker) {
+		if method != "" {
+			c.method = method
+		}
+		c.body = body
+	}
+}
+
+func WithExpectContinue(timeout time.Duration) Option {
+	return func(c *Checker) {
+		if timeout <= 0 {
+			timeout = time.Second
+		}
+		c.expect = true
+		c.tuneTransport(func(t *http.Transport) {
+			t.ExpectContinueTimeout = timeout
+		})
+	}
+}
+
+func (c *Checker) newRequest(ctx context.Context, target string, override *HostOverride) (*http.Request, error) {
+	var body io.Reader
+	if c.body != nil {
+		body = bytes.NewReader(c.body)
+	}
+	req, err := http.NewRequestWithContext(ctx, c.methodFor(override), target, body)
+	if err != nil {
+		return nil, err
+	}
+	if override != nil {
+		for k, v := range override.Headers {
+			req.Header.Set(k, v)
+		}
+	}
+	if len(c.encodings) > 0 {
+		req.Header.Set("Accept-Encoding", strings.Join(c.encodings, ", "))
+	}
+	if c.expect && c.body != nil {
+		req.Header.Set("Expect", "100-continue")
+	}
+	return req, nil
+}
+
+func (c *Checker) tuneTransport(fn func(*http.Transport)) {
+	var t *http.Transport
+	switch rt := c.client.Transport.(type) {
+	case nil:
+		t = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		t = rt.Clone()
+	default:
+		return
+	}
+	fn(t)
+	client := *c.client
+	client.Transport = t
+	c.client = &client
+}
+
+func withContinueTrace(ctx context.Context, continued *bool) context.Context {
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		Got100Continue: func() {
+			*continued = true
+		},
+	})
+}
+
+func continueOutcome(continued bool) string {
+	if continued {
+		return "honored"
+	}
+	return "not_honored"
+}
+
+func WithTransport(wrap func(http.RoundTripper) http.RoundTripper) Option {
+	return func(c *Checker) {
+		base := c.client.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		if t, ok := base.(*http.Transport); ok {
+			c.tlsRouter = &tlsRouter{c: c, base: t}
+			base = c.tlsRouter
+		}
+		client := *c.client
+		client.Transport = wrap(base)
+		c.client = &client
+	}
+}
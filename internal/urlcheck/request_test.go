@@ -0,0 +1,53 @@
+package urlcheck
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestExpectContinueHonored(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		n, _ := io.Copy(io.Discard, r.Body)
+		if n != 4096 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	body := bytes.Repeat([]byte("x"), 4096)
+	checker := NewChecker(1, 2*time.Second, 0, server.Client(),
+		WithRequestBody(http.MethodPost, body), WithExpectContinue(time.Second))
+	results, err := checker.Check(context.Background(), []string{server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !results[0].OK || results[0].ExpectContinue != "honored" {
+		t.Fatalf("expected honored 100-continue, got %+v", results[0])
+	}
+}
+
+func TestExpectContinueNotHonored(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+	}))
+	defer server.Close()
+	checker := NewChecker(1, 2*time.Second, 0, server.Client(),
+		WithRequestBody(http.MethodPost, []byte("payload")), WithExpectContinue(time.Second))
+	results, err := checker.Check(context.Background(), []string{server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[0].Status != http.StatusRequestEntityTooLarge || results[0].ExpectContinue != "not_honored" {
+		t.Fatalf("expected early rejection without 100-continue, got %+v", results[0])
+	}
+}
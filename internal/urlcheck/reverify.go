@@ -0,0 +1,62 @@
+package urlcheck
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+const (
+	ReverifyReproduced = "reproduced"
+	ReverifyRecovered  = "recovered"
+)
+
+func (c *Checker) Reverify(ctx context.Context, results []Result, timeout time.Duration, concurrency int) ([]Result, error) {
+	var idxs []int
+	var urls []string
+	for i, r := range results {
+		if r.OK || r.SkipReason != "" {
+			continue
+		}
+		idxs = append(idxs, i)
+		urls = append(urls, r.URL)
+	}
+	if len(urls) == 0 {
+		return results, nil
+	}
+	again := *c
+	again.onResult = nil
+	again.budget = 0
+	if timeout > 0 {
+		again.timeout = timeout
+	}
+	if concurrency > 0 {
+		again.concurrency = concurrency
+	}
+	second, err := again.Check(ctx, urls)
+	out := append([]Result(nil), results...)
+	for n, idx := range idxs {
+		prev := out[idx]
+		next := second[n]
+		if next.URL == "" {
+			continue
+		}
+		if next.OK {
+			next.Reverify = ReverifyRecovered
+			next.FirstError = failureText(prev)
+			next.Source = prev.Source
+			out[idx] = next
+			continue
+		}
+		prev.Reverify = ReverifyReproduced
+		out[idx] = prev
+	}
+	return out, err
+}
+
+func failureText(r Result) string {
+	if r.Error != "" {
+		return r.Error
+	}
+	return "status " + strconv.Itoa(r.Status)
+}
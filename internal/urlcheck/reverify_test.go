@@ -0,0 +1,48 @@
+package urlcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReverifyAnnotatesFailures(t *testing.T) {
+	var flakyHits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/flaky":
+			if atomic.AddInt32(&flakyHits, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		case "/broken":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+	checker := NewChecker(4, time.Second, 0, server.Client())
+	urls := []string{server.URL + "/ok", server.URL + "/flaky", server.URL + "/broken"}
+	results, err := checker.Check(context.Background(), urls)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	results, err = checker.Reverify(context.Background(), results, 2*time.Second, 1)
+	if err != nil {
+		t.Fatalf("reverify: %v", err)
+	}
+	if results[0].Reverify != "" || !results[0].OK {
+		t.Fatalf("ok result must be untouched, got %+v", results[0])
+	}
+	if !results[1].OK || results[1].Reverify != ReverifyRecovered || results[1].FirstError != "status 503" {
+		t.Fatalf("expected flaky url to recover, got %+v", results[1])
+	}
+	if results[2].OK || results[2].Reverify != ReverifyReproduced {
+		t.Fatalf("expected broken url to reproduce, got %+v", results[2])
+	}
+}
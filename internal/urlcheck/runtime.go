@@ -0,0 +1,26 @@
+package urlcheck
+
+import "sync/atomic"
+
+type RuntimeStats struct {
+	Dispatched int64 `json:"dispatched"`
+	Completed  int64 `json:"completed"`
+	InFlight   int64 `json:"in_flight"`
+	Requests   int64 `json:"requests_in_flight"`
+}
+
+type runCounters struct {
+	dispatched atomic.Int64
+	completed  atomic.Int64
+	inFlight   atomic.Int64
+	requests   atomic.Int64
+}
+
+func (c *Checker) RuntimeStats() RuntimeStats {
+	return RuntimeStats{
+		Dispatched: c.counters.dispatched.Load(),
+		Completed:  c.counters.completed.Load(),
+		InFlight:   c.counters.inFlight.Load(),
+		Requests:   c.counters.requests.Load(),
+	}
+}
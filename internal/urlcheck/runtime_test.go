@@ -0,0 +1,36 @@
+package urlcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRuntimeStats(t *testing.T) {
+	release := make(chan struct{})
+	entered := make(chan struct{}, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entered <- struct{}{}
+		<-release
+	}))
+	defer srv.Close()
+	checker := NewChecker(2, 5*time.Second, 0, nil)
+	done := make(chan struct{})
+	go func() {
+		checker.Check(context.Background(), []string{srv.URL + "/a", srv.URL + "/b", srv.URL + "/c"})
+		close(done)
+	}()
+	<-entered
+	<-entered
+	s := checker.RuntimeStats()
+	if s.InFlight != 2 || s.Requests != 2 || s.Completed != 0 || s.Dispatched < 2 {
+		t.Fatalf("unexpected stats mid-run %+v", s)
+	}
+	close(release)
+	<-done
+	if s := checker.RuntimeStats(); s != (RuntimeStats{Dispatched: 3, Completed: 3}) {
+		t.Fatalf("unexpected stats after run %+v", s)
+	}
+}
@@ -0,0 +1,117 @@
+package urlcheck
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const minHSTSMaxAge = 180 * 24 * 60 * 60
+
+func WithSecurityAudit(fail bool) Option {
+	return func(c *Checker) {
+		c.securityAudit = true
+		c.securityFails = fail
+	}
+}
+
+func (c *Checker) auditSecurity(target string, resp *http.Response, res Result) Result {
+	if !c.securityAudit || resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return res
+	}
+	// The headers belong to the final hop, so its scheme decides whether HSTS
+	// applies: http:// redirected to https:// must send it, the reverse need not.
+	https := strings.HasPrefix(strings.ToLower(target), "https:")
+	if resp.Request != nil && resp.Request.URL != nil {
+		https = resp.Request.URL.Scheme == "https"
+	}
+	res.SecurityFindings = securityFindings(https, resp.Header)
+	if c.securityFails && len(res.SecurityFindings) > 0 && res.Error == "" {
+		res.OK = false
+		res.Error = "security headers: " + res.SecurityFindings[0]
+		res.ErrorKind = KindSecurityHeaders
+	}
+	return res
+}
+
+func securityFindings(https bool, h http.Header) []string {
+	var findings []string
+	if https {
+		findings = appendFinding(findings, "Strict-Transport-Security", h.Get("Strict-Transport-Security"), weakHSTS)
+	}
+	csp := h.Get("Content-Security-Policy")
+	findings = appendFinding(findings, "Content-Security-Policy", csp, weakCSP)
+	findings = appendFinding(findings, "X-Content-Type-Options", h.Get("X-Content-Type-Options"), func(v string) string {
+		if !strings.EqualFold(strings.TrimSpace(v), "nosniff") {
+			return "want nosniff"
+		}
+		return ""
+	})
+	if !strings.Contains(strings.ToLower(csp), "frame-ancestors") {
+		findings = appendFinding(findings, "X-Frame-Options", h.Get("X-Frame-Options"), func(v string) string {
+			switch strings.ToUpper(strings.TrimSpace(v)) {
+			case "DENY", "SAMEORIGIN":
+				return ""
+			}
+			return "want DENY or SAMEORIGIN"
+		})
+	}
+	findings = appendFinding(findings, "Referrer-Policy", h.Get("Referrer-Policy"), func(v string) string {
+		for _, policy := range strings.Split(v, ",") {
+			switch strings.ToLower(strings.TrimSpace(policy)) {
+			case "unsafe-url", "no-referrer-when-downgrade":
+				return "leaks full urls to other origins"
+			}
+		}
+		return ""
+	})
+	return findings
+}
+
+func appendFinding(findings []string, header, value string, weak func(string) string) []string {
+	if value == "" {
+		return append(findings, "missing "+header)
+	}
+	if why := weak(value); why != "" {
+		return append(findings, "weak "+header+": "+why)
+	}
+	return findings
+}
+
+func weakHSTS(v string) string {
+	for _, directive := range strings.Split(v, ";") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		if !strings.EqualFold(name, "max-age") {
+			continue
+		}
+		age, err := strconv.Atoi(strings.Trim(value, `"`))
+		if err != nil {
+			return "invalid max-age"
+		}
+		if age < minHSTSMaxAge {
+			return "max-age " + strconv.Itoa(age) + " is under 180 days"
+		}
+		return ""
+	}
+	return "no max-age"
+}
+
+func weakCSP(v string) string {
+	for _, directive := range strings.Split(v, ";") {
+		fields := strings.Fields(directive)
+		if len(fields) == 0 {
+			continue
+		}
+		name := strings.ToLower(fields[0])
+		if name != "default-src" && name != "script-src" {
+			continue
+		}
+		for _, source := range fields[1:] {
+			switch strings.ToLower(source) {
+			case "'unsafe-inline'", "'unsafe-eval'", "*":
+				return name + " allows " + source
+			}
+		}
+	}
+	return ""
+}
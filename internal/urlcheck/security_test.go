@@ -0,0 +1,93 @@
+package urlcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSecurityFindings(t *testing.T) {
+	strong := http.Header{}
+	strong.Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
+	strong.Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+	strong.Set("X-Content-Type-Options", "nosniff")
+	strong.Set("Referrer-Policy", "strict-origin-when-cross-origin")
+	if got := securityFindings(true, strong); len(got) != 0 {
+		t.Fatalf("expected no findings for strong headers, got %q", got)
+	}
+	if got := securityFindings(false, http.Header{}); slices.Contains(got, "missing Strict-Transport-Security") {
+		t.Fatalf("hsts should not be required over plain http: %q", got)
+	}
+	weak := http.Header{}
+	weak.Set("Strict-Transport-Security", "max-age=3600")
+	weak.Set("Content-Security-Policy", "default-src 'self'; script-src 'self' 'unsafe-inline'")
+	weak.Set("X-Content-Type-Options", "sniff")
+	weak.Set("X-Frame-Options", "ALLOW-FROM https://a.example")
+	weak.Set("Referrer-Policy", "unsafe-url")
+	want := []string{
+		"weak Strict-Transport-Security: max-age 3600 is under 180 days",
+		"weak Content-Security-Policy: script-src allows 'unsafe-inline'",
+		"weak X-Content-Type-Options: want nosniff",
+		"weak X-Frame-Options: want DENY or SAMEORIGIN",
+		"weak Referrer-Policy: leaks full urls to other origins",
+	}
+	if got := securityFindings(true, weak); !slices.Equal(got, want) {
+		t.Fatalf("got %q\nwant %q", got, want)
+	}
+	want = []string{
+		"missing Strict-Transport-Security",
+		"missing Content-Security-Policy",
+		"missing X-Content-Type-Options",
+		"missing X-Frame-Options",
+		"missing Referrer-Policy",
+	}
+	if got := securityFindings(true, http.Header{}); !slices.Equal(got, want) {
+		t.Fatalf("got %q\nwant %q", got, want)
+	}
+}
+
+func TestSecurityAuditModes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+	}))
+	defer server.Close()
+	urls := []string{server.URL + "/", server.URL + "/missing"}
+	results, err := NewChecker(1, time.Second, 0, server.Client(), WithSecurityAudit(false)).Check(context.Background(), urls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results[0].OK || len(results[0].SecurityFindings) != 3 {
+		t.Fatalf("warn mode should report findings without failing: %+v", results[0])
+	}
+	if results[1].SecurityFindings != nil {
+		t.Fatalf("error responses should not be audited: %+v", results[1])
+	}
+	results, err = NewChecker(1, time.Second, 0, server.Client(), WithSecurityAudit(true)).Check(context.Background(), urls[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := results[0]; r.OK || r.ErrorKind != KindSecurityHeaders || r.Error != "security headers: missing Content-Security-Policy" {
+		t.Fatalf("fail mode should fail the url: %+v", r)
+	}
+}
+
+func TestSecurityAuditUsesFinalScheme(t *testing.T) {
+	secure := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer secure.Close()
+	plain := httptest.NewServer(http.RedirectHandler(secure.URL+"/", http.StatusMovedPermanently))
+	defer plain.Close()
+	results, err := NewChecker(1, time.Second, 0, secure.Client(), WithSecurityAudit(false)).Check(context.Background(), []string{plain.URL + "/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(results[0].SecurityFindings, "missing Strict-Transport-Security") {
+		t.Fatalf("an https final hop should be checked for HSTS: %+v", results[0])
+	}
+}
@@ -0,0 +1,67 @@
+package urlcheck
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type Severity string
+
+const (
+	SeverityOK    Severity = "ok"
+	SeverityWarn  Severity = "warn"
+	SeverityError Severity = "error"
+)
+
+func WithCertExpiryWarning(d time.Duration) Option {
+	return func(c *Checker) {
+		c.certWarn = d
+	}
+}
+
+func WithWarningsAsErrors() Option {
+	return func(c *Checker) {
+		c.warnErrors = true
+	}
+}
+
+func (c *Checker) certWarning(resp *http.Response) string {
+	if c.certWarn <= 0 || resp.TLS == nil || len(resp.TLS.PeerCertificates) == 0 {
+		return ""
+	}
+	expires := resp.TLS.PeerCertificates[0].NotAfter
+	left := time.Until(expires)
+	if left > c.certWarn {
+		return ""
+	}
+	return fmt.Sprintf("certificate expires in %dd (%s)", int(left.Hours()/24), expires.UTC().Format("2006-01-02"))
+}
+
+func (c *Checker) grade(res Result) Result {
+	if res.SkipReason != "" {
+		return res
+	}
+	if !res.OK {
+		res.Severity = SeverityError
+		return res
+	}
+	if res.MovedTo != "" {
+		res.Warnings = append(res.Warnings, "permanent redirect to "+res.MovedTo)
+	}
+	if res.Slow {
+		res.Warnings = append(res.Warnings, fmt.Sprintf("slow: took %s, limit %s", res.Duration.Round(time.Millisecond), c.maxLatency))
+	}
+	switch {
+	case len(res.Warnings) == 0:
+		res.Severity = SeverityOK
+	case c.warnErrors:
+		res.OK = false
+		res.Error = "warning: " + res.Warnings[0]
+		res.ErrorKind = KindWarning
+		res.Severity = SeverityError
+	default:
+		res.Severity = SeverityWarn
+	}
+	return res
+}
@@ -0,0 +1,57 @@
+package urlcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGrade(t *testing.T) {
+	c := NewChecker(1, time.Second, 0, nil, WithMaxLatency(time.Second, false))
+	cases := []struct {
+		in       Result
+		severity Severity
+		warnings int
+	}{
+		{Result{OK: true, Status: 200}, SeverityOK, 0},
+		{Result{OK: false, Status: 500}, SeverityError, 0},
+		{Result{OK: true, Status: 200, MovedTo: "https://new.example/"}, SeverityWarn, 1},
+		{Result{OK: true, Status: 200, Slow: true, Duration: 2 * time.Second}, SeverityWarn, 1},
+		{Result{SkipReason: "excluded"}, "", 0},
+	}
+	for i, tc := range cases {
+		got := c.grade(tc.in)
+		if got.Severity != tc.severity || len(got.Warnings) != tc.warnings || got.OK != tc.in.OK {
+			t.Fatalf("case %d: unexpected grade %+v", i, got)
+		}
+	}
+	strict := NewChecker(1, time.Second, 0, nil, WithWarningsAsErrors())
+	got := strict.grade(Result{OK: true, Status: 200, MovedTo: "https://new.example/"})
+	if got.OK || got.Severity != SeverityError || got.ErrorKind != KindWarning || got.Error != "warning: permanent redirect to https://new.example/" {
+		t.Fatalf("expected warning to be escalated, got %+v", got)
+	}
+}
+
+func TestCertExpiryWarning(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	expires := server.Certificate().NotAfter
+	check := func(window time.Duration) Result {
+		checker := NewChecker(1, time.Second, 0, server.Client(), WithCertExpiryWarning(window))
+		results, err := checker.Check(context.Background(), []string{server.URL})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return results[0]
+	}
+	if r := check(24 * time.Hour); r.Severity != SeverityOK || len(r.Warnings) != 0 {
+		t.Fatalf("unexpected warning for a long-lived certificate %+v", r)
+	}
+	r := check(time.Until(expires) + 24*time.Hour)
+	if !r.OK || r.Severity != SeverityWarn || len(r.Warnings) != 1 || !strings.HasPrefix(r.Warnings[0], "certificate expires in ") || !strings.HasSuffix(r.Warnings[0], "("+expires.UTC().Format("2006-01-02")+")") {
+		t.Fatalf("expected an expiry warning, got %+v", r)
+	}
+}
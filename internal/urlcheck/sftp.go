@@ -0,0 +1,129 @@
+package urlcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+func WithSSHAuth(key ssh.Signer, hostKeys ssh.HostKeyCallback) Option {
+	return func(c *Checker) {
+		c.sshKey = key
+		c.hostKeys = hostKeys
+	}
+}
+
+func (c *Checker) checkSFTP(ctx context.Context, target string) Result {
+	res := Result{URL: target}
+	u, err := url.Parse(target)
+	if err != nil || u.Hostname() == "" || u.User.Username() == "" {
+		res.Error = "sftp url needs a user and host (sftp://user@host/path)"
+		res.ErrorKind = KindInvalidRequest
+		return res
+	}
+	config, err := c.sshConfig(u)
+	if err != nil {
+		res.Error = err.Error()
+		res.ErrorKind = KindInvalidRequest
+		return res
+	}
+	override := c.hostOverride(target)
+	retries := c.retriesFor(override)
+	for {
+		res.Attempts++
+		sftpCtx, cancel := context.WithTimeout(ctx, c.timeoutFor(override))
+		err := probeSFTP(sftpCtx, c.netDialer(), u, config)
+		cancel()
+		if err == nil {
+			res.OK = true
+			return res
+		}
+		res.Error = err.Error()
+		var remoteErr *remoteError
+		if errors.As(err, &remoteErr) {
+			res.ErrorKind = remoteErr.kind
+			return res
+		}
+		res.ErrorKind = classifyError(err)
+		if res.Attempts > retries || !c.shouldRetry(err) || ctx.Err() != nil || !c.spendRetry() {
+			return res
+		}
+	}
+}
+
+func (c *Checker) sshConfig(u *url.URL) (*ssh.ClientConfig, error) {
+	config := &ssh.ClientConfig{User: u.User.Username(), HostKeyCallback: c.hostKeys}
+	if c.sshKey != nil {
+		config.Auth = append(config.Auth, ssh.PublicKeys(c.sshKey))
+	}
+	if pass, ok := u.User.Password(); ok {
+		config.Auth = append(config.Auth, ssh.Password(pass))
+	}
+	if config.HostKeyCallback == nil {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		callback, err := knownhosts.New(filepath.Join(home, ".ssh", "known_hosts"))
+		if err != nil {
+			return nil, fmt.Errorf("sftp needs known hosts: %w", err)
+		}
+		config.HostKeyCallback = callback
+	}
+	return config, nil
+}
+
+func probeSFTP(ctx context.Context, d *net.Dialer, u *url.URL, config *ssh.ClientConfig) error {
+	port := u.Port()
+	if port == "" {
+		port = "22"
+	}
+	addr := net.JoinHostPort(u.Hostname(), port)
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		var keyErr *knownhosts.KeyError
+		if errors.As(err, &keyErr) || strings.Contains(err.Error(), "unable to authenticate") {
+			return &remoteError{kind: KindAuth, msg: err.Error()}
+		}
+		return err
+	}
+	client := ssh.NewClient(sshConn, chans, reqs)
+	defer client.Close()
+	sc, err := sftp.NewClient(client)
+	if err != nil {
+		return err
+	}
+	defer sc.Close()
+	path := u.Path
+	if path == "" {
+		path = "."
+	}
+	if _, err := sc.Stat(path); err != nil {
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			return &remoteError{kind: KindNotFound, msg: "sftp " + path + ": no such file or directory"}
+		case errors.Is(err, fs.ErrPermission):
+			return &remoteError{kind: KindAuth, msg: "sftp " + path + ": permission denied"}
+		}
+		return err
+	}
+	return nil
+}
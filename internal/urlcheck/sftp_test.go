@@ -0,0 +1,120 @@
+package urlcheck
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+func newSigner(t *testing.T) ssh.Signer {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+func fakeSFTP(t *testing.T, hostKey ssh.Signer, allowed ssh.PublicKey, root string) string {
+	t.Helper()
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if bytes.Equal(key.Marshal(), allowed.Marshal()) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("unknown key")
+		},
+	}
+	config.AddHostKey(hostKey)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { lis.Close() })
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, chans, reqs, err := ssh.NewServerConn(conn, config)
+				if err != nil {
+					return
+				}
+				go ssh.DiscardRequests(reqs)
+				for newChan := range chans {
+					ch, requests, err := newChan.Accept()
+					if err != nil {
+						return
+					}
+					go func() {
+						for req := range requests {
+							req.Reply(req.Type == "subsystem", nil)
+							if req.Type == "subsystem" {
+								server, _ := sftp.NewServer(ch, sftp.WithServerWorkingDirectory(root))
+								server.Serve()
+								ch.Close()
+							}
+						}
+					}()
+				}
+			}()
+		}
+	}()
+	return lis.Addr().String()
+}
+
+func TestCheckSFTP(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "release.tar.gz"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	hostKey, clientKey := newSigner(t), newSigner(t)
+	addr := fakeSFTP(t, hostKey, clientKey.PublicKey(), root)
+	checker := NewChecker(2, 2*time.Second, 0, nil, WithSSHAuth(clientKey, ssh.FixedHostKey(hostKey.PublicKey())))
+	results, err := checker.Check(context.Background(), []string{
+		"sftp://deploy@" + addr + filepath.Join(root, "release.tar.gz"),
+		"sftp://deploy@" + addr + filepath.Join(root, "gone.zip"),
+		"sftp://" + addr + "/",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r := results[0]; !r.OK || r.Attempts != 1 {
+		t.Fatalf("unexpected result %+v", r)
+	}
+	if r := results[1]; r.OK || r.ErrorKind != KindNotFound {
+		t.Fatalf("unexpected missing file result %+v", r)
+	}
+	if r := results[2]; r.OK || r.ErrorKind != KindInvalidRequest {
+		t.Fatalf("unexpected missing user result %+v", r)
+	}
+
+	wrongKey := NewChecker(1, 2*time.Second, 0, nil, WithSSHAuth(newSigner(t), ssh.FixedHostKey(hostKey.PublicKey())))
+	results, _ = wrongKey.Check(context.Background(), []string{"sftp://deploy@" + addr + "/"})
+	if r := results[0]; r.OK || r.ErrorKind != KindAuth {
+		t.Fatalf("unexpected auth result %+v", r)
+	}
+
+	wrongHost := NewChecker(1, 2*time.Second, 0, nil, WithSSHAuth(clientKey, ssh.FixedHostKey(newSigner(t).PublicKey())))
+	results, _ = wrongHost.Check(context.Background(), []string{"sftp://deploy@" + addr + "/"})
+	if r := results[0]; r.OK || r.Attempts != 1 {
+		t.Fatalf("unexpected host key result %+v", r)
+	}
+}
@@ -0,0 +1,128 @@
+package urlcheck
+
+import (
+	"bufio"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+type Metadata struct {
+	Label    string
+	Location *Location
+	Labels   map[string]string
+}
+
+type Source interface {
+	Next() (string, Metadata, error)
+}
+
+type SourceFunc func() (string, Metadata, error)
+
+func (f SourceFunc) Next() (string, Metadata, error) {
+	return f()
+}
+
+func SliceSource(label string, urls []string) Source {
+	i := 0
+	return SourceFunc(func() (string, Metadata, error) {
+		if i >= len(urls) {
+			return "", Metadata{}, io.EOF
+		}
+		i++
+		return urls[i-1], Metadata{Label: label}, nil
+	})
+}
+
+func LineSource(label string, r io.Reader) Source {
+	scanner := bufio.NewScanner(r)
+	return SourceFunc(func() (string, Metadata, error) {
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				return line, Metadata{Label: label}, nil
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return "", Metadata{}, err
+		}
+		return "", Metadata{}, io.EOF
+	})
+}
+
+func MultiSource(sources ...Source) Source {
+	return SourceFunc(func() (string, Metadata, error) {
+		for len(sources) > 0 {
+			u, meta, err := sources[0].Next()
+			if err == io.EOF {
+				sources = sources[1:]
+				continue
+			}
+			return u, meta, err
+		}
+		return "", Metadata{}, io.EOF
+	})
+}
+
+func SitemapSource(ctx context.Context, client *http.Client, sitemapURL string) (Source, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sitemapURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("sitemap %s: status %d", sitemapURL, resp.StatusCode)
+	}
+	var doc struct {
+		URLs []struct {
+			Loc string `xml:"loc"`
+		} `xml:"url"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("sitemap %s: %w", sitemapURL, err)
+	}
+	urls := make([]string, 0, len(doc.URLs))
+	for _, u := range doc.URLs {
+		if loc := strings.TrimSpace(u.Loc); loc != "" {
+			urls = append(urls, loc)
+		}
+	}
+	return SliceSource("sitemap:"+sitemapURL, urls), nil
+}
+
+func Collect(src Source) ([]string, []Metadata, error) {
+	var urls []string
+	var metas []Metadata
+	for {
+		u, meta, err := src.Next()
+		if err == io.EOF {
+			return urls, metas, nil
+		}
+		if err != nil {
+			return urls, metas, err
+		}
+		urls = append(urls, u)
+		metas = append(metas, meta)
+	}
+}
+
+func (c *Checker) CheckSource(ctx context.Context, src Source) ([]Result, error) {
+	urls, metas, err := Collect(src)
+	if err != nil {
+		return nil, err
+	}
+	results, err := c.Check(ctx, urls)
+	for i := range results {
+		results[i] = withMetadata(results[i], metas[i])
+	}
+	return results, err
+}
@@ -0,0 +1,67 @@
+package urlcheck
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMultiSourceKeepsLabels(t *testing.T) {
+	src := MultiSource(
+		LineSource("file:urls.txt", strings.NewReader("https://a.example\n\nhttps://b.example\n")),
+		SliceSource("seeds", nil),
+		SliceSource("api", []string{"https://c.example"}),
+	)
+	urls, metas, err := Collect(src)
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	if strings.Join(urls, " ") != "https://a.example https://b.example https://c.example" {
+		t.Fatalf("unexpected urls: %v", urls)
+	}
+	if metas[1].Label != "file:urls.txt" || metas[2].Label != "api" {
+		t.Fatalf("unexpected metadata: %+v", metas)
+	}
+}
+
+func TestCheckSourceAttributesResults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/sitemap.xml" {
+			w.Write([]byte(`<?xml version="1.0"?><urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"><url><loc>` +
+				"http://" + r.Host + `/page</loc></url></urlset>`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	sitemap, err := SitemapSource(context.Background(), server.Client(), server.URL+"/sitemap.xml")
+	if err != nil {
+		t.Fatalf("sitemap: %v", err)
+	}
+	loc := &Location{File: "README.md", Line: 3}
+	scanned := SourceFunc(func() func() (string, Metadata, error) {
+		done := false
+		return func() (string, Metadata, error) {
+			if done {
+				return "", Metadata{}, io.EOF
+			}
+			done = true
+			return server.URL + "/docs", Metadata{Label: "scan", Location: loc, Labels: map[string]string{"team": "docs"}}, nil
+		}
+	}())
+	checker := NewChecker(2, time.Second, 0, server.Client())
+	results, err := checker.CheckSource(context.Background(), MultiSource(sitemap, scanned))
+	if err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	if len(results) != 2 || !results[0].OK || results[0].Origin != "sitemap:"+server.URL+"/sitemap.xml" {
+		t.Fatalf("unexpected sitemap result: %+v", results)
+	}
+	if results[1].Origin != "scan" || results[1].Source != loc || results[1].Labels["team"] != "docs" {
+		t.Fatalf("unexpected scan result: %+v", results[1])
+	}
+}
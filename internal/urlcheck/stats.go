@@ -0,0 +1,159 @@
+package urlcheck
+
+import (
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+type Stats struct {
+	Summary
+	StatusClasses map[string]int       `json:"status_classes"`
+	ErrorKinds    map[ErrorKind]int    `json:"error_kinds,omitempty"`
+	Latency       Latency              `json:"latency"`
+	Histogram     []Bucket             `json:"histogram,omitempty"`
+	Hosts         map[string]HostStats `json:"hosts"`
+}
+
+type Latency struct {
+	Min  time.Duration `json:"min"`
+	Mean time.Duration `json:"mean"`
+	P50  time.Duration `json:"p50"`
+	P90  time.Duration `json:"p90"`
+	P95  time.Duration `json:"p95"`
+	P99  time.Duration `json:"p99"`
+	Max  time.Duration `json:"max"`
+}
+
+type HostStats struct {
+	Total     int            `json:"total"`
+	OK        int            `json:"ok"`
+	Failed    int            `json:"failed"`
+	ErrorRate float64        `json:"error_rate"`
+	Mean      time.Duration  `json:"mean"`
+	P50       time.Duration  `json:"p50"`
+	P95       time.Duration  `json:"p95"`
+	Errors    map[string]int `json:"errors,omitempty"`
+	Histogram []Bucket       `json:"histogram,omitempty"`
+}
+
+type Bucket struct {
+	Le    string `json:"le"`
+	Count int    `json:"count"`
+}
+
+var histogramBounds = []time.Duration{
+	10 * time.Millisecond, 25 * time.Millisecond, 50 * time.Millisecond,
+	100 * time.Millisecond, 250 * time.Millisecond, 500 * time.Millisecond,
+	time.Second, 2500 * time.Millisecond, 5 * time.Second, 10 * time.Second,
+}
+
+func ComputeStats(results []Result, elapsed time.Duration) Stats {
+	s := Stats{
+		Summary:       Summarize(results, elapsed),
+		StatusClasses: make(map[string]int),
+		Hosts:         make(map[string]HostStats),
+	}
+	var durations []time.Duration
+	hostDurations := make(map[string][]time.Duration)
+	for _, r := range results {
+		if r.SkipReason != "" {
+			continue
+		}
+		s.StatusClasses[statusClass(r.Status)]++
+		if r.ErrorKind != "" {
+			if s.ErrorKinds == nil {
+				s.ErrorKinds = make(map[ErrorKind]int)
+			}
+			s.ErrorKinds[r.ErrorKind]++
+		}
+		durations = append(durations, r.Duration)
+		host := statsHost(r.URL)
+		h := s.Hosts[host]
+		h.Total++
+		if r.OK {
+			h.OK++
+		} else {
+			h.Failed++
+			if h.Errors == nil {
+				h.Errors = make(map[string]int)
+			}
+			h.Errors[failureCause(r)]++
+		}
+		s.Hosts[host] = h
+		hostDurations[host] = append(hostDurations[host], r.Duration)
+	}
+	s.Latency = latencyOf(durations)
+	s.Histogram = histogramOf(durations)
+	for host, d := range hostDurations {
+		h := s.Hosts[host]
+		lat := latencyOf(d)
+		h.ErrorRate = float64(h.Failed) / float64(h.Total)
+		h.Mean, h.P50, h.P95 = lat.Mean, lat.P50, lat.P95
+		h.Histogram = histogramOf(d)
+		s.Hosts[host] = h
+	}
+	return s
+}
+
+func failureCause(r Result) string {
+	if r.ErrorKind != "" {
+		return string(r.ErrorKind)
+	}
+	if r.Status > 0 {
+		return "http_" + strconv.Itoa(r.Status)
+	}
+	return "other"
+}
+
+func statusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "none"
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
+
+func statsHost(target string) string {
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" {
+		return "unknown"
+	}
+	return u.Hostname()
+}
+
+func histogramOf(durations []time.Duration) []Bucket {
+	if len(durations) == 0 {
+		return nil
+	}
+	buckets := make([]Bucket, len(histogramBounds)+1)
+	for i, bound := range histogramBounds {
+		buckets[i].Le = bound.String()
+	}
+	buckets[len(histogramBounds)].Le = "+Inf"
+	for _, d := range durations {
+		i, _ := slices.BinarySearch(histogramBounds, d)
+		buckets[i].Count++
+	}
+	return buckets
+}
+
+func latencyOf(durations []time.Duration) Latency {
+	if len(durations) == 0 {
+		return Latency{}
+	}
+	slices.Sort(durations)
+	var sum time.Duration
+	for _, d := range durations {
+		sum += d
+	}
+	return Latency{
+		Min:  durations[0],
+		Mean: sum / time.Duration(len(durations)),
+		P50:  percentile(durations, 50),
+		P90:  percentile(durations, 90),
+		P95:  percentile(durations, 95),
+		P99:  percentile(durations, 99),
+		Max:  durations[len(durations)-1],
+	}
+}
@@ -0,0 +1,77 @@
+package urlcheck
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeStatsBreakdowns(t *testing.T) {
+	results := []Result{
+		{URL: "https://a.example/1", OK: true, Status: 200, Duration: 10 * time.Millisecond},
+		{URL: "https://a.example/2", OK: true, Status: 301, Duration: 20 * time.Millisecond},
+		{URL: "https://a.example/3", Status: 404, ErrorKind: KindForbiddenContent, Duration: 30 * time.Millisecond},
+		{URL: "https://b.example:8443/", Error: "timeout", ErrorKind: KindTimeout, Duration: 100 * time.Millisecond},
+		{URL: "https://c.example/", SkipReason: "excluded"},
+	}
+	s := ComputeStats(results, time.Second)
+	if s.Total != 5 || s.OK != 2 || s.Broken != 1 || s.Errored != 1 || s.Skipped != 1 {
+		t.Fatalf("unexpected summary: %+v", s.Summary)
+	}
+	if s.StatusClasses["2xx"] != 1 || s.StatusClasses["3xx"] != 1 || s.StatusClasses["4xx"] != 1 || s.StatusClasses["none"] != 1 {
+		t.Fatalf("unexpected status classes: %v", s.StatusClasses)
+	}
+	if s.ErrorKinds[KindForbiddenContent] != 1 || s.ErrorKinds[KindTimeout] != 1 {
+		t.Fatalf("unexpected error kinds: %v", s.ErrorKinds)
+	}
+	if s.Latency.Min != 10*time.Millisecond || s.Latency.Max != 100*time.Millisecond || s.Latency.Mean != 40*time.Millisecond {
+		t.Fatalf("unexpected latency: %+v", s.Latency)
+	}
+	if len(s.Histogram) != len(histogramBounds)+1 || s.Histogram[0] != (Bucket{Le: "10ms", Count: 1}) || s.Histogram[1].Count != 1 || s.Histogram[2].Count != 1 || s.Histogram[3] != (Bucket{Le: "100ms", Count: 1}) {
+		t.Fatalf("unexpected histogram: %+v", s.Histogram)
+	}
+	a := s.Hosts["a.example"]
+	if a.Total != 3 || a.OK != 2 || a.Failed != 1 || a.P50 != 20*time.Millisecond || a.P95 != 30*time.Millisecond ||
+		a.Mean != 20*time.Millisecond || a.ErrorRate != 1.0/3 || a.Errors["forbidden_content"] != 1 {
+		t.Fatalf("unexpected host stats: %+v", a)
+	}
+	if b := s.Hosts["b.example"]; b.Total != 1 || b.Failed != 1 || b.Histogram[3].Count != 1 {
+		t.Fatalf("unexpected host stats: %+v", b)
+	}
+	if _, ok := s.Hosts["c.example"]; ok {
+		t.Fatal("skipped url counted in host stats")
+	}
+}
+
+func TestComputeStatsEmpty(t *testing.T) {
+	s := ComputeStats(nil, 0)
+	if s.Total != 0 || s.Latency != (Latency{}) || len(s.Hosts) != 0 || s.ErrorKinds != nil || s.Histogram != nil {
+		t.Fatalf("unexpected empty stats: %+v", s)
+	}
+}
+
+func TestHistogramOfBounds(t *testing.T) {
+	h := histogramOf([]time.Duration{0, 10 * time.Millisecond, 11 * time.Millisecond, 2500 * time.Millisecond, time.Minute})
+	want := map[string]int{"10ms": 2, "25ms": 1, "2.5s": 1, "+Inf": 1}
+	total := 0
+	for _, b := range h {
+		if b.Count != want[b.Le] {
+			t.Fatalf("bucket %s: expected %d, got %d", b.Le, want[b.Le], b.Count)
+		}
+		total += b.Count
+	}
+	if total != 5 || h[len(h)-1].Le != "+Inf" {
+		t.Fatalf("unexpected histogram: %+v", h)
+	}
+}
+
+func TestFailureCause(t *testing.T) {
+	for want, r := range map[string]Result{
+		"timeout":  {ErrorKind: KindTimeout, Status: 0},
+		"http_404": {Status: 404},
+		"other":    {Error: "boom"},
+	} {
+		if got := failureCause(r); got != want {
+			t.Fatalf("failureCause(%+v) = %q, want %q", r, got, want)
+		}
+	}
+}
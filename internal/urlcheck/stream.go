@@ -0,0 +1,76 @@
+package urlcheck
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+)
+
+func (c *Checker) CheckStream(ctx context.Context, src Source, emit func(Result)) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var mu sync.Mutex
+	type pending struct {
+		url  string
+		meta Metadata
+	}
+	inflight := make(map[int]pending)
+	var srcErr error
+	seq := 0
+	dispatched, pulled, reason := c.dispatch(ctx, func() (string, bool) {
+		u, meta, err := src.Next()
+		if err != nil {
+			if err != io.EOF {
+				srcErr = err
+			}
+			return "", false
+		}
+		mu.Lock()
+		inflight[seq] = pending{url: u, meta: meta}
+		mu.Unlock()
+		seq++
+		return u, true
+	}, func(n int, res Result) {
+		mu.Lock()
+		p := inflight[n]
+		delete(inflight, n)
+		mu.Unlock()
+		emit(withMetadata(res, p.meta))
+	})
+	if srcErr != nil {
+		return srcErr
+	}
+	if reason != "" {
+		if pulled > dispatched {
+			p := inflight[dispatched]
+			emit(withMetadata(notAttempted(p.url, reason), p.meta))
+		}
+		for {
+			u, meta, err := src.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			emit(withMetadata(notAttempted(u, reason), meta))
+		}
+	}
+	if err := ctx.Err(); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
+
+func withMetadata(res Result, meta Metadata) Result {
+	res.Origin = meta.Label
+	if res.Source == nil {
+		res.Source = meta.Location
+	}
+	if res.Labels == nil {
+		res.Labels = meta.Labels
+	}
+	return res
+}
@@ -0,0 +1,90 @@
+package urlcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCheckStreamReadsLazily(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	const n = 500
+	var read atomic.Int64
+	src := SourceFunc(func() (string, Metadata, error) {
+		if read.Load() == n {
+			return "", Metadata{}, io.EOF
+		}
+		return fmt.Sprintf("%s/%d", server.URL, read.Add(1)), Metadata{Label: "gen"}, nil
+	})
+	checker := NewChecker(4, time.Second, 0, server.Client())
+	emitted := 0
+	err := checker.CheckStream(context.Background(), src, func(r Result) {
+		emitted++
+		if !r.OK || r.Origin != "gen" {
+			t.Errorf("unexpected result %+v", r)
+		}
+		if ahead := int(read.Load()) - emitted; ahead > 2*4+1 {
+			t.Errorf("source read %d urls ahead of emitted results", ahead)
+		}
+	})
+	if err != nil {
+		t.Fatalf("CheckStream: %v", err)
+	}
+	if emitted != n {
+		t.Fatalf("emitted %d results, want %d", emitted, n)
+	}
+}
+
+func TestCheckStreamBudgetDrainsSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(40 * time.Millisecond)
+	}))
+	defer server.Close()
+	var lines []string
+	for i := 0; i < 20; i++ {
+		lines = append(lines, fmt.Sprintf("%s/%d", server.URL, i))
+	}
+	checker := NewChecker(1, time.Second, 0, server.Client(), WithBudget(100*time.Millisecond))
+	var results []Result
+	err := checker.CheckStream(context.Background(), LineSource("stdin", strings.NewReader(strings.Join(lines, "\n"))), func(r Result) {
+		results = append(results, r)
+	})
+	if err != nil {
+		t.Fatalf("CheckStream: %v", err)
+	}
+	if len(results) != len(lines) {
+		t.Fatalf("got %d results, want %d", len(results), len(lines))
+	}
+	seen := map[string]bool{}
+	for _, r := range results {
+		seen[r.URL] = true
+	}
+	if len(seen) != len(lines) {
+		t.Fatalf("results do not cover every input url: %v", seen)
+	}
+	attempted, total := Coverage(results)
+	if total != len(lines) || attempted == 0 || attempted >= total {
+		t.Fatalf("unexpected coverage %d/%d", attempted, total)
+	}
+}
+
+func TestCheckStreamSourceError(t *testing.T) {
+	boom := errors.New("boom")
+	src := SourceFunc(func() (string, Metadata, error) {
+		return "", Metadata{}, boom
+	})
+	err := NewChecker(1, time.Second, 0, nil).CheckStream(context.Background(), src, func(Result) {})
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected source error, got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package urlcheck
+
+import (
+	"sort"
+	"time"
+)
+
+type Summary struct {
+	Total         int           `json:"total"`
+	OK            int           `json:"ok"`
+	Broken        int           `json:"broken"`
+	Errored       int           `json:"errored"`
+	Skipped       int           `json:"skipped"`
+	Warnings      int           `json:"warnings,omitempty"`
+	P50           time.Duration `json:"p50"`
+	P95           time.Duration `json:"p95"`
+	TotalDuration time.Duration `json:"total_duration,omitempty"`
+	Partial       bool          `json:"partial,omitempty"`
+}
+
+func Summarize(results []Result, elapsed time.Duration) Summary {
+	s := Summary{Total: len(results), TotalDuration: elapsed}
+	var durations []time.Duration
+	for _, r := range results {
+		if r.ErrorKind == KindNotAttempted {
+			s.Partial = true
+		}
+		switch {
+		case r.SkipReason != "":
+			s.Skipped++
+			continue
+		case r.OK:
+			s.OK++
+			if r.Severity == SeverityWarn {
+				s.Warnings++
+			}
+		case r.Status != 0:
+			s.Broken++
+		default:
+			s.Errored++
+		}
+		durations = append(durations, r.Duration)
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	s.P50 = percentile(durations, 50)
+	s.P95 = percentile(durations, 95)
+	return s
+}
+
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,31 @@
+package urlcheck
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummarizeCountsAndPercentiles(t *testing.T) {
+	var results []Result
+	for i := 1; i <= 20; i++ {
+		results = append(results, Result{OK: true, Status: 200, Duration: time.Duration(i) * time.Millisecond})
+	}
+	results = append(results,
+		Result{Status: 404, Duration: 100 * time.Millisecond},
+		Result{Error: "refused", Duration: 200 * time.Millisecond},
+		Result{SkipReason: "excluded"},
+	)
+	s := Summarize(results, 3*time.Second)
+	if s.Total != 23 || s.OK != 20 || s.Broken != 1 || s.Errored != 1 || s.Skipped != 1 {
+		t.Fatalf("unexpected counts: %+v", s)
+	}
+	if s.P50 != 11*time.Millisecond || s.P95 != 100*time.Millisecond {
+		t.Fatalf("unexpected percentiles: p50=%v p95=%v", s.P50, s.P95)
+	}
+	if s.TotalDuration != 3*time.Second {
+		t.Fatalf("unexpected total duration: %v", s.TotalDuration)
+	}
+	if empty := Summarize(nil, 0); empty.P50 != 0 || empty.Total != 0 {
+		t.Fatalf("unexpected empty summary: %+v", empty)
+	}
+}
@@ -0,0 +1,51 @@
+package urlcheck
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func WithTracer(t trace.Tracer) Option {
+	return func(c *Checker) {
+		c.tracer = t
+	}
+}
+
+func (c *Checker) startSpan(ctx context.Context, url string) (context.Context, trace.Span) {
+	if c.tracer == nil {
+		return ctx, nil
+	}
+	return c.tracer.Start(ctx, "urlcheck.check",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("url.full", url)))
+}
+
+func endSpan(span trace.Span, res Result) {
+	if span == nil {
+		return
+	}
+	span.SetAttributes(
+		attribute.Bool("urlcheck.ok", res.OK),
+		attribute.Int("urlcheck.attempts", res.Attempts),
+		attribute.Int64("urlcheck.duration_ms", res.Duration.Milliseconds()),
+	)
+	if res.Status != 0 {
+		span.SetAttributes(attribute.Int("http.response.status_code", res.Status))
+	}
+	if res.ErrorKind != "" {
+		span.SetAttributes(attribute.String("urlcheck.error_kind", string(res.ErrorKind)))
+	}
+	if !res.OK {
+		span.SetStatus(codes.Error, res.Error)
+	}
+	span.End()
+}
+
+func retryEvent(ctx context.Context, attempt int, err error) {
+	trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(
+		attribute.Int("urlcheck.attempt", attempt),
+		attribute.String("error.message", err.Error())))
+}
@@ -0,0 +1,45 @@
+package urlcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestWithTracerRecordsSpanPerURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	c := NewChecker(2, time.Second, 0, nil, WithTracer(tp.Tracer("test")))
+	if _, err := c.Check(context.Background(), []string{server.URL + "/ok", server.URL + "/broken"}); err != nil {
+		t.Fatal(err)
+	}
+	spans := rec.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	for _, s := range spans {
+		attrs := make(map[string]any)
+		for _, kv := range s.Attributes() {
+			attrs[string(kv.Key)] = kv.Value.AsInterface()
+		}
+		broken := attrs["url.full"] == server.URL+"/broken"
+		if s.Name() != "urlcheck.check" || attrs["urlcheck.attempts"] != int64(1) {
+			t.Fatalf("unexpected span %s: %v", s.Name(), attrs)
+		}
+		if broken != (s.Status().Code == codes.Error) || (broken && attrs["http.response.status_code"] != int64(404)) {
+			t.Fatalf("unexpected status for %v: %v", attrs["url.full"], s.Status())
+		}
+	}
+}
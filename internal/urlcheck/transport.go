@@ -0,0 +1,44 @@
+package urlcheck
+
+import (
+	"net/http"
+	"time"
+)
+
+type TransportLimits struct {
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int
+	IdleConnTimeout     time.Duration
+	TLSHandshakeTimeout time.Duration
+}
+
+func WithTransportLimits(l TransportLimits) Option {
+	return func(c *Checker) {
+		c.tuneTransport(func(t *http.Transport) {
+			if l.MaxIdleConns > 0 {
+				t.MaxIdleConns = l.MaxIdleConns
+			}
+			if l.MaxIdleConnsPerHost > 0 {
+				t.MaxIdleConnsPerHost = l.MaxIdleConnsPerHost
+			}
+			if l.MaxConnsPerHost > 0 {
+				t.MaxConnsPerHost = l.MaxConnsPerHost
+			}
+			if l.IdleConnTimeout > 0 {
+				t.IdleConnTimeout = l.IdleConnTimeout
+			}
+			if l.TLSHandshakeTimeout > 0 {
+				t.TLSHandshakeTimeout = l.TLSHandshakeTimeout
+			}
+		})
+	}
+}
+
+func WithoutKeepAlives() Option {
+	return func(c *Checker) {
+		c.tuneTransport(func(t *http.Transport) {
+			t.DisableKeepAlives = true
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package urlcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTransportLimitsApplyToClonedTransport(t *testing.T) {
+	base := &http.Transport{MaxIdleConns: 7, IdleConnTimeout: time.Minute}
+	client := &http.Client{Transport: base}
+	c := NewChecker(1, time.Second, 0, client, WithTransportLimits(TransportLimits{
+		MaxIdleConnsPerHost: 32,
+		MaxConnsPerHost:     4,
+		TLSHandshakeTimeout: 3 * time.Second,
+	}))
+	tr, ok := c.client.Transport.(*http.Transport)
+	if !ok || tr == base {
+		t.Fatalf("expected a tuned clone of the transport, got %T", c.client.Transport)
+	}
+	if tr.MaxIdleConns != 7 || tr.IdleConnTimeout != time.Minute {
+		t.Fatalf("unset limits should keep the base values, got %d %v", tr.MaxIdleConns, tr.IdleConnTimeout)
+	}
+	if tr.MaxIdleConnsPerHost != 32 || tr.MaxConnsPerHost != 4 || tr.TLSHandshakeTimeout != 3*time.Second {
+		t.Fatalf("limits not applied: %+v", tr)
+	}
+	if client.Transport != base {
+		t.Fatal("the caller's client must not be modified")
+	}
+}
+
+func TestMaxConnsPerHostBoundsConcurrency(t *testing.T) {
+	var active, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := active.Add(1)
+		defer active.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer server.Close()
+	c := NewChecker(6, time.Second, 0, &http.Client{Transport: &http.Transport{}}, WithTransportLimits(TransportLimits{MaxConnsPerHost: 2}))
+	urls := make([]string, 6)
+	for i := range urls {
+		urls[i] = server.URL
+	}
+	if _, err := c.Check(context.Background(), urls); err != nil {
+		t.Fatal(err)
+	}
+	if p := peak.Load(); p > 2 {
+		t.Fatalf("expected at most 2 concurrent connections, saw %d", p)
+	}
+}
+
+func TestWithoutKeepAlivesOpensFreshConnections(t *testing.T) {
+	addrs := make(chan string, 3)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		addrs <- r.RemoteAddr
+	}))
+	defer server.Close()
+	for _, tc := range []struct {
+		opts []Option
+		want int
+	}{
+		{nil, 1},
+		{[]Option{WithoutKeepAlives()}, 3},
+	} {
+		c := NewChecker(1, time.Second, 0, &http.Client{Transport: &http.Transport{}}, tc.opts...)
+		if _, err := c.Check(context.Background(), []string{server.URL + "/a", server.URL + "/b", server.URL + "/c"}); err != nil {
+			t.Fatal(err)
+		}
+		seen := map[string]bool{}
+		for range 3 {
+			seen[<-addrs] = true
+		}
+		if len(seen) != tc.want {
+			t.Fatalf("expected %d distinct connections, got %d", tc.want, len(seen))
+		}
+	}
+}
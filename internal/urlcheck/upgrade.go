@@ -0,0 +1,76 @@
+package urlcheck
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	UpgradeRedirects   = "redirects"
+	UpgradeAvailable   = "available"
+	UpgradeUnavailable = "unavailable"
+)
+
+func WithHTTPSUpgradeCheck() Option {
+	return func(c *Checker) {
+		c.httpsUpgrade = true
+	}
+}
+
+func (c *Checker) checkHTTPSUpgrade(ctx context.Context, target string, res Result) Result {
+	if !c.httpsUpgrade || res.SkipReason != "" || !strings.HasPrefix(strings.ToLower(target), "http://") {
+		return res
+	}
+	override := c.hostOverride(target)
+	client := *c.client
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	if resp, err := c.upgradeProbe(ctx, &client, target, override); err == nil && isRedirect(resp.StatusCode) {
+		if next, err := resolveLocation(target, resp.Header.Get("Location")); err == nil && strings.HasPrefix(next, "https://") {
+			res.HTTPSUpgrade = UpgradeRedirects
+			return res
+		}
+	}
+	secure, err := url.Parse(target)
+	if err != nil {
+		return res
+	}
+	secure.Scheme = "https"
+	if secure.Port() == "80" {
+		secure.Host = secure.Hostname()
+	}
+	resp, err := c.upgradeProbe(ctx, c.client, secure.String(), override)
+	switch {
+	case err != nil:
+		res.HTTPSUpgrade = UpgradeUnavailable + ": " + err.Error()
+	case resp.StatusCode >= 400:
+		res.HTTPSUpgrade = UpgradeUnavailable + ": status " + strconv.Itoa(resp.StatusCode)
+	default:
+		res.HTTPSUpgrade = UpgradeAvailable
+	}
+	return res
+}
+
+func (c *Checker) upgradeProbe(ctx context.Context, client *http.Client, target string, override *HostOverride) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeoutFor(override))
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	if override != nil {
+		for k, v := range override.Headers {
+			req.Header.Set(k, v)
+		}
+	}
+	resp, err := c.do(client, req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
@@ -0,0 +1,71 @@
+package urlcheck
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type upgradeTransport struct{}
+
+func (upgradeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader("")), Request: req}
+	switch req.URL.Scheme + "://" + req.URL.Host {
+	case "http://redirect.example":
+		resp.StatusCode = http.StatusMovedPermanently
+		resp.Header.Set("Location", "https://redirect.example"+req.URL.Path)
+	case "https://plain.example":
+		return nil, errors.New("connection refused")
+	case "https://broken.example":
+		resp.StatusCode = http.StatusNotFound
+	}
+	return resp, nil
+}
+
+func TestCheckHTTPSUpgrade(t *testing.T) {
+	client := &http.Client{Transport: upgradeTransport{}}
+	checker := NewChecker(2, time.Second, 0, client, WithHTTPSUpgradeCheck())
+	results, err := checker.Check(context.Background(), []string{
+		"http://redirect.example/docs",
+		"http://secure.example:80/",
+		"http://plain.example/",
+		"http://broken.example/",
+		"https://secure.example/",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{UpgradeRedirects, UpgradeAvailable, "unavailable: ", "unavailable: status 404", ""}
+	for i, w := range want {
+		if got := results[i].HTTPSUpgrade; !strings.HasPrefix(got, w) || (w == "" && got != "") {
+			t.Fatalf("result %d: https upgrade %q, want %q", i, got, w)
+		}
+	}
+	if !results[2].OK {
+		t.Fatalf("a missing https upgrade should not fail the url: %+v", results[2])
+	}
+	if got := NewChecker(1, time.Second, 0, client).checkHTTPSUpgrade(context.Background(), "http://secure.example/", Result{}); got.HTTPSUpgrade != "" {
+		t.Fatalf("upgrade probe ran without the option: %+v", got)
+	}
+}
+
+type slowHTTPSTransport struct{}
+
+func (slowHTTPSTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme == "https" {
+		time.Sleep(200 * time.Millisecond)
+	}
+	return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+}
+
+func TestHTTPSUpgradeProbeNotCountedInDuration(t *testing.T) {
+	checker := NewChecker(1, time.Second, 0, &http.Client{Transport: slowHTTPSTransport{}}, WithHTTPSUpgradeCheck(), WithMaxLatency(100*time.Millisecond, true))
+	results, _ := checker.Check(context.Background(), []string{"http://plain.example/"})
+	if r := results[0]; !r.OK || r.HTTPSUpgrade != UpgradeAvailable || r.Duration >= 100*time.Millisecond {
+		t.Fatalf("the https probe leaked into the url's latency: %+v", r)
+	}
+}
@@ -0,0 +1,24 @@
+package urlcheck
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+)
+
+type Validator func(*http.Response, error) (ok bool, reason string)
+
+func WithValidator(v Validator) Option {
+	return func(c *Checker) {
+		c.validator = v
+	}
+}
+
+func (c *Checker) validateResponse(resp *http.Response, body []byte) (bool, string) {
+	if c.validator == nil {
+		return resp.StatusCode >= 200 && resp.StatusCode < 400, ""
+	}
+	view := *resp
+	view.Body = io.NopCloser(bytes.NewReader(body))
+	return c.validator(&view, nil)
+}
@@ -0,0 +1,98 @@
+package urlcheck
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestValidatorReplacesStatusCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/up":
+			w.Write([]byte(`{"status":"up"}`))
+		case "/degraded":
+			w.Write([]byte(`{"status":"degraded"}`))
+		case "/teapot":
+			w.WriteHeader(http.StatusTeapot)
+			w.Write([]byte(`{"status":"up"}`))
+		}
+	}))
+	defer server.Close()
+	validator := func(resp *http.Response, err error) (bool, string) {
+		if err != nil {
+			return false, "unreachable: " + err.Error()
+		}
+		var doc struct{ Status string }
+		if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+			return false, "invalid json"
+		}
+		if doc.Status != "up" {
+			return false, "status is " + doc.Status
+		}
+		return true, ""
+	}
+	checker := NewChecker(2, time.Second, 0, server.Client(), WithValidator(validator))
+	results, err := checker.Check(context.Background(), []string{server.URL + "/up", server.URL + "/degraded", server.URL + "/teapot", "http://127.0.0.1:1/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results[0].OK || !results[2].OK {
+		t.Fatalf("expected the validator to decide success regardless of status: %+v %+v", results[0], results[2])
+	}
+	if r := results[1]; r.OK || r.Error != "status is degraded" || r.ErrorKind != KindValidation {
+		t.Fatalf("unexpected degraded result %+v", r)
+	}
+	if r := results[3]; r.OK || r.ErrorKind != KindValidation {
+		t.Fatalf("expected the validator to describe transport errors, got %+v", r)
+	}
+}
+
+func TestValidatorCanAcceptErrors(t *testing.T) {
+	checker := NewChecker(1, time.Second, 0, nil, WithValidator(func(resp *http.Response, err error) (bool, string) {
+		return err != nil, ""
+	}))
+	results, err := checker.Check(context.Background(), []string{"http://127.0.0.1:1/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results[0].OK || results[0].Error != "" {
+		t.Fatalf("expected an accepted error to be ok, got %+v", results[0])
+	}
+}
+
+func TestStatusAssertionCannotOverrideValidator(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/degraded" {
+			w.Write([]byte(`{"status":"degraded"}`))
+			return
+		}
+		w.Write([]byte(`{"status":"up"}`))
+	}))
+	defer server.Close()
+	validator := func(resp *http.Response, err error) (bool, string) {
+		var doc struct{ Status string }
+		if err != nil || json.NewDecoder(resp.Body).Decode(&doc) != nil || doc.Status != "up" {
+			return false, "status is " + doc.Status
+		}
+		return true, ""
+	}
+	assertions, err := ParseAssertions([]byte(`[{"pattern":"/degraded$","status":200},{"pattern":"/up$","status":201}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checker := NewChecker(1, time.Second, 0, server.Client(), WithValidator(validator), WithAssertions(assertions...))
+	results, err := checker.Check(context.Background(), []string{server.URL + "/degraded", server.URL + "/up"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := results[0]; r.OK || r.ErrorKind != KindValidation || r.Error != "status is degraded" {
+		t.Fatalf("a passing status assertion overrode the validator: %+v", r)
+	}
+	if r := results[1]; r.OK || r.ErrorKind != KindAssertion {
+		t.Fatalf("a failing status assertion should still fail the url: %+v", r)
+	}
+}
@@ -0,0 +1,50 @@
+package urlcheck
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+)
+
+const vhostProbeHost = "urlcheck-vhost-probe.invalid"
+
+const (
+	VhostRejected         = "rejected"
+	VhostSameContent      = "same_content"
+	VhostDifferentContent = "different_content"
+)
+
+func WithVhostAudit() Option {
+	return func(c *Checker) {
+		c.vhostAudit = true
+	}
+}
+
+func (c *Checker) probeVhost(ctx context.Context, client *http.Client, target string, body []byte) string {
+	reqCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, target, nil)
+	if err != nil {
+		return VhostRejected
+	}
+	req.Host = vhostProbeHost
+	probe := *client
+	probe.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	resp, err := probe.Do(req)
+	if err != nil {
+		return VhostRejected
+	}
+	defer resp.Body.Close()
+	probeBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxInspectBytes))
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return VhostRejected
+	}
+	if bytes.Equal(probeBody, body) {
+		return VhostSameContent
+	}
+	return VhostDifferentContent
+}
@@ -0,0 +1,38 @@
+package urlcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestVhostAudit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/strict" && r.Host == vhostProbeHost:
+			w.WriteHeader(http.StatusMisdirectedRequest)
+		case r.URL.Path == "/default" && r.Host == vhostProbeHost:
+			w.Write([]byte("default site"))
+		default:
+			w.Write([]byte("real site"))
+		}
+	}))
+	defer server.Close()
+	checker := NewChecker(1, time.Second, 0, server.Client(), WithVhostAudit())
+	urls := []string{server.URL + "/strict", server.URL + "/default", server.URL + "/same"}
+	results, err := checker.Check(context.Background(), urls)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{VhostRejected, VhostDifferentContent, VhostSameContent}
+	for i, w := range want {
+		if results[i].VhostProbe != w {
+			t.Fatalf("%s: expected %s, got %+v", urls[i], w, results[i])
+		}
+		if !results[i].OK {
+			t.Fatalf("audit must not change ok, got %+v", results[i])
+		}
+	}
+}
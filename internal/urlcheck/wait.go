@@ -0,0 +1,61 @@
+package urlcheck
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const defaultPollInterval = 250 * time.Millisecond
+
+func WithPollInterval(d time.Duration) Option {
+	return func(c *Checker) {
+		c.pollInterval = d
+	}
+}
+
+func WaitUntilHealthy(ctx context.Context, urls []string, opts ...Option) error {
+	c := NewChecker(len(urls), 0, 0, nil, opts...)
+	interval := c.pollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	pending := urls
+	var failing []Result
+	for {
+		results, _ := c.Check(ctx, pending)
+		if ctx.Err() != nil && failing != nil {
+			return unhealthyError(ctx.Err(), failing)
+		}
+		var next []string
+		failing = nil
+		for i, r := range results {
+			if r.OK {
+				continue
+			}
+			if r.URL == "" {
+				r = Result{URL: pending[i], Error: "not checked"}
+			}
+			next = append(next, pending[i])
+			failing = append(failing, r)
+		}
+		if len(next) == 0 {
+			return nil
+		}
+		pending = next
+		select {
+		case <-ctx.Done():
+			return unhealthyError(ctx.Err(), failing)
+		case <-time.After(interval):
+		}
+	}
+}
+
+func unhealthyError(cause error, results []Result) error {
+	parts := make([]string, 0, len(results))
+	for _, r := range results {
+		parts = append(parts, r.URL+" ("+failureText(r)+")")
+	}
+	return fmt.Errorf("%w: still unhealthy: %s", cause, strings.Join(parts, ", "))
+}
@@ -0,0 +1,89 @@
+package urlcheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const DefaultArchiveEndpoint = "https://archive.org/wayback/available"
+
+// archiveInterval spaces out availability lookups so a run full of dead
+// links does not hammer archive.org.
+const archiveInterval = 200 * time.Millisecond
+
+type archiveAvailability struct {
+	ArchivedSnapshots struct {
+		Closest *struct {
+			Available bool   `json:"available"`
+			URL       string `json:"url"`
+			Status    string `json:"status"`
+		} `json:"closest"`
+	} `json:"archived_snapshots"`
+}
+
+func WithArchiveLookup(endpoint string) Option {
+	return func(c *Checker) {
+		if endpoint == "" {
+			endpoint = DefaultArchiveEndpoint
+		}
+		c.archive = endpoint
+		c.archivePacer = &hostPacer{delay: archiveInterval, next: make(map[string]time.Time)}
+	}
+}
+
+type archiveLookup struct {
+	once     sync.Once
+	snapshot string
+	err      error
+}
+
+func (c *Checker) suggestArchive(ctx context.Context, target string, res Result, run *runState) Result {
+	if c.archive == "" || res.OK || res.SkipReason != "" || res.ErrorKind == KindNotAttempted || ctx.Err() != nil {
+		return res
+	}
+	v, _ := run.archive.LoadOrStore(target, &archiveLookup{})
+	lookup := v.(*archiveLookup)
+	lookup.once.Do(func() {
+		lookup.snapshot, lookup.err = c.lookupArchive(ctx, target)
+	})
+	if lookup.err != nil {
+		c.debug(ctx, "archive lookup failed", "url", target, "error", lookup.err)
+		return res
+	}
+	res.ArchiveURL = lookup.snapshot
+	return res
+}
+
+func (c *Checker) lookupArchive(ctx context.Context, target string) (string, error) {
+	if err := c.archivePacer.wait(ctx, c.archive); err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.archive+"?url="+url.QueryEscape(target), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("archive returned %s", resp.Status)
+	}
+	var avail archiveAvailability
+	if err := json.NewDecoder(resp.Body).Decode(&avail); err != nil {
+		return "", err
+	}
+	closest := avail.ArchivedSnapshots.Closest
+	if closest == nil || !closest.Available || closest.URL == "" {
+		return "", nil
+	}
+	return closest.URL, nil
+}
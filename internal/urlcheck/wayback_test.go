@@ -0,0 +1,99 @@
+package urlcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestArchiveLookupForDeadURLs(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ok" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer target.Close()
+	var lookups atomic.Int32
+	archive := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups.Add(1)
+		if strings.HasSuffix(r.URL.Query().Get("url"), "/archived") {
+			fmt.Fprintf(w, `{"archived_snapshots":{"closest":{"available":true,"url":"http://web.archive.org/web/2020/%s","timestamp":"20200101000000","status":"200"}}}`, r.URL.Query().Get("url"))
+			return
+		}
+		fmt.Fprint(w, `{"archived_snapshots":{}}`)
+	}))
+	defer archive.Close()
+	checker := NewChecker(1, time.Second, 0, nil, WithArchiveLookup(archive.URL))
+	results, err := checker.Check(context.Background(), []string{target.URL + "/ok", target.URL + "/archived", target.URL + "/gone"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].ArchiveURL != "" {
+		t.Fatalf("ok urls should not get an archive link: %+v", results[0])
+	}
+	if want := "http://web.archive.org/web/2020/" + target.URL + "/archived"; results[1].ArchiveURL != want {
+		t.Fatalf("got archive %q, want %q", results[1].ArchiveURL, want)
+	}
+	if results[1].OK || results[1].Status != http.StatusNotFound {
+		t.Fatalf("archive lookup should not change the outcome: %+v", results[1])
+	}
+	if results[2].ArchiveURL != "" {
+		t.Fatalf("unexpected archive link: %+v", results[2])
+	}
+	if n := lookups.Load(); n != 2 {
+		t.Fatalf("expected 2 archive lookups, got %d", n)
+	}
+}
+
+func TestArchiveLookupFailureIsIgnored(t *testing.T) {
+	archive := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer archive.Close()
+	checker := NewChecker(1, time.Second, 0, nil, WithArchiveLookup(archive.URL))
+	results, err := checker.Check(context.Background(), []string{"http://127.0.0.1:1/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].OK || results[0].ArchiveURL != "" || results[0].Error == "" {
+		t.Fatalf("unexpected result: %+v", results[0])
+	}
+}
+
+func TestArchiveLookupCachedAndThrottledPerRun(t *testing.T) {
+	var lookups atomic.Int32
+	var mu sync.Mutex
+	var at []time.Time
+	archive := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups.Add(1)
+		mu.Lock()
+		at = append(at, time.Now())
+		mu.Unlock()
+		fmt.Fprint(w, `{"archived_snapshots":{}}`)
+	}))
+	defer archive.Close()
+	checker := NewChecker(3, time.Second, 0, nil, WithArchiveLookup(archive.URL))
+	dead := []string{"http://127.0.0.1:1/a", "http://127.0.0.1:1/a", "http://127.0.0.1:1/b"}
+	started := time.Now()
+	if _, err := checker.Check(context.Background(), dead); err != nil {
+		t.Fatal(err)
+	}
+	if n := lookups.Load(); n != 2 {
+		t.Fatalf("expected one lookup per distinct url, got %d", n)
+	}
+	if at[1].Sub(started) < archiveInterval {
+		t.Fatalf("archive lookups were not spaced out: %v", at)
+	}
+	if _, err := checker.Check(context.Background(), dead[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if n := lookups.Load(); n != 3 {
+		t.Fatalf("the archive cache should not outlive a run, got %d lookups", n)
+	}
+}